@@ -0,0 +1,10 @@
+data "clickhousedbops_role_graph" "all" {}
+
+resource "local_file" "role_graph" {
+  filename = "${path.module}/roles.dot"
+  content  = data.clickhousedbops_role_graph.all.dot
+}
+
+output "roles_granted_to_alice" {
+  value = [for e in data.clickhousedbops_role_graph.all.edges : e.role_name if e.grantee_user_name == "alice"]
+}
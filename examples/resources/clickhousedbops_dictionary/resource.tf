@@ -0,0 +1,89 @@
+# Load the countries table of the same server, reloading it every 5 to 10 minutes.
+resource "clickhousedbops_dictionary" "countries" {
+  database_name = clickhousedbops_database.logs.name
+  name          = "countries_dict"
+  lifetime_min  = 300
+  lifetime_max  = 600
+
+  key {
+    name = "id"
+    type = "UInt64"
+  }
+
+  attribute {
+    name    = "name"
+    type    = "String"
+    default = "'unknown'"
+  }
+
+  attribute {
+    name         = "parent_id"
+    type         = "UInt64"
+    hierarchical = true
+  }
+
+  source {
+    type = "CLICKHOUSE"
+    parameters = {
+      db    = "logs"
+      table = "countries"
+    }
+    secret_parameters_wo = {
+      user     = "dictionary_reader"
+      password = var.dictionary_reader_password
+    }
+    secret_parameters_wo_version = 1
+  }
+
+  layout {
+    type = "HASHED"
+  }
+}
+
+# Prices valid over a range of dates, read from PostgreSQL.
+resource "clickhousedbops_dictionary" "prices" {
+  database_name = clickhousedbops_database.logs.name
+  name          = "prices_dict"
+  lifetime_min  = 3600
+  range_min     = "valid_from"
+  range_max     = "valid_to"
+
+  key {
+    name = "product_id"
+    type = "UInt64"
+  }
+
+  attribute {
+    name = "valid_from"
+    type = "Date"
+  }
+
+  attribute {
+    name = "valid_to"
+    type = "Date"
+  }
+
+  attribute {
+    name = "price"
+    type = "Float64"
+  }
+
+  source {
+    type = "POSTGRESQL"
+    parameters = {
+      host  = "postgres.internal"
+      port  = "5432"
+      db    = "shop"
+      table = "prices"
+    }
+    secret_parameters_wo = {
+      user     = "clickhouse"
+      password = var.postgres_password
+    }
+    secret_parameters_wo_version = 1
+  }
+
+  layout {
+    type = "RANGE_HASHED"
+  }
+}
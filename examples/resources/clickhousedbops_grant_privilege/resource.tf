@@ -40,3 +40,17 @@ resource "clickhousedbops_grant_privilege" "team_user_admin" {
   grantee_role_name = "team_provisioner"
   grant_option      = true
 }
+
+# Privileges on named collections and table engines target them with
+# named_collection and table_engine instead of database_name/table_name.
+resource "clickhousedbops_grant_privilege" "s3_collections" {
+  privilege_name    = "NAMED COLLECTION"
+  named_collection  = "s3_*"
+  grantee_role_name = "etl"
+}
+
+resource "clickhousedbops_grant_privilege" "mysql_engine" {
+  privilege_name    = "TABLE ENGINE"
+  table_engine      = "MySQL"
+  grantee_role_name = "etl"
+}
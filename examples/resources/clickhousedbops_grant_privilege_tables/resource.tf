@@ -0,0 +1,13 @@
+resource "clickhousedbops_grant_privilege_tables" "select_events" {
+  privilege_name    = "SELECT"
+  database_name     = "analytics"
+  table_glob        = "events_*"
+  grantee_role_name = clickhousedbops_role.analysts.name
+}
+
+resource "clickhousedbops_grant_privilege_tables" "insert_monthly_events" {
+  privilege_name    = "INSERT"
+  database_name     = "analytics"
+  table_regex       = "events_[0-9]{4}_[0-9]{2}"
+  grantee_user_name = clickhousedbops_user.ingest.name
+}
@@ -0,0 +1,34 @@
+# Aggregate each insert into logs.events into an existing table.
+resource "clickhousedbops_materialized_view" "events_per_tenant" {
+  database_name = clickhousedbops_database.logs.name
+  name          = "events_per_tenant_mv"
+  to_table_name = clickhousedbops_table.events_per_tenant.name
+  sql_security  = "DEFINER"
+  definer       = "CURRENT_USER"
+
+  query = <<-EOT
+    SELECT tenant_id, count() AS events
+    FROM logs.events
+    GROUP BY tenant_id
+  EOT
+}
+
+# Rebuild a daily report every day at 2am, after the view it reads from is refreshed.
+resource "clickhousedbops_materialized_view" "daily_report" {
+  database_name = clickhousedbops_database.logs.name
+  name          = "daily_report"
+  engine        = "MergeTree"
+  order_by      = "tenant_id"
+
+  refresh {
+    every      = "1 DAY"
+    offset     = "2 HOUR"
+    depends_on = ["logs.daily_rollup"]
+  }
+
+  query = <<-EOT
+    SELECT tenant_id, sum(events) AS events
+    FROM logs.daily_rollup
+    GROUP BY tenant_id
+  EOT
+}
@@ -0,0 +1,16 @@
+resource "clickhousedbops_role_members" "analysts" {
+  role_name = "analysts"
+
+  member {
+    user_name = "alice"
+  }
+
+  member {
+    user_name    = "bob"
+    admin_option = true
+  }
+
+  member {
+    role_name = "data_engineers"
+  }
+}
@@ -0,0 +1,37 @@
+resource "clickhousedbops_table" "events" {
+  database_name = clickhousedbops_database.logs.name
+  name          = "events"
+  engine        = "MergeTree"
+  order_by      = "(tenant_id, ts)"
+  partition_by  = "toYYYYMM(ts)"
+  ttl           = "ts + INTERVAL 90 DAY DELETE"
+  comment       = "Application events"
+
+  settings = {
+    index_granularity = "8192"
+  }
+
+  column {
+    name = "tenant_id"
+    type = "UInt32"
+  }
+
+  column {
+    name    = "ts"
+    type    = "DateTime64(3)"
+    default = "now64(3)"
+    codec   = "Delta, ZSTD(3)"
+  }
+
+  column {
+    name = "payload"
+    type = "String"
+  }
+
+  column {
+    name         = "size"
+    type         = "UInt64"
+    default_kind = "MATERIALIZED"
+    default      = "length(payload)"
+  }
+}
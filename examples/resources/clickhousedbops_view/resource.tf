@@ -0,0 +1,26 @@
+resource "clickhousedbops_user" "reporter" {
+  name = "reporter"
+}
+
+# Creating a view with another user as definer requires SET DEFINER on that user.
+resource "clickhousedbops_grant_privilege" "set_definer" {
+  privilege_name    = "SET DEFINER"
+  access_object     = clickhousedbops_user.reporter.name
+  grantee_user_name = "terraform"
+}
+
+resource "clickhousedbops_view" "daily_events" {
+  database_name = clickhousedbops_database.logs.name
+  name          = "daily_events"
+  definer       = clickhousedbops_user.reporter.name
+  sql_security  = "DEFINER"
+  comment       = "Events per tenant and day"
+
+  query = <<-EOT
+    SELECT tenant_id, toDate(ts) AS day, count() AS events
+    FROM logs.events
+    GROUP BY tenant_id, day
+  EOT
+
+  depends_on = [clickhousedbops_grant_privilege.set_definer]
+}
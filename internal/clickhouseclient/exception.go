@@ -0,0 +1,85 @@
+package clickhouseclient
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// exceptionCodeHeader is set by ClickHouse on HTTP responses of failed queries.
+const exceptionCodeHeader = "X-ClickHouse-Exception-Code"
+
+// exceptionBodyRegex splits the exception text returned by the HTTP interface, for example
+// `Code: 81. DB::Exception: Database foo does not exist. (UNKNOWN_DATABASE) (version 24.8.1.1)`.
+var exceptionBodyRegex = regexp.MustCompile(`(?s)^Code: (\d+)\. (?:(DB::Exception): )?(.*?)(?: \(([A-Z0-9_]+)\))?(?: \(version [^)]*\))?\s*$`)
+
+// parseException builds the exception carried by a failed HTTP response, so that callers get the
+// same *clickhouse.Exception the native protocol returns. It returns false when the response does
+// not carry a ClickHouse exception, for example when it was sent by a proxy.
+func parseException(header http.Header, body string) (*clickhouse.Exception, bool) {
+	ex := &clickhouse.Exception{Name: "DB::Exception"}
+
+	headerCode, headerErr := strconv.ParseInt(header.Get(exceptionCodeHeader), 10, 32)
+
+	match := exceptionBodyRegex.FindStringSubmatch(strings.TrimSpace(body))
+	switch {
+	case match != nil:
+		code, err := strconv.ParseInt(match[1], 10, 32)
+		if err != nil {
+			return nil, false
+		}
+		ex.Code = int32(code)
+		ex.Message = match[3]
+		ex.CodeName = match[4]
+	case headerErr == nil:
+		ex.Message = strings.TrimSpace(body)
+	default:
+		return nil, false
+	}
+
+	// The header is authoritative when both are present.
+	if headerErr == nil {
+		ex.Code = int32(headerCode)
+	}
+
+	return ex, true
+}
+
+// AsException returns the ClickHouse exception carried by err, if any.
+func AsException(err error) (*clickhouse.Exception, bool) {
+	var ret *clickhouse.Exception
+	found := FindInChain(err, func(e error) bool {
+		ex, ok := e.(*clickhouse.Exception)
+		if ok {
+			ret = ex
+		}
+		return ok
+	})
+
+	return ret, found
+}
+
+// FindInChain reports whether match accepts err or one of the errors it wraps. It follows both
+// Cause() and Unwrap() chains: pingcap/errors wrappers implement only Cause(), so stdlib
+// errors.Is/As cannot traverse them.
+func FindInChain(err error, match func(error) bool) bool {
+	for err != nil {
+		if match(err) {
+			return true
+		}
+
+		switch x := err.(type) {
+		case interface{ Cause() error }:
+			err = x.Cause()
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		default:
+			return false
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+package clickhouseclient
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func Test_parseException(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		want   *clickhouse.Exception
+	}{
+		{
+			name:   "Header and body",
+			header: "497",
+			body:   "Code: 497. DB::Exception: alice: Not enough privileges. To execute this query, it's necessary to have the grant CREATE USER ON *.*. (ACCESS_DENIED) (version 24.8.1.1)\n",
+			want: &clickhouse.Exception{
+				Code:     497,
+				Name:     "DB::Exception",
+				CodeName: "ACCESS_DENIED",
+				Message:  "alice: Not enough privileges. To execute this query, it's necessary to have the grant CREATE USER ON *.*.",
+			},
+		},
+		{
+			name: "Body only",
+			body: "Code: 81. DB::Exception: Database `foo` does not exist. (UNKNOWN_DATABASE)",
+			want: &clickhouse.Exception{
+				Code:     81,
+				Name:     "DB::Exception",
+				CodeName: "UNKNOWN_DATABASE",
+				Message:  "Database `foo` does not exist.",
+			},
+		},
+		{
+			name:   "Header only",
+			header: "999",
+			body:   "Coordination error: Connection loss\n",
+			want: &clickhouse.Exception{
+				Code:    999,
+				Name:    "DB::Exception",
+				Message: "Coordination error: Connection loss",
+			},
+		},
+		{
+			name: "Not an exception",
+			body: "<html>502 Bad Gateway</html>",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set(exceptionCodeHeader, tt.header)
+			}
+
+			got, ok := parseException(header, tt.body)
+			if ok != (tt.want != nil) {
+				t.Fatalf("parseException() ok = %v, want %v", ok, tt.want != nil)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseException() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package clickhouseclient
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/pingcap/errors"
+)
+
+// ConnOpenStrategy controls the order in which the configured hosts are tried when opening a connection.
+type ConnOpenStrategy string
+
+const (
+	ConnOpenInOrder    ConnOpenStrategy = "in_order"
+	ConnOpenRoundRobin ConnOpenStrategy = "round_robin"
+	ConnOpenRandom     ConnOpenStrategy = "random"
+)
+
+// AvailableConnOpenStrategies lists the valid ConnOpenStrategy values.
+var AvailableConnOpenStrategies = []string{string(ConnOpenInOrder), string(ConnOpenRoundRobin), string(ConnOpenRandom)}
+
+// toNative maps the strategy to the equivalent clickhouse-go option. Unset means in order.
+func (s ConnOpenStrategy) toNative() (clickhouse.ConnOpenStrategy, error) {
+	switch s {
+	case "", ConnOpenInOrder:
+		return clickhouse.ConnOpenInOrder, nil
+	case ConnOpenRoundRobin:
+		return clickhouse.ConnOpenRoundRobin, nil
+	case ConnOpenRandom:
+		return clickhouse.ConnOpenRandom, nil
+	default:
+		return 0, errors.New(fmt.Sprintf("invalid connection open strategy %q", s))
+	}
+}
+
+// order returns the index of the host to try at the given attempt, following the same rules as
+// clickhouse-go's DefaultDialStrategy so that both transports spread load the same way.
+// seed and attempt are in [0, count), so the sum cannot overflow.
+func (s ConnOpenStrategy) order(seed int, attempt int, count int) int {
+	switch s {
+	case ConnOpenRoundRobin, ConnOpenRandom:
+		return (seed + attempt) % count
+	default:
+		return attempt
+	}
+}
+
+// seed returns the starting offset in [0, count) for a new request; counter is the per-client
+// request sequence.
+func (s ConnOpenStrategy) seed(counter uint64, count int) int {
+	switch s {
+	case ConnOpenRoundRobin:
+		return int(counter % uint64(count))
+	case ConnOpenRandom:
+		return rand.IntN(count) //nolint:gosec
+	default:
+		return 0
+	}
+}
+
+// addresses returns the `host:port` list for the primary host followed by the additional hosts.
+// Additional hosts may either be a bare hostname, which uses defaultPort, or a `host:port` pair.
+func addresses(host string, defaultPort uint16, additionalHosts []string) ([]string, error) {
+	ret := []string{net.JoinHostPort(host, strconv.Itoa(int(defaultPort)))}
+
+	for _, h := range additionalHosts {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			return nil, errors.New("additional hosts cannot be empty")
+		}
+
+		hostname, port, err := net.SplitHostPort(h)
+		if err != nil {
+			// No port specified, use the default one.
+			ret = append(ret, net.JoinHostPort(strings.Trim(h, "[]"), strconv.Itoa(int(defaultPort))))
+			continue
+		}
+
+		portNum, err := strconv.ParseUint(port, 10, 16)
+		if err != nil || portNum == 0 {
+			return nil, errors.New(fmt.Sprintf("invalid port in host %q", h))
+		}
+
+		ret = append(ret, net.JoinHostPort(hostname, port))
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,96 @@
+package clickhouseclient
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_addresses(t *testing.T) {
+	tests := []struct {
+		name            string
+		host            string
+		port            uint16
+		additionalHosts []string
+		want            []string
+		wantErr         bool
+	}{
+		{
+			name: "Single host",
+			host: "localhost",
+			port: 9000,
+			want: []string{"localhost:9000"},
+		},
+		{
+			name:            "Additional hosts with and without port",
+			host:            "replica1",
+			port:            9000,
+			additionalHosts: []string{"replica2", "replica3:9440"},
+			want:            []string{"replica1:9000", "replica2:9000", "replica3:9440"},
+		},
+		{
+			name:            "IPv6 additional hosts",
+			host:            "::1",
+			port:            8123,
+			additionalHosts: []string{"[::2]", "[::3]:8443"},
+			want:            []string{"[::1]:8123", "[::2]:8123", "[::3]:8443"},
+		},
+		{
+			name:            "Invalid port",
+			host:            "replica1",
+			port:            9000,
+			additionalHosts: []string{"replica2:http"},
+			wantErr:         true,
+		},
+		{
+			name:            "Empty additional host",
+			host:            "replica1",
+			port:            9000,
+			additionalHosts: []string{" "},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := addresses(tt.host, tt.port, tt.additionalHosts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("addresses() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("addresses() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnOpenStrategy_order(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy ConnOpenStrategy
+		counter  uint64
+	}{
+		{name: "In order", strategy: ConnOpenInOrder, counter: 7},
+		{name: "Round robin", strategy: ConnOpenRoundRobin, counter: 7},
+		{name: "Round robin at the end of the counter", strategy: ConnOpenRoundRobin, counter: math.MaxUint64},
+		{name: "Random", strategy: ConnOpenRandom},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for count := 1; count <= 4; count++ {
+				seed := tt.strategy.seed(tt.counter, count)
+				seen := make(map[int]bool)
+				for attempt := range count {
+					idx := tt.strategy.order(seed, attempt, count)
+					if idx < 0 || idx >= count {
+						t.Fatalf("order() = %d, want an index in [0, %d)", idx, count)
+					}
+					seen[idx] = true
+				}
+				if len(seen) != count {
+					t.Errorf("order() tried %d of the %d hosts", len(seen), count)
+				}
+			}
+		})
+	}
+}
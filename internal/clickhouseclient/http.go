@@ -1,31 +1,67 @@
 package clickhouseclient
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
-	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/pingcap/errors"
 )
 
+const (
+	// killQueryTimeout bounds the KILL QUERY statement sent after a query was abandoned.
+	killQueryTimeout = 10 * time.Second
+	// maxErrorBodySize bounds how much of a failed response is read.
+	maxErrorBodySize = 1024 * 1024
+	// defaultIdleConnTimeout matches http.DefaultTransport so pooled connections are eventually released.
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
 type httpClient struct {
-	client  *http.Client
-	baseUrl url.URL
+	client           *http.Client
+	baseUrls         []url.URL
+	connOpenStrategy ConnOpenStrategy
+	requestCounter   atomic.Uint64
+	queryTimeout     time.Duration
+	headers          http.Header
+	settings         map[string]string
 }
 
 type HTTPClientConfig struct {
-	Protocol  string
-	Host      string
-	Port      uint16
-	BasicAuth *BasicAuth
-	TLSConfig *tls.Config
+	Protocol         string
+	Host             string
+	Port             uint16
+	AdditionalHosts  []string
+	ConnOpenStrategy ConnOpenStrategy
+	BasicAuth        *BasicAuth
+	TLSConfig        *tls.Config
+	// QueryTimeout is the maximum duration of a single query, 0 means no limit.
+	QueryTimeout time.Duration
+	// MaxIdleConns is the maximum number of idle connections kept per host, 0 means the net/http default.
+	MaxIdleConns int
+	// MaxOpenConns is the maximum number of connections per host, 0 means no limit.
+	MaxOpenConns int
+	// Path is the URL path ClickHouse is served on, e.g. when behind a gateway. Defaults to "/".
+	Path string
+	// Headers are additional HTTP headers sent with every request.
+	Headers map[string]string
+	// ProxyURL is the HTTP(S) or SOCKS5 proxy to send requests through.
+	ProxyURL *url.URL
+	// CredentialsInHeaders sends the credentials in the X-ClickHouse-User and X-ClickHouse-Key
+	// headers instead of the URL userinfo.
+	CredentialsInHeaders bool
+	// Settings are the ClickHouse settings applied to every query, see WithSettings for overrides.
+	Settings map[string]string
 }
 
 func NewHTTPClient(config HTTPClientConfig) (ClickhouseClient, error) {
@@ -43,68 +79,135 @@ func NewHTTPClient(config HTTPClientConfig) (ClickhouseClient, error) {
 		protocol = config.Protocol
 	}
 
-	urlStr := fmt.Sprintf("%s://%s", protocol, config.Host)
-
-	if config.Port != 0 {
-		urlStr = fmt.Sprintf("%s:%d", urlStr, config.Port)
+	if _, err := config.ConnOpenStrategy.toNative(); err != nil {
+		return nil, err
 	}
 
-	baseUrl, err := url.Parse(urlStr)
+	addrs, err := addresses(config.Host, config.Port, config.AdditionalHosts)
 	if err != nil {
-		return nil, errors.WithMessage(err, "cannot parse URL")
+		return nil, err
 	}
 
-	baseUrl.Path = "/"
+	path := "/"
+	if config.Path != "" {
+		path = "/" + strings.TrimPrefix(config.Path, "/")
+	}
+
+	headers := http.Header{}
+	for k, v := range config.Headers {
+		headers.Set(k, v)
+	}
 
-	if config.BasicAuth != nil {
-		if config.BasicAuth.Password == "" {
-			baseUrl.User = url.User(config.BasicAuth.Username)
-		} else {
-			baseUrl.User = url.UserPassword(config.BasicAuth.Username, config.BasicAuth.Password)
+	if config.CredentialsInHeaders {
+		headers.Set("X-ClickHouse-User", config.BasicAuth.Username)
+		if config.BasicAuth.Password != "" {
+			headers.Set("X-ClickHouse-Key", config.BasicAuth.Password)
+		}
+	}
+
+	baseUrls := make([]url.URL, 0, len(addrs))
+	for _, addr := range addrs {
+		baseUrl, err := url.Parse(fmt.Sprintf("%s://%s", protocol, addr))
+		if err != nil {
+			return nil, errors.WithMessage(err, "cannot parse URL")
+		}
+
+		baseUrl.Path = path
+
+		if config.BasicAuth != nil && !config.CredentialsInHeaders {
+			if config.BasicAuth.Password == "" {
+				baseUrl.User = url.User(config.BasicAuth.Username)
+			} else {
+				baseUrl.User = url.UserPassword(config.BasicAuth.Username, config.BasicAuth.Password)
+			}
+		}
+
+		baseUrls = append(baseUrls, *baseUrl)
+	}
+
+	transport := &http.Transport{
+		TLSClientConfig:     config.TLSConfig,
+		MaxIdleConnsPerHost: config.MaxIdleConns,
+		MaxConnsPerHost:     config.MaxOpenConns,
+		IdleConnTimeout:     defaultIdleConnTimeout,
+	}
+
+	if config.ProxyURL != nil {
+		switch config.ProxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+			transport.Proxy = http.ProxyURL(config.ProxyURL)
+		default:
+			return nil, errors.New(fmt.Sprintf("unsupported proxy scheme %q", config.ProxyURL.Scheme))
 		}
 	}
 
 	return &httpClient{
-		baseUrl: *baseUrl,
+		baseUrls:         baseUrls,
+		connOpenStrategy: config.ConnOpenStrategy,
+		queryTimeout:     config.QueryTimeout,
+		headers:          headers,
+		settings:         config.Settings,
 		client: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: config.TLSConfig,
-			},
+			Transport: transport,
 		},
 	}, nil
 }
 
-func (i *httpClient) Select(ctx context.Context, qry string, callback func(Row) error) error {
-	body, err := i.runQuery(ctx, qry, nil)
-	if err != nil {
-		return errors.WithMessage(err, "error running query")
+func (i *httpClient) Select(ctx context.Context, qry string, callback func(Row) error, params ...map[string]string) error {
+	var queryParams map[string]string
+	if len(params) > 0 {
+		queryParams = params[0]
 	}
 
-	parsed := jsonCompatStrings{}
+	err := i.runQuery(ctx, qry, queryParams, func(ctx context.Context, body io.Reader) error {
+		decoder := newRowDecoder(body)
 
-	err = json.Unmarshal([]byte(body), &parsed)
-	if err != nil {
-		return errors.WithMessage(err, "error parsing response")
-	}
+		count := 0
+		for {
+			row, ok, err := decoder.Next()
+			if err != nil {
+				return errors.WithMessage(err, "error parsing response")
+			}
+			if !ok {
+				break
+			}
+			count++
 
-	for _, row := range parsed.Rows() {
-		err = callback(row)
-		if err != nil {
-			return errors.WithMessage(err, "error calling callback function")
+			tflog.Trace(ctx, "Query result row", map[string]any{"row": row.data})
+
+			err = callback(row)
+			if err != nil {
+				return errors.WithMessage(err, "error calling callback function")
+			}
 		}
+
+		tflog.Debug(ctx, "Run Query", map[string]any{"Rows": count})
+
+		return nil
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
 	}
 
 	return nil
 }
 
 func (i *httpClient) Exec(ctx context.Context, qry string, params ...map[string]string) error {
-	var err error
-
+	var queryParams map[string]string
 	if len(params) > 0 {
-		_, err = i.runQuery(ctx, qry, params[0])
-	} else {
-		_, err = i.runQuery(ctx, qry, nil)
+		queryParams = params[0]
 	}
+
+	err := i.runQuery(ctx, qry, queryParams, func(ctx context.Context, body io.Reader) error {
+		result, err := io.ReadAll(body)
+		if err != nil {
+			return errors.WithMessage(err, "error reading response")
+		}
+
+		tflog.Debug(ctx, "Run Query", map[string]any{"QueryResult": string(result)})
+
+		return nil
+	})
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
@@ -112,58 +215,157 @@ func (i *httpClient) Exec(ctx context.Context, qry string, params ...map[string]
 	return nil
 }
 
-func (i *httpClient) runQuery(ctx context.Context, qry string, params map[string]string) (string, error) {
-	ctx = tflog.SetField(ctx, "Query", qry)
+// do sends the query to the configured hosts following the connection open strategy.
+// The next host is only tried when a connection to the current one cannot be established,
+// so a statement is never sent to the server more than once.
+// The returned URL is the host the request may have reached, nil if no connection could be established.
+func (i *httpClient) do(ctx context.Context, queryID string, qry string, params map[string]string) (*http.Response, *url.URL, error) {
+	seed := i.connOpenStrategy.seed(i.requestCounter.Add(1), len(i.baseUrls))
+	settings := querySettings(ctx, i.settings)
 
-	reqURL := i.baseUrl
-	if len(params) > 0 {
+	var err error
+	for attempt := range i.baseUrls {
+		baseUrl := i.baseUrls[i.connOpenStrategy.order(seed, attempt, len(i.baseUrls))]
+
+		reqURL := baseUrl
 		q := reqURL.Query()
+		for k, v := range settings {
+			q.Set(k, v)
+		}
+		q.Set("date_time_output_format", dateTimeOutputFormat)
+		q.Set("query_id", queryID)
 		for k, v := range params {
 			q.Set("param_"+k, v)
 		}
 		reqURL.RawQuery = q.Encode()
-	}
 
-	req, err := http.NewRequest(http.MethodPost, reqURL.String(), strings.NewReader(qry))
-	if err != nil {
-		return "", errors.WithMessage(err, "error preparing HTTP request")
-	}
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), strings.NewReader(qry))
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, "error preparing HTTP request")
+		}
 
-	req.Header.Add("X-ClickHouse-Format", "JSONCompactStrings")
+		req.Header = i.headers.Clone()
+		req.Header.Add("X-ClickHouse-Format", responseFormat)
+
+		var resp *http.Response
+		resp, err = i.client.Do(req)
+		if err == nil {
+			return resp, &baseUrl, nil
+		}
 
-	resp, err := i.client.Do(req)
-	if err != nil {
 		// Do's *url.Error embeds the request URL; drop the query string so param_ secrets don't leak.
 		if uerr, ok := err.(*url.Error); ok {
 			u := *req.URL
 			u.RawQuery = ""
 			uerr.URL = u.Redacted()
 		}
-		return "", errors.WithMessage(err, "error executing query")
+
+		if !isDialError(err) {
+			return nil, &baseUrl, err
+		}
+
+		tflog.Debug(ctx, "Unable to connect to host, trying next one", map[string]any{"host": reqURL.Host, "error": err.Error()})
+	}
+
+	return nil, nil, err
+}
+
+// killQuery stops a query whose request was abandoned because ctx was cancelled or timed out.
+// ClickHouse keeps running non read-only queries after the HTTP client disconnects, so without
+// this a DDL statement could still be applied after Terraform gave up on it.
+func (i *httpClient) killQuery(ctx context.Context, baseUrl url.URL, queryID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), killQueryTimeout)
+	defer cancel()
+
+	// queryID is a UUID generated by runQuery, so it is safe to interpolate.
+	qry := fmt.Sprintf("KILL QUERY WHERE query_id = '%s' ASYNC", queryID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseUrl.String(), strings.NewReader(qry))
+	if err == nil {
+		req.Header = i.headers.Clone()
+
+		var resp *http.Response
+		resp, err = i.client.Do(req)
+		if err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				err = errors.New(fmt.Sprintf("unexpected status code %d", resp.StatusCode))
+			}
+		}
 	}
 
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", errors.WithMessage(err, "error reading response")
+		tflog.Warn(ctx, "Unable to kill abandoned query", map[string]any{"error": err.Error()})
+		return
 	}
 
-	var buf bytes.Buffer
-	err = json.Indent(&buf, body, "", "  ")
-	if err == nil {
-		// Best effort, if we fail parsing we leave the body as-is, otherwise we use the formatted version.
-		body = buf.Bytes()
+	tflog.Debug(ctx, "Killed abandoned query")
+}
+
+// httpStatusError is returned when the server answers with a status other than 200 OK.
+type httpStatusError struct {
+	statusCode int
+	body       string
+}
+
+func (e *httpStatusError) Error() string {
+	return e.body
+}
+
+// isDialError reports whether err happened while establishing the connection, before any data was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return stderrors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// runQuery sends the query and passes the body of a successful response to consume, which is
+// expected to read it incrementally. Failed responses are returned as errors.
+func (i *httpClient) runQuery(ctx context.Context, qry string, params map[string]string, consume func(context.Context, io.Reader) error) error {
+	queryID := uuid.NewString()
+	ctx = tflog.SetField(ctx, "Query", qry)
+	ctx = tflog.SetField(ctx, "QueryID", queryID)
+
+	if i.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.queryTimeout)
+		defer cancel()
 	}
 
-	ctx = tflog.SetField(ctx, "QueryResult", string(body))
+	resp, baseUrl, err := i.do(ctx, queryID, qry, params)
+	if err != nil {
+		if ctx.Err() != nil && baseUrl != nil {
+			i.killQuery(ctx, *baseUrl, queryID)
+		}
+		return errors.WithMessage(err, "error executing query")
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", errors.New(string(body))
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if err != nil {
+			return errors.WithMessage(err, "error reading response")
+		}
+
+		tflog.Debug(ctx, "Query failed", map[string]any{"QueryResult": string(body)})
+
+		if ex, ok := parseException(resp.Header, string(body)); ok {
+			return errors.WithStack(ex)
+		}
+		return errors.WithStack(&httpStatusError{statusCode: resp.StatusCode, body: string(body)})
 	}
 
-	tflog.Debug(ctx, "Run Query")
+	err = consume(ctx, resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			i.killQuery(ctx, *baseUrl, queryID)
+		}
+		return err
+	}
 
-	return string(body), nil
+	return nil
 }
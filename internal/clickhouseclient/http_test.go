@@ -2,8 +2,14 @@ package clickhouseclient
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func Test_httpClient_execErrorDoesNotLeakParams(t *testing.T) {
@@ -24,3 +30,350 @@ func Test_httpClient_execErrorDoesNotLeakParams(t *testing.T) {
 		t.Errorf("error message leaks query parameter value: %v", err)
 	}
 }
+
+func Test_httpClient_failoverToNextHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[\"name\"]\n[\"String\"]\n[\"default\"]\n"))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	client, err := NewHTTPClient(HTTPClientConfig{
+		Host:            "127.0.0.1",
+		Port:            1,
+		AdditionalHosts: []string{serverURL.Host},
+		BasicAuth:       &BasicAuth{Username: "default"},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	names := make([]string, 0)
+	err = client.Select(context.Background(), "SELECT name FROM system.users;", func(row Row) error {
+		name, err := row.GetString("name")
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(names) != 1 || names[0] != "default" {
+		t.Errorf("Select() got = %v, want [default]", names)
+	}
+}
+
+func Test_httpClient_timeoutKillsQuery(t *testing.T) {
+	queryIDs := make(chan string, 1)
+	killed := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(string(body), "KILL QUERY") {
+			killed <- string(body)
+			return
+		}
+
+		// Simulate a hung DDL.
+		queryIDs <- r.URL.Query().Get("query_id")
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	port, err := strconv.ParseUint(serverURL.Port(), 10, 16)
+	if err != nil {
+		t.Fatalf("strconv.ParseUint() error = %v", err)
+	}
+
+	client, err := NewHTTPClient(HTTPClientConfig{
+		Host:         serverURL.Hostname(),
+		Port:         uint16(port),
+		BasicAuth:    &BasicAuth{Username: "default"},
+		QueryTimeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	err = client.Exec(context.Background(), "CREATE DATABASE `db` ON CLUSTER 'cluster1';")
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+
+	select {
+	case qry := <-killed:
+		queryID := <-queryIDs
+		if queryID == "" {
+			t.Fatal("query was sent without a query_id")
+		}
+		if !strings.Contains(qry, queryID) {
+			t.Errorf("KILL QUERY = %q, want query_id %q", qry, queryID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("query was not killed after timeout")
+	}
+}
+
+func Test_httpClient_pathHeadersAndCredentials(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	port, err := strconv.ParseUint(serverURL.Port(), 10, 16)
+	if err != nil {
+		t.Fatalf("strconv.ParseUint() error = %v", err)
+	}
+
+	client, err := NewHTTPClient(HTTPClientConfig{
+		Host:                 serverURL.Hostname(),
+		Port:                 uint16(port),
+		BasicAuth:            &BasicAuth{Username: "admin", Password: "secret"},
+		Path:                 "gateway/clickhouse",
+		Headers:              map[string]string{"X-Gateway-Token": "token"},
+		CredentialsInHeaders: true,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	err = client.Exec(context.Background(), "CREATE ROLE `r`;")
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	r := <-requests
+	if r.URL.Path != "/gateway/clickhouse" {
+		t.Errorf("path = %q, want %q", r.URL.Path, "/gateway/clickhouse")
+	}
+	if got := r.Header.Get("X-Gateway-Token"); got != "token" {
+		t.Errorf("X-Gateway-Token = %q, want %q", got, "token")
+	}
+	if got := r.Header.Get("X-ClickHouse-User"); got != "admin" {
+		t.Errorf("X-ClickHouse-User = %q, want %q", got, "admin")
+	}
+	if got := r.Header.Get("X-ClickHouse-Key"); got != "secret" {
+		t.Errorf("X-ClickHouse-Key = %q, want %q", got, "secret")
+	}
+	if _, _, ok := r.BasicAuth(); ok {
+		t.Error("credentials were sent in the URL userinfo")
+	}
+}
+
+func Test_httpClient_proxy(t *testing.T) {
+	hosts := make(chan string, 1)
+
+	// Plain HTTP proxies receive the absolute target URL.
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts <- r.URL.Host
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	client, err := NewHTTPClient(HTTPClientConfig{
+		Host:      "clickhouse.internal",
+		Port:      8123,
+		BasicAuth: &BasicAuth{Username: "default"},
+		ProxyURL:  proxyURL,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	err = client.Exec(context.Background(), "CREATE ROLE `r`;")
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	if got := <-hosts; got != "clickhouse.internal:8123" {
+		t.Errorf("proxied host = %q, want %q", got, "clickhouse.internal:8123")
+	}
+}
+
+func Test_httpClient_settings(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	port, err := strconv.ParseUint(serverURL.Port(), 10, 16)
+	if err != nil {
+		t.Fatalf("strconv.ParseUint() error = %v", err)
+	}
+
+	client, err := NewHTTPClient(HTTPClientConfig{
+		Host:      serverURL.Hostname(),
+		Port:      uint16(port),
+		BasicAuth: &BasicAuth{Username: "default"},
+		Settings:  map[string]string{"distributed_ddl_task_timeout": "180", "max_execution_time": "60"},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	ctx := WithSettings(context.Background(), map[string]string{"max_execution_time": "10", "query_id": "override"})
+	err = client.Exec(ctx, "CREATE ROLE `r`;")
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	q := (<-requests).URL.Query()
+	if got := q.Get("distributed_ddl_task_timeout"); got != "180" {
+		t.Errorf("distributed_ddl_task_timeout = %q, want %q", got, "180")
+	}
+	if got := q.Get("max_execution_time"); got != "10" {
+		t.Errorf("max_execution_time = %q, want %q", got, "10")
+	}
+	if got := q.Get("query_id"); got == "override" {
+		t.Error("settings overrode the query_id")
+	}
+}
+
+func Test_httpClient_exception(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-ClickHouse-Exception-Code", "511")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("Code: 511. DB::Exception: There is no role `reader` in user directories. (UNKNOWN_ROLE) (version 24.8.1.1)\n"))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	port, err := strconv.ParseUint(serverURL.Port(), 10, 16)
+	if err != nil {
+		t.Fatalf("strconv.ParseUint() error = %v", err)
+	}
+
+	client, err := NewHTTPClient(HTTPClientConfig{
+		Host:      serverURL.Hostname(),
+		Port:      uint16(port),
+		BasicAuth: &BasicAuth{Username: "default"},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	err = client.Exec(context.Background(), "GRANT `reader` TO `alice`;")
+	ex, ok := AsException(err)
+	if !ok {
+		t.Fatalf("Exec() error = %v, want a ClickHouse exception", err)
+	}
+	if ex.Code != 511 || ex.CodeName != "UNKNOWN_ROLE" {
+		t.Errorf("exception = %+v, want code 511 (UNKNOWN_ROLE)", ex)
+	}
+}
+
+func Test_httpClient_streamedException(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-ClickHouse-Format"); got != responseFormat {
+			t.Errorf("X-ClickHouse-Format = %q, want %q", got, responseFormat)
+		}
+
+		// The status is sent with the first rows, before the query fails.
+		_, _ = w.Write([]byte("[\"name\"]\n[\"String\"]\n[\"default\"]\n"))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte("Code: 241. DB::Exception: Memory limit (total) exceeded. (MEMORY_LIMIT_EXCEEDED) (version 24.8.1.1)\n"))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	port, err := strconv.ParseUint(serverURL.Port(), 10, 16)
+	if err != nil {
+		t.Fatalf("strconv.ParseUint() error = %v", err)
+	}
+
+	client, err := NewHTTPClient(HTTPClientConfig{
+		Host:      serverURL.Hostname(),
+		Port:      uint16(port),
+		BasicAuth: &BasicAuth{Username: "default"},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	rows := 0
+	err = client.Select(context.Background(), "SELECT name FROM system.users;", func(row Row) error {
+		rows++
+		return nil
+	})
+	if rows != 1 {
+		t.Errorf("Select() returned %d rows, want 1", rows)
+	}
+	ex, ok := AsException(err)
+	if !ok {
+		t.Fatalf("Select() error = %v, want a ClickHouse exception", err)
+	}
+	if ex.Code != 241 {
+		t.Errorf("exception = %+v, want code 241", ex)
+	}
+}
+
+func Test_httpClient_selectParameters(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+		_, _ = w.Write([]byte("[\"name\"]\n[\"String\"]\n"))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	port, err := strconv.ParseUint(serverURL.Port(), 10, 16)
+	if err != nil {
+		t.Fatalf("strconv.ParseUint() error = %v", err)
+	}
+
+	client, err := NewHTTPClient(HTTPClientConfig{
+		Host:      serverURL.Hostname(),
+		Port:      uint16(port),
+		BasicAuth: &BasicAuth{Username: "default"},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	err = client.Select(context.Background(), "SELECT `name` FROM `system`.`users` WHERE (`name` = {value_0:String});", func(Row) error {
+		return nil
+	}, map[string]string{"value_0": "o'brien"})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+
+	if got := (<-requests).URL.Query().Get("param_value_0"); got != "o'brien" {
+		t.Errorf("param_value_0 = %q, want %q", got, "o'brien")
+	}
+}
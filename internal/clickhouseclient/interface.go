@@ -5,7 +5,8 @@ import (
 )
 
 type ClickhouseClient interface {
-	Select(ctx context.Context, qry string, callback func(Row) error) error
+	// Select runs a query and calls callback for each returned row, only the first params entry passed to the server.
+	Select(ctx context.Context, qry string, callback func(Row) error, params ...map[string]string) error
 	// Exec runs a query, only the first params entry passed to the server.
 	Exec(ctx context.Context, qry string, params ...map[string]string) error
 }
@@ -1,69 +1,371 @@
 package clickhouseclient
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
 )
 
-const nullString = "ᴺᵁᴸᴸ"
-
-// jsonCompatStrings is used to parse clickhouse query output when using 'jsonCompatStrings' format.
-type jsonCompatStrings struct {
-	Meta []struct {
-		Name string
-		Type string
-	} `json:"meta"`
-	Data [][]string `json:"data"`
-}
-
-func (j jsonCompatStrings) Rows() []Row {
-	ret := make([]Row, 0)
-
-	// Extract slice of column names and column data types in the result.
-	colNames := make([]string, 0)
-	colTypes := make([]string, 0)
-	for _, entry := range j.Meta {
-		colNames = append(colNames, entry.Name)
-		colTypes = append(colTypes, entry.Type)
-	}
-
-	// Create a slice of Rows associating the value to each column name.
-	for _, row := range j.Data {
-		data := Row{}
-
-		for i, field := range row {
-			switch colTypes[i] {
-			case "String":
-				data.Set(colNames[i], field)
-			case "Nullable(String)":
-				if field == nullString {
-					data.Set(colNames[i], (*string)(nil))
-				} else {
-					data.Set(colNames[i], &field)
-				}
-			case "UInt8":
-				val, err := strconv.ParseUint(field, 10, 8)
-				if err != nil {
-					// Failed parsing as number, return value as-is.
-					data.Set(colNames[i], field)
-				} else {
-					data.Set(colNames[i], uint8(val))
-				}
-			case "UInt64":
-				val, err := strconv.ParseUint(field, 10, 64)
-				if err != nil {
-					// Failed parsing as number, return value as-is.
-					data.Set(colNames[i], field)
-				} else {
-					data.Set(colNames[i], val)
-				}
+// responseFormat is the format of the results of HTTP queries: one JSON array of strings per line,
+// preceded by a line with the column names and a line with the column types. Unlike a single JSON
+// document, it can be decoded one row at a time.
+const responseFormat = "JSONCompactStringsEachRowWithNamesAndTypes"
+
+// dateTimeOutputFormat is requested on every HTTP query so that DateTime values carry their time zone.
+const dateTimeOutputFormat = "iso"
+
+// maxTrailingExceptionSize bounds how much of the response is read to find an exception ClickHouse
+// wrote after it started streaming rows.
+const maxTrailingExceptionSize = 64 * 1024
+
+// rowDecoder reads the rows of a response in responseFormat.
+type rowDecoder struct {
+	r       io.Reader
+	dec     *json.Decoder
+	names   []string
+	types   []string
+	started bool
+}
+
+func newRowDecoder(r io.Reader) *rowDecoder {
+	return &rowDecoder{
+		r:   r,
+		dec: json.NewDecoder(r),
+	}
+}
+
+// Next returns the next row of the response, or false when all rows were read.
+func (d *rowDecoder) Next() (Row, bool, error) {
+	if !d.started {
+		d.started = true
+
+		names, ok, err := d.line()
+		if err != nil {
+			return Row{}, false, errors.WithMessage(err, "error reading column names")
+		}
+		if !ok {
+			// Empty response, for example from a statement that does not return rows.
+			return Row{}, false, nil
+		}
+		types, ok, err := d.line()
+		if err != nil {
+			return Row{}, false, errors.WithMessage(err, "error reading column types")
+		}
+		if !ok || len(types) != len(names) {
+			return Row{}, false, errors.New("response is missing the column types")
+		}
+
+		for i := range names {
+			if names[i] == nil || types[i] == nil {
+				return Row{}, false, errors.New("response has a NULL column name or type")
+			}
+			d.names = append(d.names, *names[i])
+			d.types = append(d.types, *types[i])
+		}
+	}
+
+	fields, ok, err := d.line()
+	if err != nil || !ok {
+		return Row{}, false, err
+	}
+
+	if len(fields) != len(d.names) {
+		return Row{}, false, errors.New(fmt.Sprintf("row has %d fields but the result has %d columns", len(fields), len(d.names)))
+	}
+
+	row := Row{}
+	for i, field := range fields {
+		val, err := parseValue(d.types[i], field)
+		if err != nil {
+			return Row{}, false, errors.WithMessage(err, fmt.Sprintf("error parsing column %s", d.names[i]))
+		}
+		row.Set(d.names[i], val)
+	}
+
+	return row, true, nil
+}
+
+// line decodes the next line of the response, returning false at the end of the response.
+func (d *rowDecoder) line() ([]*string, bool, error) {
+	var raw json.RawMessage
+	err := d.dec.Decode(&raw)
+	if err == io.EOF {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, d.streamError(err)
+	}
+
+	// ClickHouse writes an exception object when a query fails after the response was started.
+	if len(raw) > 0 && raw[0] == '{' {
+		var obj struct {
+			Exception string `json:"exception"`
+		}
+		if err := json.Unmarshal(raw, &obj); err == nil && obj.Exception != "" {
+			if ex, ok := parseException(http.Header{}, obj.Exception); ok {
+				return nil, false, errors.WithStack(ex)
+			}
+			return nil, false, errors.New(obj.Exception)
+		}
+	}
+
+	var fields []*string
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, false, errors.WithMessage(err, "error parsing response")
+	}
+
+	return fields, true, nil
+}
+
+// streamError returns the exception ClickHouse appended as plain text to a response it already
+// started streaming, if any, and err otherwise.
+func (d *rowDecoder) streamError(err error) error {
+	rest, _ := io.ReadAll(io.LimitReader(io.MultiReader(d.dec.Buffered(), d.r), maxTrailingExceptionSize))
+	if idx := bytes.Index(rest, []byte("Code: ")); idx >= 0 {
+		if ex, ok := parseException(http.Header{}, string(rest[idx:])); ok {
+			return errors.WithStack(ex)
+		}
+	}
+
+	return errors.WithMessage(err, "error reading response")
+}
+
+// unwrapType returns the argument of a parametric type such as `Nullable(String)`.
+func unwrapType(colType string, name string) (string, bool) {
+	if strings.HasPrefix(colType, name+"(") && strings.HasSuffix(colType, ")") {
+		return colType[len(name)+1 : len(colType)-1], true
+	}
+	return "", false
+}
+
+// isStringType reports whether values of colType are returned as Go strings.
+func isStringType(colType string) bool {
+	if inner, ok := unwrapType(colType, "LowCardinality"); ok {
+		colType = inner
+	}
+
+	switch {
+	case colType == "String", colType == "UUID",
+		strings.HasPrefix(colType, "FixedString("),
+		strings.HasPrefix(colType, "Enum8("),
+		strings.HasPrefix(colType, "Enum16("):
+		return true
+	default:
+		return false
+	}
+}
+
+// parseValue converts a field of a responseFormat result to the Go type the native client
+// returns for the same column, see Row. A nil field is NULL.
+func parseValue(colType string, value *string) (interface{}, error) {
+	if inner, ok := unwrapType(colType, "LowCardinality"); ok {
+		colType = inner
+	}
+	if inner, ok := unwrapType(colType, "Nullable"); ok {
+		if value == nil {
+			return nil, nil
+		}
+		colType = inner
+	}
+	if value == nil {
+		return nil, errors.New(fmt.Sprintf("NULL value in non-Nullable column type %q", colType))
+	}
+	field := *value
+
+	if isStringType(colType) {
+		return field, nil
+	}
+
+	switch colType {
+	case "Bool":
+		return strconv.ParseBool(field)
+	case "Int8", "Int16", "Int32", "Int64":
+		bits, _ := strconv.Atoi(strings.TrimPrefix(colType, "Int"))
+		return strconv.ParseInt(field, 10, bits)
+	case "UInt8", "UInt16", "UInt32", "UInt64":
+		bits, _ := strconv.Atoi(strings.TrimPrefix(colType, "UInt"))
+		return strconv.ParseUint(field, 10, bits)
+	case "Date", "Date32":
+		return time.ParseInLocation(time.DateOnly, field, time.UTC)
+	}
+
+	if colType == "DateTime" || strings.HasPrefix(colType, "DateTime(") || strings.HasPrefix(colType, "DateTime64(") {
+		return parseDateTime(field)
+	}
+
+	if inner, ok := unwrapType(colType, "Array"); ok && isStringType(inner) {
+		return parseStringArray(field)
+	}
+
+	if inner, ok := unwrapType(colType, "Map"); ok {
+		key, value, found := strings.Cut(inner, ", ")
+		if found && isStringType(key) && isStringType(value) {
+			return parseStringMap(field)
+		}
+	}
+
+	return nil, errors.New(fmt.Sprintf("unsupported column type %q", colType))
+}
+
+// parseDateTime parses a DateTime formatted according to dateTimeOutputFormat.
+// The simple format is accepted as well, as UTC, for servers ignoring the setting.
+func parseDateTime(field string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, field); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("2006-01-02 15:04:05.999999999", field, time.UTC)
+}
+
+// textScanner reads the quoted values of arrays and maps in ClickHouse's text format,
+// for example `['a','it\'s']` or `{'key':'value'}`.
+type textScanner struct {
+	s   string
+	pos int
+}
+
+func (t *textScanner) peek() byte {
+	if t.pos < len(t.s) {
+		return t.s[t.pos]
+	}
+	return 0
+}
+
+func (t *textScanner) expect(c byte) error {
+	if t.peek() != c {
+		return errors.New(fmt.Sprintf("expected %q at position %d of %q", c, t.pos, t.s))
+	}
+	t.pos++
+	return nil
+}
+
+// next consumes the separator after a value and reports whether another value follows.
+func (t *textScanner) next(closing byte) (bool, error) {
+	switch t.peek() {
+	case ',':
+		t.pos++
+		return true, nil
+	case closing:
+		t.pos++
+		return false, nil
+	default:
+		return false, errors.New(fmt.Sprintf("expected ',' or %q at position %d of %q", closing, t.pos, t.s))
+	}
+}
+
+func (t *textScanner) quoted() (string, error) {
+	if err := t.expect('\''); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for t.pos < len(t.s) {
+		c := t.s[t.pos]
+		t.pos++
+
+		switch c {
+		case '\'':
+			return sb.String(), nil
+		case '\\':
+			if t.pos >= len(t.s) {
+				return "", errors.New(fmt.Sprintf("unterminated escape sequence in %q", t.s))
+			}
+			c = t.s[t.pos]
+			t.pos++
+			switch c {
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '0':
+				sb.WriteByte(0)
 			default:
-				panic(fmt.Sprintf("unknown data type %q", colTypes[i]))
+				sb.WriteByte(c)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", errors.New(fmt.Sprintf("unterminated string in %q", t.s))
+}
+
+func parseStringArray(field string) ([]string, error) {
+	t := &textScanner{s: field}
+	if err := t.expect('['); err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, 0)
+	if t.peek() == ']' {
+		t.pos++
+	} else {
+		for more := true; more; {
+			val, err := t.quoted()
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, val)
+
+			if more, err = t.next(']'); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if t.pos != len(field) {
+		return nil, errors.New(fmt.Sprintf("unexpected trailing data in %q", field))
+	}
+
+	return ret, nil
+}
+
+func parseStringMap(field string) (map[string]string, error) {
+	t := &textScanner{s: field}
+	if err := t.expect('{'); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]string)
+	if t.peek() == '}' {
+		t.pos++
+	} else {
+		for more := true; more; {
+			key, err := t.quoted()
+			if err != nil {
+				return nil, err
+			}
+			if err = t.expect(':'); err != nil {
+				return nil, err
+			}
+			val, err := t.quoted()
+			if err != nil {
+				return nil, err
+			}
+			ret[key] = val
+
+			if more, err = t.next('}'); err != nil {
+				return nil, err
 			}
 		}
+	}
 
-		ret = append(ret, data)
+	if t.pos != len(field) {
+		return nil, errors.New(fmt.Sprintf("unexpected trailing data in %q", field))
 	}
 
-	return ret
+	return ret, nil
 }
@@ -1,29 +1,80 @@
 package clickhouseclient
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
-func Test_jsonCompatStrings_Rows(t *testing.T) {
+type column struct {
+	Name string
+	Type string
+}
+
+// response is a query result, encoded as the server would send it in responseFormat.
+type response struct {
+	columns []column
+	// data holds the fields of each row: strings, or nil for NULL.
+	data [][]any
+}
+
+func (r response) encode(t *testing.T) string {
+	lines := make([]any, 0)
+
+	names := make([]string, 0)
+	types := make([]string, 0)
+	for _, c := range r.columns {
+		names = append(names, c.Name)
+		types = append(types, c.Type)
+	}
+	lines = append(lines, names, types)
+
+	for _, row := range r.data {
+		fields := make([]*string, 0)
+		for _, f := range row {
+			if f == nil {
+				fields = append(fields, nil)
+			} else {
+				value := f.(string)
+				fields = append(fields, &value)
+			}
+		}
+		lines = append(lines, fields)
+	}
+
+	var sb strings.Builder
+	for _, line := range lines {
+		encoded, err := json.Marshal(line)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		sb.Write(encoded)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func Test_rowDecoder(t *testing.T) {
 	tests := []struct {
-		name              string
-		jsonCompatStrings jsonCompatStrings
-		want              []Row
+		name     string
+		response response
+		body     string
+		want     []Row
+		wantErr  bool
 	}{
 		{
 			name: "Basic test",
-			jsonCompatStrings: jsonCompatStrings{
-				Meta: []struct {
-					Name string
-					Type string
-				}{
+			response: response{
+				columns: []column{
 					{
 						Name: "name",
 						Type: "String",
 					},
 				},
-				Data: [][]string{
+				data: [][]any{
 					{
 						"john",
 					},
@@ -33,27 +84,213 @@ func Test_jsonCompatStrings_Rows(t *testing.T) {
 				},
 			},
 			want: []Row{
-				rowFromMap(map[string]string{
+				rowFromMap(map[string]interface{}{
 					"name": "john",
 				}),
-				rowFromMap(map[string]string{
+				rowFromMap(map[string]interface{}{
 					"name": "frank",
 				}),
 			},
 		},
+		{
+			name: "Typed columns",
+			response: response{
+				columns: []column{
+					{Name: "id", Type: "UUID"},
+					{Name: "flag", Type: "UInt8"},
+					{Name: "enabled", Type: "Bool"},
+					{Name: "priority", Type: "Int32"},
+					{Name: "count", Type: "UInt64"},
+					{Name: "created", Type: "DateTime('UTC')"},
+					{Name: "updated", Type: "DateTime64(3)"},
+					{Name: "day", Type: "Date"},
+					{Name: "roles", Type: "Array(String)"},
+					{Name: "settings", Type: "Map(String, String)"},
+					{Name: "kind", Type: "LowCardinality(String)"},
+				},
+				data: [][]any{
+					{
+						"d3b6c6a0-0000-4000-8000-000000000001",
+						"1",
+						"true",
+						"-10",
+						"18446744073709551615",
+						"2024-05-06T07:08:09Z",
+						"2024-05-06T07:08:09.123Z",
+						"2024-05-06",
+						`['a','it\'s','x,y']`,
+						`{'max_threads':'4','k\\':'v'}`,
+						"user",
+					},
+				},
+			},
+			want: []Row{
+				rowFromMap(map[string]interface{}{
+					"id":       "d3b6c6a0-0000-4000-8000-000000000001",
+					"flag":     uint64(1),
+					"enabled":  true,
+					"priority": int64(-10),
+					"count":    uint64(18446744073709551615),
+					"created":  time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+					"updated":  time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC),
+					"day":      time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+					"roles":    []string{"a", "it's", "x,y"},
+					"settings": map[string]string{"max_threads": "4", `k\`: "v"},
+					"kind":     "user",
+				}),
+			},
+		},
+		{
+			name: "Nullable columns",
+			response: response{
+				columns: []column{
+					{Name: "filter", Type: "Nullable(String)"},
+					{Name: "priority", Type: "Nullable(Int64)"},
+				},
+				data: [][]any{
+					{nil, "5"},
+					{"1 = 1", nil},
+					{"ᴺᵁᴸᴸ", "6"},
+				},
+			},
+			want: []Row{
+				rowFromMap(map[string]interface{}{
+					"filter":   nil,
+					"priority": int64(5),
+				}),
+				rowFromMap(map[string]interface{}{
+					"filter":   "1 = 1",
+					"priority": nil,
+				}),
+				rowFromMap(map[string]interface{}{
+					"filter":   "ᴺᵁᴸᴸ",
+					"priority": int64(6),
+				}),
+			},
+		},
+		{
+			name: "NULL in a non-Nullable column",
+			response: response{
+				columns: []column{
+					{Name: "name", Type: "String"},
+				},
+				data: [][]any{
+					{nil},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Empty array and map",
+			response: response{
+				columns: []column{
+					{Name: "roles", Type: "Array(String)"},
+					{Name: "settings", Type: "Map(String, String)"},
+				},
+				data: [][]any{
+					{"[]", "{}"},
+				},
+			},
+			want: []Row{
+				rowFromMap(map[string]interface{}{
+					"roles":    []string{},
+					"settings": map[string]string{},
+				}),
+			},
+		},
+		{
+			name: "Unsupported type",
+			response: response{
+				columns: []column{
+					{Name: "amount", Type: "Decimal(10, 2)"},
+				},
+				data: [][]any{
+					{"1.50"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid value",
+			response: response{
+				columns: []column{
+					{Name: "count", Type: "UInt8"},
+				},
+				data: [][]any{
+					{"256"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Malformed array",
+			response: response{
+				columns: []column{
+					{Name: "roles", Type: "Array(String)"},
+				},
+				data: [][]any{
+					{"['a'"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Empty response",
+			body: "\n",
+		},
+		{
+			name:    "Exception after the first rows",
+			body:    "[\"name\"]\n[\"String\"]\n[\"john\"]\nCode: 241. DB::Exception: Memory limit (total) exceeded. (MEMORY_LIMIT_EXCEEDED) (version 24.8.1.1)\n",
+			wantErr: true,
+		},
+		{
+			name:    "Exception object",
+			body:    "[\"name\"]\n[\"String\"]\n{\"exception\": \"Code: 241. DB::Exception: Memory limit (total) exceeded. (MEMORY_LIMIT_EXCEEDED)\"}\n",
+			wantErr: true,
+		},
+		{
+			name:    "Truncated response",
+			body:    "[\"name\"]\n[\"String\"]\n[\"jo",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.jsonCompatStrings.Rows()
+			body := tt.body
+			if body == "" {
+				body = tt.response.encode(t)
+			}
 
+			decoder := newRowDecoder(strings.NewReader(body))
+			got := make([]Row, 0)
+			var err error
+			for {
+				var row Row
+				var ok bool
+				row, ok, err = decoder.Next()
+				if err != nil || !ok {
+					break
+				}
+				got = append(got, row)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rowDecoder.Next() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if tt.want == nil {
+				tt.want = []Row{}
+			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("jsonCompatStrings.Rows() want = %v, got %v", tt.want, got)
+				t.Errorf("rowDecoder.Next() want = %v, got %v", tt.want, got)
 			}
 		})
 	}
 }
 
-func rowFromMap(data map[string]string) Row {
+func rowFromMap(data map[string]interface{}) Row {
 	row := Row{}
 
 	for k, v := range data {
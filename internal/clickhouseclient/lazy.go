@@ -0,0 +1,53 @@
+package clickhouseclient
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+)
+
+// lazyClient opens the wrapped client when the first query runs, so that configuring the provider
+// does not require a reachable server. A failed open is retried by the next query.
+type lazyClient struct {
+	mu     sync.Mutex
+	open   func() (ClickhouseClient, error)
+	client ClickhouseClient
+}
+
+func newLazyClient(open func() (ClickhouseClient, error)) ClickhouseClient {
+	return &lazyClient{open: open}
+}
+
+func (c *lazyClient) get() (ClickhouseClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil {
+		client, err := c.open()
+		if err != nil {
+			return nil, errors.WithMessage(err, "error opening connection")
+		}
+		c.client = client
+	}
+
+	return c.client, nil
+}
+
+func (c *lazyClient) Select(ctx context.Context, qry string, callback func(Row) error, params ...map[string]string) error {
+	client, err := c.get()
+	if err != nil {
+		return err
+	}
+
+	return client.Select(ctx, qry, callback, params...)
+}
+
+func (c *lazyClient) Exec(ctx context.Context, qry string, params ...map[string]string) error {
+	client, err := c.get()
+	if err != nil {
+		return err
+	}
+
+	return client.Exec(ctx, qry, params...)
+}
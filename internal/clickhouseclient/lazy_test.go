@@ -0,0 +1,59 @@
+package clickhouseclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type countingClient struct {
+	queries int
+}
+
+func (c *countingClient) Select(_ context.Context, _ string, _ func(Row) error, _ ...map[string]string) error {
+	c.queries++
+	return nil
+}
+
+func (c *countingClient) Exec(_ context.Context, _ string, _ ...map[string]string) error {
+	c.queries++
+	return nil
+}
+
+func Test_lazyClient(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingClient{}
+	opens := 0
+	failOpen := true
+
+	client := newLazyClient(func() (ClickhouseClient, error) {
+		opens++
+		if failOpen {
+			return nil, errors.New("dial tcp: connection refused")
+		}
+		return inner, nil
+	})
+
+	if opens != 0 {
+		t.Fatalf("want no open before the first query, got %d", opens)
+	}
+
+	if err := client.Exec(ctx, "SELECT 1"); err == nil {
+		t.Fatalf("Exec() want error when the open fails, got nil")
+	}
+
+	failOpen = false
+	if err := client.Exec(ctx, "SELECT 1"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if err := client.Select(ctx, "SELECT 1", func(Row) error { return nil }); err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+
+	if opens != 2 {
+		t.Errorf("want 2 opens, a failed one and a successful one, got %d", opens)
+	}
+	if inner.queries != 2 {
+		t.Errorf("want 2 queries on the opened client, got %d", inner.queries)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
@@ -17,15 +19,27 @@ import (
 const defaultDatabase = "default"
 
 type nativeClient struct {
-	connection driver.Conn
+	connection   driver.Conn
+	queryTimeout time.Duration
+	settings     map[string]string
 }
 
 type NativeClientConfig struct {
 	Host             string
 	Port             uint16
+	AdditionalHosts  []string
+	ConnOpenStrategy ConnOpenStrategy
 	UserPasswordAuth *UserPasswordAuth
 	TLSConfig        *tls.Config
 	DialTimeout      time.Duration
+	// QueryTimeout is the maximum duration of a single query, 0 means no limit.
+	QueryTimeout time.Duration
+	// MaxIdleConns is the maximum number of idle connections in the pool, 0 means the driver default.
+	MaxIdleConns int
+	// MaxOpenConns is the maximum number of open connections, 0 means the driver default.
+	MaxOpenConns int
+	// Settings are the ClickHouse settings applied to every query, see WithSettings for overrides.
+	Settings map[string]string
 }
 
 func NewNativeClient(config NativeClientConfig) (ClickhouseClient, error) {
@@ -39,14 +53,34 @@ func NewNativeClient(config NativeClientConfig) (ClickhouseClient, error) {
 		return nil, errors.New("Exactly one authentication method is required")
 	}
 
+	addrs, err := addresses(config.Host, config.Port, config.AdditionalHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy, err := config.ConnOpenStrategy.toNative()
+	if err != nil {
+		return nil, err
+	}
+
+	// The driver fails over to the next address when dialing a host fails.
 	options := clickhouse.Options{
-		Addr: []string{fmt.Sprintf("%s:%d", config.Host, config.Port)},
+		Addr:             addrs,
+		ConnOpenStrategy: strategy,
 	}
 
 	if config.DialTimeout > 0 {
 		options.DialTimeout = config.DialTimeout
 	}
 
+	if config.MaxIdleConns > 0 {
+		options.MaxIdleConns = config.MaxIdleConns
+	}
+
+	if config.MaxOpenConns > 0 {
+		options.MaxOpenConns = config.MaxOpenConns
+	}
+
 	if config.UserPasswordAuth != nil {
 		auth := clickhouse.Auth{}
 		auth.Database = config.UserPasswordAuth.Database
@@ -64,24 +98,53 @@ func NewNativeClient(config NativeClientConfig) (ClickhouseClient, error) {
 		options.TLS = config.TLSConfig
 	}
 
-	conn, err := clickhouse.Open(&options)
-	if err != nil {
-		return nil, err
+	// The configuration is validated above, the connection is only opened by the first query.
+	return newLazyClient(func() (ClickhouseClient, error) {
+		conn, err := clickhouse.Open(&options)
+		if err != nil {
+			return nil, err
+		}
+
+		return &nativeClient{
+			connection:   conn,
+			queryTimeout: config.QueryTimeout,
+			settings:     config.Settings,
+		}, nil
+	}), nil
+}
+
+// queryContext tags the query with a unique query_id, attaches the query settings and applies the
+// configured query timeout. The driver cancels the query on the server when ctx is done.
+func (i *nativeClient) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	queryID := uuid.NewString()
+	ctx = tflog.SetField(ctx, "QueryID", queryID)
+	ctx = clickhouse.Context(ctx, clickhouse.WithQueryID(queryID), clickhouse.WithSettings(nativeSettings(querySettings(ctx, i.settings))))
+
+	if i.queryTimeout > 0 {
+		return context.WithTimeout(ctx, i.queryTimeout)
 	}
 
-	return &nativeClient{
-		connection: conn,
-	}, nil
+	return ctx, func() {}
 }
 
-func (i *nativeClient) Select(ctx context.Context, qry string, callback func(Row) error) error {
+func (i *nativeClient) Select(ctx context.Context, qry string, callback func(Row) error, params ...map[string]string) error {
+	ctx, cancel := i.queryContext(ctx)
+	defer cancel()
+
 	ctx = tflog.SetField(ctx, "Query", qry)
 	tflog.Debug(ctx, "Running Query")
 
+	if len(params) > 0 {
+		ctx = clickhouse.Context(ctx, clickhouse.WithParameters(params[0]))
+	}
+
 	rows, err := i.connection.Query(ctx, qry)
 	if err != nil {
 		return errors.WithMessage(err, "error executing query")
 	}
+	defer func() {
+		_ = rows.Close()
+	}()
 
 	// Prepare a slice of variable pointers dynamically typed based on the query result's column types.
 	columnTypes := rows.ColumnTypes()
@@ -104,23 +167,11 @@ func (i *nativeClient) Select(ctx context.Context, qry string, callback func(Row
 		// Prepare a Row for the callback.
 		ret := Row{}
 		for i, v := range vars {
-			switch v := v.(type) {
-			case *string:
-				// Non-nullable string, return string value.
-				ret.Set(rows.Columns()[i], *v)
-			case *uuid.UUID:
-				// Return string representation.
-				ret.Set(rows.Columns()[i], v.String())
-			case **string:
-				// Nullable string, return either nil or a pointer to the string
-				ret.Set(rows.Columns()[i], *v)
-			case *uint8:
-				ret.Set(rows.Columns()[i], *v)
-			case *uint64:
-				ret.Set(rows.Columns()[i], *v)
-			default:
-				return errors.New(fmt.Sprintf("unsupported column type: %s", reflect.TypeOf(v)))
+			val, err := nativeValue(v)
+			if err != nil {
+				return errors.WithMessage(err, fmt.Sprintf("unsupported column type %s", columnTypes[i].DatabaseTypeName()))
 			}
+			ret.Set(rows.Columns()[i], val)
 		}
 		err = callback(ret)
 		if err != nil {
@@ -128,10 +179,67 @@ func (i *nativeClient) Select(ctx context.Context, qry string, callback func(Row
 		}
 	}
 
+	// Exceptions raised after the first block, such as a distributed DDL timeout, end the iteration.
+	if err := rows.Err(); err != nil {
+		return errors.WithMessage(err, "error iterating over rows")
+	}
+
 	return nil
 }
 
+// nativeValue converts a value scanned by clickhouse-go to the Go type used by Row, so that the
+// native and HTTP clients return the same values for the same columns.
+func nativeValue(v any) (any, error) {
+	val := reflect.ValueOf(v).Elem()
+
+	// Nullable columns are scanned into a pointer, nil for NULL.
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return nil, nil
+		}
+		val = val.Elem()
+	}
+
+	switch v := val.Interface().(type) {
+	case string:
+		return v, nil
+	case uuid.UUID:
+		// Return string representation.
+		return v.String(), nil
+	case bool:
+		return v, nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case time.Time:
+		return v, nil
+	case []string:
+		// The scan destination is reused for the next row.
+		return slices.Clone(v), nil
+	case map[string]string:
+		return maps.Clone(v), nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unsupported Go type %s", val.Type()))
+	}
+}
+
 func (i *nativeClient) Exec(ctx context.Context, qry string, params ...map[string]string) error {
+	ctx, cancel := i.queryContext(ctx)
+	defer cancel()
+
 	ctx = tflog.SetField(ctx, "Query", qry)
 	tflog.Debug(ctx, "Running Query")
 
@@ -0,0 +1,44 @@
+package clickhouseclient
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_nativeValue(t *testing.T) {
+	created := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	id := uuid.MustParse("d3b6c6a0-0000-4000-8000-000000000001")
+
+	tests := []struct {
+		name    string
+		scanned any
+		want    any
+		wantErr bool
+	}{
+		{name: "String", scanned: new("alice"), want: "alice"},
+		{name: "Nullable string", scanned: new(new("alice")), want: "alice"},
+		{name: "NULL", scanned: new((*string)(nil)), want: nil},
+		{name: "UUID", scanned: &id, want: id.String()},
+		{name: "UInt8", scanned: new(uint8(1)), want: uint64(1)},
+		{name: "Int16", scanned: new(int16(-2)), want: int64(-2)},
+		{name: "Bool", scanned: new(true), want: true},
+		{name: "DateTime", scanned: &created, want: created},
+		{name: "Array(String)", scanned: &[]string{"a"}, want: []string{"a"}},
+		{name: "Map(String, String)", scanned: &map[string]string{"k": "v"}, want: map[string]string{"k": "v"}},
+		{name: "Unsupported", scanned: new(float64(1.5)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nativeValue(tt.scanned)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("nativeValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nativeValue() want = %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package clickhouseclient
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultRetryInitialBackoff = 200 * time.Millisecond
+	defaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// ClickHouse error codes that are worth retrying.
+const (
+	codeTimeoutExceeded           = 159
+	codeTooManySimultaneousQuery  = 202
+	codeNoFreeConnection          = 203
+	codeSocketTimeout             = 209
+	codeNetworkError              = 210
+	codeNoZooKeeper               = 225
+	codeTableIsReadOnly           = 242
+	codeAllConnectionTriesFailed  = 279
+	codeCannotScheduleTask        = 439
+	codeKeeperException           = 999
+	codeTooManyParallelOperations = 736
+)
+
+// retryClass tells whether a failed query can be run again.
+type retryClass int
+
+const (
+	// notRetryable errors are final, for example a syntax error or a missing privilege.
+	notRetryable retryClass = iota
+	// retryableIfIdempotent errors may have happened after the server started running the query,
+	// so the query is only run again when doing so twice has the same effect as doing it once.
+	retryableIfIdempotent
+	// retryableAlways errors happened before the server started running the query.
+	retryableAlways
+)
+
+// RetryConfig configures the retries of queries failing with a transient error.
+type RetryConfig struct {
+	// MaxDuration bounds the total time spent retrying a query, including the attempts themselves.
+	MaxDuration time.Duration
+	// InitialBackoff is the upper bound of the wait before the first retry, doubled at every attempt.
+	// Defaults to 200ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts. Defaults to 10s.
+	MaxBackoff time.Duration
+}
+
+type retryingClient struct {
+	client ClickhouseClient
+	config RetryConfig
+}
+
+// NewRetryingClient wraps client so that queries failing with a transient error are retried with
+// jittered exponential backoff. Statements that are not safe to run twice are only retried when
+// they failed before reaching the server.
+func NewRetryingClient(client ClickhouseClient, config RetryConfig) ClickhouseClient {
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultRetryMaxBackoff
+	}
+
+	return &retryingClient{
+		client: client,
+		config: config,
+	}
+}
+
+func (r *retryingClient) Select(ctx context.Context, qry string, callback func(Row) error, params ...map[string]string) error {
+	// Statements ON CLUSTER are run as queries to read the result of every host, so check them too.
+	return r.retry(ctx, qry, isIdempotent(qry), func() (bool, error) {
+		// Rows already passed to the callback cannot be taken back, so only retry when none were.
+		received := false
+		err := r.client.Select(ctx, qry, func(row Row) error {
+			received = true
+			return callback(row)
+		}, params...)
+		return !received, err
+	})
+}
+
+func (r *retryingClient) Exec(ctx context.Context, qry string, params ...map[string]string) error {
+	idempotent := isIdempotent(qry)
+	return r.retry(ctx, qry, idempotent, func() (bool, error) {
+		return true, r.client.Exec(ctx, qry, params...)
+	})
+}
+
+// retry runs attempt until it succeeds, fails with an error that must not be retried or the retry
+// budget is exhausted. attempt reports whether it is allowed to be run again.
+func (r *retryingClient) retry(ctx context.Context, qry string, idempotent bool, attempt func() (bool, error)) error {
+	deadline := time.Now().Add(r.config.MaxDuration)
+
+	for i := 0; ; i++ {
+		repeatable, err := attempt()
+		if err == nil || !repeatable || ctx.Err() != nil {
+			return err
+		}
+
+		switch classify(err) {
+		case notRetryable:
+			return err
+		case retryableIfIdempotent:
+			if !idempotent {
+				return err
+			}
+		}
+
+		wait := backoff(r.config.InitialBackoff, r.config.MaxBackoff, i)
+		if !time.Now().Add(wait).Before(deadline) {
+			return err
+		}
+
+		tflog.Warn(ctx, "Query failed with a transient error, retrying", map[string]any{
+			"Query":   qry,
+			"attempt": i + 1,
+			"backoff": wait.String(),
+			"error":   err.Error(),
+		})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns a random wait between 0 and initial*2^attempt, capped at maxBackoff ("full jitter").
+func backoff(initial time.Duration, maxBackoff time.Duration, attempt int) time.Duration {
+	ceiling := maxBackoff
+	if attempt < 32 && initial<<attempt < maxBackoff && initial<<attempt > 0 {
+		ceiling = initial << attempt
+	}
+
+	return time.Duration(rand.Int64N(int64(ceiling) + 1)) //nolint:gosec
+}
+
+// exceptionCodeRegex matches the error code of exceptions only available as text, for example
+// when ClickHouse fails after it started streaming an HTTP response.
+var exceptionCodeRegex = regexp.MustCompile(`^Code: (\d+)\. `)
+
+// exceptionCode returns the ClickHouse error code carried by err, if any.
+func exceptionCode(err error) (int32, bool) {
+	if ex, ok := AsException(err); ok {
+		return ex.Code, true
+	}
+
+	var code int32
+	found := FindInChain(err, func(e error) bool {
+		match := exceptionCodeRegex.FindStringSubmatch(strings.TrimSpace(e.Error()))
+		if match == nil {
+			return false
+		}
+		parsed, err := strconv.ParseInt(match[1], 10, 32)
+		code = int32(parsed)
+		return err == nil
+	})
+
+	return code, found
+}
+
+// classify tells whether err is transient and, if so, whether the query might have run on the server.
+func classify(err error) retryClass {
+	if code, ok := exceptionCode(err); ok {
+		switch code {
+		case codeTooManySimultaneousQuery, codeNoFreeConnection, codeAllConnectionTriesFailed, codeTooManyParallelOperations:
+			// The server refused to start the query.
+			return retryableAlways
+		case codeTimeoutExceeded, codeSocketTimeout, codeNetworkError, codeNoZooKeeper, codeTableIsReadOnly, codeCannotScheduleTask, codeKeeperException:
+			return retryableIfIdempotent
+		default:
+			return notRetryable
+		}
+	}
+
+	ret := notRetryable
+	FindInChain(err, func(e error) bool {
+		var opErr *net.OpError
+		var netErr net.Error
+		var statusErr *httpStatusError
+		switch {
+		case e == clickhouse.ErrAcquireConnTimeout,
+			stderrors.As(e, &opErr) && opErr.Op == "dial",
+			stderrors.Is(e, syscall.ECONNREFUSED):
+			// The connection could not be established, nothing was sent.
+			ret = retryableAlways
+		case stderrors.As(e, &statusErr):
+			switch statusErr.statusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				// Returned by proxies in front of ClickHouse, for example while an idle service wakes up.
+				ret = retryableIfIdempotent
+			default:
+				return false
+			}
+		case e == clickhouse.ErrConnectionClosed,
+			stderrors.Is(e, syscall.ECONNRESET),
+			stderrors.Is(e, syscall.EPIPE),
+			stderrors.Is(e, io.EOF),
+			stderrors.Is(e, io.ErrUnexpectedEOF),
+			stderrors.As(e, &netErr) && netErr.Timeout():
+			ret = retryableIfIdempotent
+		default:
+			return false
+		}
+		return true
+	})
+
+	return ret
+}
+
+// accessEntities are the keywords following ALTER for the entities whose ALTER statements only set
+// properties, so running them twice is harmless unless they rename the entity.
+var accessEntities = []string{"USER", "ROLE", "SETTINGS", "PROFILE", "ROW", "POLICY", "QUOTA", "MASKING"}
+
+// isIdempotent reports whether running qry twice has the same effect as running it once.
+func isIdempotent(qry string) bool {
+	words := strings.Fields(strings.ToUpper(stripQuoted(qry)))
+	if len(words) == 0 {
+		return false
+	}
+
+	contains := func(sequence ...string) bool {
+		for i := 0; i+len(sequence) <= len(words); i++ {
+			match := true
+			for j, w := range sequence {
+				if words[i+j] != w {
+					match = false
+					break
+				}
+			}
+			if match {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch words[0] {
+	case "SELECT", "WITH", "SHOW", "DESCRIBE", "DESC", "EXISTS", "EXPLAIN", "GRANT", "REVOKE", "KILL":
+		return true
+	case "CREATE":
+		return contains("IF", "NOT", "EXISTS") || contains("CREATE", "OR", "REPLACE")
+	case "DROP":
+		return contains("IF", "EXISTS")
+	case "ALTER":
+		return len(words) > 1 && slices.Contains(accessEntities, words[1]) && !contains("RENAME")
+	default:
+		return false
+	}
+}
+
+// stripQuoted replaces identifiers and literals with a placeholder so that their content is never
+// mistaken for keywords.
+func stripQuoted(qry string) string {
+	var sb strings.Builder
+	var quote rune
+	escaped := false
+	for _, c := range qry {
+		switch {
+		case quote == 0 && (c == '`' || c == '\'' || c == '"'):
+			quote = c
+			sb.WriteString(" _ ")
+		case quote == 0:
+			sb.WriteRune(c)
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == quote:
+			quote = 0
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,212 @@
+package clickhouseclient
+
+import (
+	"context"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/pingcap/errors"
+)
+
+func Test_isIdempotent(t *testing.T) {
+	tests := []struct {
+		name string
+		qry  string
+		want bool
+	}{
+		{name: "Select", qry: "SELECT `name` FROM `system`.`users`;", want: true},
+		{name: "Grant", qry: "GRANT SELECT ON `db`.* TO `role`;", want: true},
+		{name: "Revoke", qry: "REVOKE `reader` FROM `user`;", want: true},
+		{name: "Create", qry: "CREATE ROLE `reader`;", want: false},
+		{name: "Create if not exists", qry: "CREATE DATABASE IF NOT EXISTS `db`;", want: true},
+		{name: "Create or replace", qry: "CREATE OR REPLACE VIEW `db`.`v` AS SELECT 1;", want: true},
+		{name: "Drop", qry: "DROP USER `user`;", want: false},
+		{name: "Drop if exists", qry: "DROP ROLE IF EXISTS `reader`;", want: true},
+		{name: "Alter access entity", qry: "ALTER SETTINGS PROFILE `p` SETTINGS max_threads = 2;", want: true},
+		{name: "Rename access entity", qry: "ALTER ROLE `a` RENAME TO `b`;", want: false},
+		{name: "Alter table", qry: "ALTER TABLE `db`.`t` ADD COLUMN `c` String;", want: false},
+		{name: "Keywords in identifiers are ignored", qry: "CREATE ROLE `IF NOT EXISTS`;", want: false},
+		{name: "Keywords in literals are ignored", qry: "CREATE USER `u` IDENTIFIED BY 'if not exists';", want: false},
+		{name: "Lowercase", qry: "  drop role if exists `r`", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isIdempotent(tt.qry); got != tt.want {
+				t.Errorf("isIdempotent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_classify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want retryClass
+	}{
+		{
+			name: "Keeper exception over native",
+			err:  errors.WithMessage(&clickhouse.Exception{Code: 999, Message: "Coordination error"}, "error running query"),
+			want: retryableIfIdempotent,
+		},
+		{
+			name: "Timeout exceeded over HTTP",
+			err:  errors.WithMessage(errors.New("Code: 159. DB::Exception: Watching task is executing longer than distributed_ddl_task_timeout. (TIMEOUT_EXCEEDED)"), "error running query"),
+			want: retryableIfIdempotent,
+		},
+		{
+			name: "Too many simultaneous queries",
+			err:  &clickhouse.Exception{Code: 202},
+			want: retryableAlways,
+		},
+		{
+			name: "Access denied",
+			err:  errors.New("Code: 497. DB::Exception: default: Not enough privileges. (ACCESS_DENIED)"),
+			want: notRetryable,
+		},
+		{
+			name: "Connection refused",
+			err:  errors.WithMessage(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, "error executing query"),
+			want: retryableAlways,
+		},
+		{
+			name: "Connection reset",
+			err:  errors.WithMessage(&net.OpError{Op: "read", Err: syscall.ECONNRESET}, "error executing query"),
+			want: retryableIfIdempotent,
+		},
+		{
+			name: "Unexpected EOF",
+			err:  errors.WithMessage(io.ErrUnexpectedEOF, "error reading response"),
+			want: retryableIfIdempotent,
+		},
+		{
+			name: "Service unavailable",
+			err:  errors.WithStack(&httpStatusError{statusCode: 503, body: "upstream unavailable"}),
+			want: retryableIfIdempotent,
+		},
+		{
+			name: "Bad request",
+			err:  errors.WithStack(&httpStatusError{statusCode: 400, body: "Syntax error"}),
+			want: notRetryable,
+		},
+		{
+			name: "Other error",
+			err:  errors.New("boom"),
+			want: notRetryable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.err); got != tt.want {
+				t.Errorf("classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeClient struct {
+	errs  []error
+	calls int
+}
+
+func (f *fakeClient) next() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeClient) Select(_ context.Context, _ string, _ func(Row) error, _ ...map[string]string) error {
+	return f.next()
+}
+
+func (f *fakeClient) Exec(_ context.Context, _ string, _ ...map[string]string) error {
+	return f.next()
+}
+
+func Test_retryingClient_Exec(t *testing.T) {
+	keeperErr := &clickhouse.Exception{Code: 999}
+	refusedErr := &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}
+
+	tests := []struct {
+		name      string
+		qry       string
+		errs      []error
+		maxDur    time.Duration
+		wantErr   bool
+		wantCalls int
+	}{
+		{
+			name:      "Idempotent statement is retried until it succeeds",
+			qry:       "GRANT SELECT ON *.* TO `r`;",
+			errs:      []error{keeperErr, keeperErr},
+			maxDur:    time.Minute,
+			wantCalls: 3,
+		},
+		{
+			name:      "Non idempotent statement is not retried once sent",
+			qry:       "CREATE ROLE `r`;",
+			errs:      []error{keeperErr},
+			maxDur:    time.Minute,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "Non idempotent statement is retried when never sent",
+			qry:       "CREATE ROLE `r`;",
+			errs:      []error{refusedErr},
+			maxDur:    time.Minute,
+			wantCalls: 2,
+		},
+		{
+			name:      "Permanent errors are not retried",
+			qry:       "GRANT SELECT ON *.* TO `r`;",
+			errs:      []error{errors.New("Code: 497. DB::Exception: Not enough privileges. (ACCESS_DENIED)")},
+			maxDur:    time.Minute,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "Retries disabled",
+			qry:       "GRANT SELECT ON *.* TO `r`;",
+			errs:      []error{keeperErr},
+			maxDur:    0,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeClient{errs: tt.errs}
+			client := NewRetryingClient(fake, RetryConfig{
+				MaxDuration:    tt.maxDur,
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     time.Millisecond,
+			})
+
+			err := client.Exec(context.Background(), tt.qry)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Exec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if fake.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", fake.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_backoff(t *testing.T) {
+	for attempt := range 70 {
+		wait := backoff(100*time.Millisecond, 5*time.Second, attempt)
+		if wait < 0 || wait > 5*time.Second {
+			t.Fatalf("backoff(attempt=%d) = %v, want between 0 and 5s", attempt, wait)
+		}
+	}
+}
@@ -2,73 +2,218 @@ package clickhouseclient
 
 import (
 	"fmt"
-	"reflect"
+	"math"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/pingcap/errors"
 )
 
+// Row is a single row of a query result.
+//
+// Both transports store values with the same Go types, regardless of the width of the ClickHouse type:
+// strings, UUIDs and enums as string, signed integers as int64, unsigned integers as uint64, Bool as
+// bool, Date and DateTime variants as time.Time, Array(String) as []string and Map(String, String) as
+// map[string]string. NULL values of Nullable columns are stored as nil.
 type Row struct {
 	data map[string]interface{}
 }
 
-func (r *Row) GetString(fieldName string) (string, error) {
+// get returns the value of fieldName, failing when the row has no such column.
+func (r *Row) get(fieldName string) (interface{}, error) {
 	val, ok := r.data[fieldName]
 	if !ok {
-		return "", errors.New(fmt.Sprintf("field %s was not found in row", fieldName))
+		return nil, errors.New(fmt.Sprintf("field %s was not found in row", fieldName))
+	}
+
+	return val, nil
+}
+
+func typeError(fieldName string, want string, val interface{}) error {
+	if val == nil {
+		return errors.New(fmt.Sprintf("field %s is NULL, want %s", fieldName, want))
+	}
+	return errors.New(fmt.Sprintf("field %s is not a %s (%T)", fieldName, want, val))
+}
+
+// getNullable calls getter unless the value of fieldName is NULL.
+func getNullable[T any](r *Row, fieldName string, getter func(string) (T, error)) (*T, error) {
+	val, err := r.get(fieldName)
+	if err != nil {
+		return nil, err
 	}
 
-	if reflect.TypeOf(val).Name() != "string" {
-		return "", errors.New(fmt.Sprintf("field %s is not a string", fieldName))
+	if val == nil {
+		return nil, nil
 	}
 
-	return val.(string), nil
+	ret, err := getter(fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
 }
 
-func (r *Row) GetNullableString(fieldName string) (*string, error) {
-	val, ok := r.data[fieldName]
-	if !ok {
-		return nil, errors.New(fmt.Sprintf("field %s was not found in row", fieldName))
+func (r *Row) GetString(fieldName string) (string, error) {
+	val, err := r.get(fieldName)
+	if err != nil {
+		return "", err
 	}
 
-	if reflect.TypeOf(val).String() != "*string" {
-		return nil, errors.New(fmt.Sprintf("field %s is not a string pointer (%s)", fieldName, reflect.TypeOf(val).String()))
+	str, ok := val.(string)
+	if !ok {
+		return "", typeError(fieldName, "string", val)
 	}
 
-	return val.(*string), nil
+	return str, nil
 }
 
+func (r *Row) GetNullableString(fieldName string) (*string, error) {
+	return getNullable(r, fieldName, r.GetString)
+}
+
+// GetBool returns a Bool column, or an unsigned integer column holding 0 or 1 such as the UInt8
+// flags of older system tables.
 func (r *Row) GetBool(fieldName string) (bool, error) {
-	val, ok := r.data[fieldName]
-	if !ok {
-		return false, errors.New(fmt.Sprintf("field %s was not found in row", fieldName))
+	val, err := r.get(fieldName)
+	if err != nil {
+		return false, err
 	}
 
-	switch reflect.TypeOf(val).String() {
-	case "bool":
-		return val.(bool), nil
-	case "uint8":
-		if val.(uint8) == 0 {
+	switch v := val.(type) {
+	case bool:
+		return v, nil
+	case uint64:
+		switch v {
+		case 0:
 			return false, nil
-		}
-		if val.(uint8) == 1 {
+		case 1:
 			return true, nil
 		}
 	}
 
-	return false, errors.New(fmt.Sprintf("unable to get field %s as bool: (%s)", fieldName, reflect.TypeOf(val).String()))
+	return false, typeError(fieldName, "bool", val)
+}
+
+func (r *Row) GetNullableBool(fieldName string) (*bool, error) {
+	return getNullable(r, fieldName, r.GetBool)
+}
+
+// GetInt64 returns an integer column of any width, failing if the value overflows an int64.
+func (r *Row) GetInt64(fieldName string) (int64, error) {
+	val, err := r.get(fieldName)
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := val.(type) {
+	case int64:
+		return v, nil
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v), nil
+		}
+		return 0, errors.New(fmt.Sprintf("field %s overflows int64 (%d)", fieldName, v))
+	}
+
+	return 0, typeError(fieldName, "int64", val)
+}
+
+func (r *Row) GetNullableInt64(fieldName string) (*int64, error) {
+	return getNullable(r, fieldName, r.GetInt64)
 }
 
+// GetUInt64 returns an integer column of any width, failing if the value is negative.
 func (r *Row) GetUInt64(fieldName string) (uint64, error) {
-	val, ok := r.data[fieldName]
+	val, err := r.get(fieldName)
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := val.(type) {
+	case uint64:
+		return v, nil
+	case int64:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+		return 0, errors.New(fmt.Sprintf("field %s is negative (%d)", fieldName, v))
+	}
+
+	return 0, typeError(fieldName, "uint64", val)
+}
+
+func (r *Row) GetNullableUInt64(fieldName string) (*uint64, error) {
+	return getNullable(r, fieldName, r.GetUInt64)
+}
+
+// GetDateTime returns a Date, Date32, DateTime or DateTime64 column.
+func (r *Row) GetDateTime(fieldName string) (time.Time, error) {
+	val, err := r.get(fieldName)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, ok := val.(time.Time)
+	if !ok {
+		return time.Time{}, typeError(fieldName, "time.Time", val)
+	}
+
+	return t, nil
+}
+
+func (r *Row) GetNullableDateTime(fieldName string) (*time.Time, error) {
+	return getNullable(r, fieldName, r.GetDateTime)
+}
+
+// GetUUID returns a UUID column, or a string column holding a UUID.
+func (r *Row) GetUUID(fieldName string) (uuid.UUID, error) {
+	str, err := r.GetString(fieldName)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	ret, err := uuid.Parse(str)
+	if err != nil {
+		return uuid.Nil, errors.WithMessage(err, fmt.Sprintf("field %s is not a UUID", fieldName))
+	}
+
+	return ret, nil
+}
+
+func (r *Row) GetNullableUUID(fieldName string) (*uuid.UUID, error) {
+	return getNullable(r, fieldName, r.GetUUID)
+}
+
+// GetStringArray returns an Array(String) column.
+func (r *Row) GetStringArray(fieldName string) ([]string, error) {
+	val, err := r.get(fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, ok := val.([]string)
 	if !ok {
-		return 0, errors.New(fmt.Sprintf("field %s was not found in row", fieldName))
+		return nil, typeError(fieldName, "[]string", val)
+	}
+
+	return arr, nil
+}
+
+// GetStringMap returns a Map(String, String) column.
+func (r *Row) GetStringMap(fieldName string) (map[string]string, error) {
+	val, err := r.get(fieldName)
+	if err != nil {
+		return nil, err
 	}
 
-	if reflect.TypeOf(val).Name() != "uint64" {
-		return 0, errors.New(fmt.Sprintf("field %s is not a uint64 (%s)", fieldName, reflect.TypeOf(val).Name()))
+	m, ok := val.(map[string]string)
+	if !ok {
+		return nil, typeError(fieldName, "map[string]string", val)
 	}
 
-	return val.(uint64), nil
+	return m, nil
 }
 
 func (r *Row) Set(fieldName string, val interface{}) {
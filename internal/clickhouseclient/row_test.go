@@ -0,0 +1,73 @@
+package clickhouseclient
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRow_getters(t *testing.T) {
+	created := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	row := rowFromMap(map[string]interface{}{
+		"name":     "alice",
+		"null":     nil,
+		"flag":     uint64(1),
+		"other":    uint64(2),
+		"enabled":  false,
+		"priority": int64(-3),
+		"count":    uint64(42),
+		"created":  created,
+		"id":       "d3b6c6a0-0000-4000-8000-000000000001",
+		"roles":    []string{"a", "b"},
+		"settings": map[string]string{"k": "v"},
+	})
+
+	tests := []struct {
+		name    string
+		get     func(r *Row) (interface{}, error)
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "String", get: func(r *Row) (interface{}, error) { return r.GetString("name") }, want: "alice"},
+		{name: "String from NULL", get: func(r *Row) (interface{}, error) { return r.GetString("null") }, wantErr: true},
+		{name: "Missing field", get: func(r *Row) (interface{}, error) { return r.GetString("missing") }, wantErr: true},
+		{name: "Nullable string", get: func(r *Row) (interface{}, error) { return r.GetNullableString("name") }, want: new("alice")},
+		{name: "Nullable string from NULL", get: func(r *Row) (interface{}, error) { return r.GetNullableString("null") }, want: (*string)(nil)},
+		{name: "Bool from UInt8", get: func(r *Row) (interface{}, error) { return r.GetBool("flag") }, want: true},
+		{name: "Bool from other integer", get: func(r *Row) (interface{}, error) { return r.GetBool("other") }, wantErr: true},
+		{name: "Bool", get: func(r *Row) (interface{}, error) { return r.GetBool("enabled") }, want: false},
+		{name: "Nullable bool from NULL", get: func(r *Row) (interface{}, error) { return r.GetNullableBool("null") }, want: (*bool)(nil)},
+		{name: "Int64", get: func(r *Row) (interface{}, error) { return r.GetInt64("priority") }, want: int64(-3)},
+		{name: "Int64 from unsigned", get: func(r *Row) (interface{}, error) { return r.GetInt64("count") }, want: int64(42)},
+		{name: "Int64 from string", get: func(r *Row) (interface{}, error) { return r.GetInt64("name") }, wantErr: true},
+		{name: "Nullable int64", get: func(r *Row) (interface{}, error) { return r.GetNullableInt64("priority") }, want: new(int64(-3))},
+		{name: "UInt64", get: func(r *Row) (interface{}, error) { return r.GetUInt64("count") }, want: uint64(42)},
+		{name: "UInt64 from negative", get: func(r *Row) (interface{}, error) { return r.GetUInt64("priority") }, wantErr: true},
+		{name: "Nullable uint64 from NULL", get: func(r *Row) (interface{}, error) { return r.GetNullableUInt64("null") }, want: (*uint64)(nil)},
+		{name: "DateTime", get: func(r *Row) (interface{}, error) { return r.GetDateTime("created") }, want: created},
+		{name: "Nullable DateTime", get: func(r *Row) (interface{}, error) { return r.GetNullableDateTime("created") }, want: &created},
+		{name: "UUID", get: func(r *Row) (interface{}, error) { return r.GetUUID("id") }, want: uuid.MustParse("d3b6c6a0-0000-4000-8000-000000000001")},
+		{name: "UUID from other string", get: func(r *Row) (interface{}, error) { return r.GetUUID("name") }, wantErr: true},
+		{name: "Nullable UUID from NULL", get: func(r *Row) (interface{}, error) { return r.GetNullableUUID("null") }, want: (*uuid.UUID)(nil)},
+		{name: "String array", get: func(r *Row) (interface{}, error) { return r.GetStringArray("roles") }, want: []string{"a", "b"}},
+		{name: "String map", get: func(r *Row) (interface{}, error) { return r.GetStringMap("settings") }, want: map[string]string{"k": "v"}},
+		{name: "String map from array", get: func(r *Row) (interface{}, error) { return r.GetStringMap("roles") }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get(&row)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want = %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
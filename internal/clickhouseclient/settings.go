@@ -0,0 +1,51 @@
+package clickhouseclient
+
+import (
+	"context"
+	"maps"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+type settingsContextKey struct{}
+
+// WithSettings returns a copy of ctx carrying ClickHouse settings for the queries run with it.
+// Settings already carried by ctx are kept unless overridden, and all of them take precedence
+// over the settings configured on the client.
+func WithSettings(ctx context.Context, settings map[string]string) context.Context {
+	if len(settings) == 0 {
+		return ctx
+	}
+
+	merged := make(map[string]string)
+	if existing, ok := ctx.Value(settingsContextKey{}).(map[string]string); ok {
+		maps.Copy(merged, existing)
+	}
+	maps.Copy(merged, settings)
+
+	return context.WithValue(ctx, settingsContextKey{}, merged)
+}
+
+// querySettings returns the client defaults overridden by the settings carried by ctx.
+func querySettings(ctx context.Context, defaults map[string]string) map[string]string {
+	ret := maps.Clone(defaults)
+	if ret == nil {
+		ret = make(map[string]string)
+	}
+
+	if fromContext, ok := ctx.Value(settingsContextKey{}).(map[string]string); ok {
+		maps.Copy(ret, fromContext)
+	}
+
+	return ret
+}
+
+// nativeSettings converts settings to the clickhouse-go representation.
+func nativeSettings(settings map[string]string) clickhouse.Settings {
+	ret := make(clickhouse.Settings, len(settings))
+	for k, v := range settings {
+		ret[k] = v
+	}
+
+	return ret
+}
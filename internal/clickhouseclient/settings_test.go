@@ -0,0 +1,56 @@
+package clickhouseclient
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func Test_querySettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults map[string]string
+		layers   []map[string]string
+		want     map[string]string
+	}{
+		{
+			name: "Nothing set",
+			want: map[string]string{},
+		},
+		{
+			name:     "Only defaults",
+			defaults: map[string]string{"max_execution_time": "60"},
+			want:     map[string]string{"max_execution_time": "60"},
+		},
+		{
+			name:     "Context overrides defaults",
+			defaults: map[string]string{"max_execution_time": "60", "insert_quorum": "2"},
+			layers:   []map[string]string{{"max_execution_time": "10"}},
+			want:     map[string]string{"max_execution_time": "10", "insert_quorum": "2"},
+		},
+		{
+			name:     "Inner context overrides outer context",
+			defaults: map[string]string{"max_execution_time": "60"},
+			layers: []map[string]string{
+				{"max_execution_time": "10", "insert_quorum": "2"},
+				{"max_execution_time": "5"},
+			},
+			want: map[string]string{"max_execution_time": "5", "insert_quorum": "2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			for _, layer := range tt.layers {
+				ctx = WithSettings(ctx, layer)
+			}
+
+			if got := querySettings(ctx, tt.defaults); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("querySettings() = %v, want %v", got, tt.want)
+			}
+			if len(tt.defaults) > 0 && !reflect.DeepEqual(querySettings(context.Background(), tt.defaults), tt.defaults) {
+				t.Error("querySettings() modified the defaults")
+			}
+		})
+	}
+}
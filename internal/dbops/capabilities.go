@@ -1,15 +1,33 @@
 package dbops
 
 import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pingcap/errors"
 	"golang.org/x/mod/semver"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
 )
 
 // Represents the features supported by the connected ClickHouse server.
 type CapabilityFlags struct {
 	SourcesGrantReadWriteSeparation bool
+	// MaskingPolicies is set when the server has system.masking_policies, as ClickHouse Cloud does.
+	MaskingPolicies bool
+	// MultipleAuthMethods is set when a user can be identified with more than one method.
+	MultipleAuthMethods bool
+	// FromVersion is set when the server could not be probed and the flags were derived from its
+	// version. Flags left unset are then unknown rather than unsupported: the version does not tell
+	// about ClickHouse Cloud builds and backports, and never sets MaskingPolicies.
+	FromVersion bool
 }
 
 // Initialize a new CapabilityFlags structure based on the ClickHouse version.
+// Only used when the server cannot be probed, see probeServerFeatures.
 func NewCapabilityFlags(chVersion string) *CapabilityFlags {
 	flags := &CapabilityFlags{}
 
@@ -17,5 +35,119 @@ func NewCapabilityFlags(chVersion string) *CapabilityFlags {
 		flags.SourcesGrantReadWriteSeparation = true
 	}
 
+	if semver.Compare(chVersion, "v24.9.0") >= 0 {
+		flags.MultipleAuthMethods = true
+	}
+
 	return flags
 }
+
+// serverFeatures is what the capability probe found on the server.
+type serverFeatures struct {
+	// systemTables are the names of the tables of the system database.
+	systemTables map[string]bool
+	// userColumns are the types of the columns of system.users, by name.
+	userColumns map[string]string
+	// privileges is the catalog of system.privileges.
+	privileges grants.Catalog
+}
+
+// capabilityFlags derives the flags from what the server has rather than from its version, which
+// does not account for ClickHouse Cloud builds and backports.
+func (f serverFeatures) capabilityFlags() CapabilityFlags {
+	return CapabilityFlags{
+		SourcesGrantReadWriteSeparation: f.privileges.Has("READ") && f.privileges.Has("WRITE"),
+		MaskingPolicies:                 f.systemTables["masking_policies"],
+		MultipleAuthMethods:             strings.HasPrefix(f.userColumns["auth_type"], "Array("),
+	}
+}
+
+// Returns the capability flags of the connected ClickHouse server, probed once per provider run.
+func (i *impl) GetCapabilityFlags(ctx context.Context) (CapabilityFlags, error) {
+	return i.capabilities.get(func() (CapabilityFlags, error) {
+		features, err := i.probeServerFeatures(ctx)
+		if err == nil {
+			return features.capabilityFlags(), nil
+		}
+
+		// The probe reads system tables the user might not be allowed to, fall back to the version.
+		tflog.Warn(ctx, "Could not probe server capabilities, deriving them from the version", map[string]any{
+			"error": err.Error(),
+		})
+
+		version, err := i.GetVersion(ctx)
+		if err != nil {
+			return CapabilityFlags{}, err
+		}
+
+		flags := NewCapabilityFlags(version)
+		flags.FromVersion = true
+
+		return *flags, nil
+	})
+}
+
+func (i *impl) probeServerFeatures(ctx context.Context) (*serverFeatures, error) {
+	features := &serverFeatures{
+		systemTables: make(map[string]bool),
+		userColumns:  make(map[string]string),
+	}
+
+	probes := []struct {
+		builder querybuilder.SelectQueryBuilder
+		scan    func(clickhouseclient.Row) error
+	}{
+		{
+			builder: querybuilder.NewSelect(
+				[]querybuilder.Field{querybuilder.NewField("name")},
+				"system.tables",
+			).Where(querybuilder.WhereEquals("database", "system")),
+			scan: func(data clickhouseclient.Row) error {
+				name, err := data.GetString("name")
+				if err != nil {
+					return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+				}
+				features.systemTables[name] = true
+				return nil
+			},
+		},
+		{
+			builder: querybuilder.NewSelect(
+				[]querybuilder.Field{querybuilder.NewField("name"), querybuilder.NewField("type")},
+				"system.columns",
+			).Where(querybuilder.WhereEquals("database", "system"), querybuilder.WhereEquals("table", "users")),
+			scan: func(data clickhouseclient.Row) error {
+				name, err := data.GetString("name")
+				if err != nil {
+					return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+				}
+				columnType, err := data.GetString("type")
+				if err != nil {
+					return errors.WithMessage(err, "error scanning query result, missing 'type' field")
+				}
+				features.userColumns[name] = columnType
+				return nil
+			},
+		},
+	}
+
+	for _, probe := range probes {
+		sql, err := probe.builder.Build()
+		if err != nil {
+			return nil, errors.WithMessage(err, "error building query")
+		}
+
+		err = i.clickhouseClient.Select(ctx, sql, probe.scan, probe.builder.Parameters())
+		if err != nil {
+			return nil, errors.WithMessage(err, "error running query")
+		}
+	}
+
+	privileges, err := i.loadPrivilegeCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	features.privileges = privileges
+
+	return features, nil
+}
@@ -0,0 +1,74 @@
+package dbops
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetCapabilityFlags(t *testing.T) {
+	accessDenied := errors.New("Code: 497. DB::Exception: default: Not enough privileges. (ACCESS_DENIED)")
+
+	tests := []struct {
+		name      string
+		responses []ddlResponse
+		want      CapabilityFlags
+	}{
+		{
+			name: "Probed",
+			responses: []ddlResponse{
+				{rows: []map[string]any{{"name": "users"}, {"name": "masking_policies"}}},
+				{rows: []map[string]any{{"name": "auth_type", "type": "Array(Enum8('no_password' = 0))"}}},
+				{rows: []map[string]any{privilegeRow("SELECT", "COLUMN", "ALL"), privilegeRow("READ", "SOURCE", "ALL"), privilegeRow("WRITE", "SOURCE", "ALL")}},
+			},
+			want: CapabilityFlags{SourcesGrantReadWriteSeparation: true, MaskingPolicies: true, MultipleAuthMethods: true},
+		},
+		{
+			name: "Probed, older server",
+			responses: []ddlResponse{
+				{rows: []map[string]any{{"name": "users"}}},
+				{rows: []map[string]any{{"name": "auth_type", "type": "Enum8('no_password' = 0)"}}},
+				{rows: []map[string]any{privilegeRow("SELECT", "COLUMN", "ALL"), privilegeRow("S3", "GLOBAL", "SOURCES")}},
+			},
+			want: CapabilityFlags{},
+		},
+		{
+			name: "Probe denied, version fallback",
+			responses: []ddlResponse{
+				{err: accessDenied},
+				{rows: []map[string]any{{"value": "25.8.2.29"}}},
+			},
+			want: CapabilityFlags{SourcesGrantReadWriteSeparation: true, MultipleAuthMethods: true, FromVersion: true},
+		},
+		{
+			name: "Probe denied, older version",
+			responses: []ddlResponse{
+				{err: accessDenied},
+				{rows: []map[string]any{{"value": "24.3.1.1"}}},
+			},
+			want: CapabilityFlags{FromVersion: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &ddlClient{responses: tt.responses}
+			i := &impl{clickhouseClient: fake}
+
+			got, err := i.GetCapabilityFlags(context.Background())
+			if err != nil {
+				t.Fatalf("GetCapabilityFlags() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetCapabilityFlags() want = %+v, got %+v", tt.want, got)
+			}
+
+			// Probed once per provider run.
+			if _, err := i.GetCapabilityFlags(context.Background()); err != nil {
+				t.Fatalf("GetCapabilityFlags() error = %v", err)
+			}
+			if len(fake.responses) != 0 || len(fake.queries) != len(tt.responses) {
+				t.Errorf("want %d queries, got %d", len(tt.responses), len(fake.queries))
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// ClusterHost is one replica of a cluster, as listed in system.clusters.
+type ClusterHost struct {
+	ShardNum   uint64
+	ReplicaNum uint64
+	HostName   string
+	Port       uint64
+}
+
+// Cluster is the topology of a cluster defined on the server.
+type Cluster struct {
+	Name  string
+	Hosts []ClusterHost
+}
+
+// Shards returns the number of shards of the cluster.
+func (c *Cluster) Shards() int {
+	shards := make(map[uint64]bool)
+	for _, h := range c.Hosts {
+		shards[h.ShardNum] = true
+	}
+
+	return len(shards)
+}
+
+// GetCluster returns the topology of clusterName, or nil if the server does not define such a cluster.
+func (i *impl) GetCluster(ctx context.Context, clusterName string) (*Cluster, error) {
+	return i.clusters.get(clusterName, func() (*Cluster, error) {
+		return i.queryCluster(ctx, clusterName)
+	})
+}
+
+func (i *impl) queryCluster(ctx context.Context, clusterName string) (*Cluster, error) {
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("shard_num"),
+			querybuilder.NewField("replica_num"),
+			querybuilder.NewField("host_name"),
+			querybuilder.NewField("port"),
+		},
+		"system.clusters",
+	).Where(querybuilder.WhereEquals("cluster", clusterName))
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var cluster *Cluster
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		shardNum, err := data.GetUInt64("shard_num")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'shard_num' field")
+		}
+		replicaNum, err := data.GetUInt64("replica_num")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'replica_num' field")
+		}
+		hostName, err := data.GetString("host_name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'host_name' field")
+		}
+		port, err := data.GetUInt64("port")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'port' field")
+		}
+
+		if cluster == nil {
+			cluster = &Cluster{Name: clusterName}
+		}
+		cluster.Hosts = append(cluster.Hosts, ClusterHost{
+			ShardNum:   shardNum,
+			ReplicaNum: replicaNum,
+			HostName:   hostName,
+			Port:       port,
+		})
+
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return cluster, nil
+}
@@ -3,6 +3,7 @@ package dbops
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/pingcap/errors"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
@@ -13,9 +14,16 @@ type Database struct {
 	UUID    string `json:"uuid"`
 	Name    string `json:"name"`
 	Comment string `json:"comment" ch:"comment"`
+	// Divergence is set when the database differs between the replicas of its cluster.
+	Divergence *ReplicaDivergence `json:"-"`
 }
 
 func (i *impl) CreateDatabase(ctx context.Context, database Database, clusterName *string) (*Database, error) {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	builder := querybuilder.NewCreateDatabase(database.Name).WithCluster(clusterName)
 	if database.Comment != "" {
 		builder.WithComment(database.Comment)
@@ -25,19 +33,39 @@ func (i *impl) CreateDatabase(ctx context.Context, database Database, clusterNam
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	repair, err := i.existsOnSomeReplicas(ctx, clusterName, "system.databases", database.Name)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error running query")
+		return nil, errors.WithMessage(err, "error comparing replicas")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		if !repair || !isDatabaseAlreadyExistsError(err) {
+			return partiallyCreated(err, func() (*Database, error) {
+				return i.FindDatabaseByName(ctx, database.Name, clusterName)
+			})
+		}
+
+		// The replicas that already had the database refused to create it again, the others now have it.
+		tflog.Warn(ctx, "database existed on some replicas only, created it on the others", map[string]any{
+			"name": database.Name,
+		})
 	}
 
 	return i.FindDatabaseByName(ctx, database.Name, clusterName)
 }
 
 func (i *impl) GetDatabase(ctx context.Context, uuid string, clusterName *string) (*Database, error) {
-	sql, err := querybuilder.NewSelect(
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{querybuilder.NewField("name"), querybuilder.NewField("comment")},
 		"system.databases",
-	).WithCluster(clusterName).Where(querybuilder.WhereEquals("uuid", uuid)).Build()
+	).WithCluster(clusterName).Where(querybuilder.WhereEquals("uuid", uuid))
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
@@ -59,7 +87,7 @@ func (i *impl) GetDatabase(ctx context.Context, uuid string, clusterName *string
 			Comment: c,
 		}
 		return nil
-	})
+	}, builder.Parameters())
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
@@ -69,10 +97,24 @@ func (i *impl) GetDatabase(ctx context.Context, uuid string, clusterName *string
 		return nil, nil
 	}
 
+	database.Divergence, err = i.checkReplicas(ctx, clusterName, replicaCheck{
+		table:   "system.databases",
+		name:    database.Name,
+		columns: []string{"engine", "comment"},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error comparing replicas")
+	}
+
 	return database, nil
 }
 
 func (i *impl) DeleteDatabase(ctx context.Context, uuid string, clusterName *string) error {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	database, err := i.GetDatabase(ctx, uuid, clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error getting database name")
@@ -83,12 +125,13 @@ func (i *impl) DeleteDatabase(ctx context.Context, uuid string, clusterName *str
 		return nil
 	}
 
-	sql, err := querybuilder.NewDropDatabase(database.Name).WithCluster(clusterName).Build()
+	builder := querybuilder.NewDropDatabase(database.Name).WithCluster(clusterName)
+	sql, err := builder.Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
@@ -97,10 +140,16 @@ func (i *impl) DeleteDatabase(ctx context.Context, uuid string, clusterName *str
 }
 
 func (i *impl) FindDatabaseByName(ctx context.Context, name string, clusterName *string) (*Database, error) {
-	sql, err := querybuilder.NewSelect(
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{querybuilder.NewField("uuid").ToString()},
 		"system.databases",
-	).WithCluster(clusterName).Where(querybuilder.WhereEquals("name", name)).Build()
+	).WithCluster(clusterName).Where(querybuilder.WhereEquals("name", name))
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
@@ -114,7 +163,7 @@ func (i *impl) FindDatabaseByName(ctx context.Context, name string, clusterName
 		}
 
 		return nil
-	})
+	}, builder.Parameters())
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
@@ -0,0 +1,326 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// ClickHouse error code 159 (TIMEOUT_EXCEEDED), returned when distributed_ddl_task_timeout expires
+// before every host ran a statement ON CLUSTER.
+const timeoutExceededCode = 159
+
+const (
+	ddlQueuePollTimeout        = 5 * time.Minute
+	ddlQueuePollInitialBackoff = 500 * time.Millisecond
+	ddlQueuePollMaxBackoff     = 10 * time.Second
+)
+
+// ddlTaskRegex matches the queue entry in the message of a distributed DDL timeout, for example
+// "Distributed DDL task /clickhouse/task_queue/ddl/query-0000000012 is not finished on 1 of 3 hosts".
+var ddlTaskRegex = regexp.MustCompile(`Distributed DDL task \S*?(query-\d+)`)
+
+// DDLHostResult is the outcome of a statement ON CLUSTER on one host.
+type DDLHostResult struct {
+	Host string
+	Port uint64
+	// Error is the exception the host failed with, empty on success.
+	Error string
+}
+
+func (r DDLHostResult) String() string {
+	return fmt.Sprintf("%s:%d", r.Host, r.Port)
+}
+
+// DistributedDDLError is returned when a statement ON CLUSTER did not succeed on every host.
+type DistributedDDLError struct {
+	Cluster   string
+	Succeeded []DDLHostResult
+	Failed    []DDLHostResult
+	// Pending are the hosts that had not run the statement yet when the provider stopped waiting.
+	// ClickHouse still runs it there in the background.
+	Pending []DDLHostResult
+
+	cause error
+}
+
+func (e *DistributedDDLError) Error() string {
+	total := len(e.Succeeded) + len(e.Failed) + len(e.Pending)
+
+	parts := make([]string, 0, len(e.Failed)+1)
+	for _, h := range e.Failed {
+		parts = append(parts, fmt.Sprintf("%s failed: %s", h, strings.TrimSpace(h.Error)))
+	}
+	if len(e.Pending) > 0 {
+		pending := make([]string, 0, len(e.Pending))
+		for _, h := range e.Pending {
+			pending = append(pending, h.String())
+		}
+		parts = append(parts, fmt.Sprintf("not finished on %s", strings.Join(pending, ", ")))
+	}
+
+	return fmt.Sprintf("distributed DDL on cluster %q succeeded on %d of %d hosts: %s", e.Cluster, len(e.Succeeded), total, strings.Join(parts, "; "))
+}
+
+// Cause returns the error ClickHouse returned for the statement, if any.
+func (e *DistributedDDLError) Cause() error {
+	return e.cause
+}
+
+// IsPartiallyApplied reports whether err is a statement ON CLUSTER that succeeded on some hosts only.
+func IsPartiallyApplied(err error) bool {
+	return clickhouseclient.FindInChain(err, func(e error) bool {
+		ddlErr, ok := e.(*DistributedDDLError)
+		return ok && len(ddlErr.Succeeded) > 0
+	})
+}
+
+// newDistributedDDLError sorts results by outcome, returning nil if every host succeeded.
+func newDistributedDDLError(clusterName string, results []DDLHostResult, pending []DDLHostResult, cause error) *DistributedDDLError {
+	ret := &DistributedDDLError{Cluster: clusterName, Pending: pending, cause: cause}
+	for _, r := range results {
+		if r.Error == "" {
+			ret.Succeeded = append(ret.Succeeded, r)
+		} else {
+			ret.Failed = append(ret.Failed, r)
+		}
+	}
+
+	if len(ret.Failed) == 0 && len(ret.Pending) == 0 {
+		return nil
+	}
+
+	return ret
+}
+
+// exec runs a statement changing the server. Statements ON CLUSTER are run as queries to capture the
+// result of every host; a *DistributedDDLError is returned if some host failed or did not finish.
+func (i *impl) exec(ctx context.Context, sql string, params map[string]string, clusterName *string) error {
+	if i.snapshots != nil {
+		// Invalidate even on failure: the statement may have been applied before the error.
+		defer i.snapshots.invalidate()
+	}
+
+	if clusterName == nil {
+		return i.clickhouseClient.Exec(ctx, sql, params)
+	}
+
+	results := make([]DDLHostResult, 0)
+	err := i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		result, err := scanDDLResult(data)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+		return nil
+	}, params)
+
+	entry, timedOut := ddlTimeoutEntry(err)
+	if !timedOut {
+		if len(results) == 0 {
+			// Failed before any host reported, or the server does not return per-host results.
+			return err
+		}
+		if ddlErr := newDistributedDDLError(*clusterName, results, nil, err); ddlErr != nil {
+			return ddlErr
+		}
+		return err
+	}
+
+	tflog.Warn(ctx, "Distributed DDL timed out, waiting for the remaining hosts", map[string]any{
+		"cluster": *clusterName,
+		"entry":   entry,
+	})
+
+	finished, pending, pollErr := i.waitDDLQueue(ctx, *clusterName, entry)
+	if pollErr != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error waiting for the distributed DDL task %s: %v", entry, pollErr))
+	}
+	if len(finished) == 0 && len(pending) == 0 {
+		// The entry was already cleaned up from the queue, or the queue cannot be read: the outcome is unknown.
+		return err
+	}
+
+	if ddlErr := newDistributedDDLError(*clusterName, finished, pending, err); ddlErr != nil {
+		return ddlErr
+	}
+
+	return nil
+}
+
+// scanDDLResult reads a row of the result of a statement ON CLUSTER.
+func scanDDLResult(data clickhouseclient.Row) (DDLHostResult, error) {
+	host, err := data.GetString("host")
+	if err != nil {
+		return DDLHostResult{}, errors.WithMessage(err, "error scanning query result, missing 'host' field")
+	}
+	port, err := data.GetUInt64("port")
+	if err != nil {
+		return DDLHostResult{}, errors.WithMessage(err, "error scanning query result, missing 'port' field")
+	}
+	status, err := data.GetInt64("status")
+	if err != nil {
+		return DDLHostResult{}, errors.WithMessage(err, "error scanning query result, missing 'status' field")
+	}
+	message, err := data.GetNullableString("error")
+	if err != nil {
+		return DDLHostResult{}, errors.WithMessage(err, "error scanning query result, missing 'error' field")
+	}
+
+	result := DDLHostResult{Host: host, Port: port}
+	if status != 0 {
+		result.Error = fmt.Sprintf("Code: %d", status)
+		if message != nil && *message != "" {
+			result.Error = *message
+		}
+	}
+
+	return result, nil
+}
+
+// ddlTimeoutEntry returns the queue entry of a distributed DDL task err reports as timed out.
+func ddlTimeoutEntry(err error) (string, bool) {
+	if !hasExceptionCode(err, timeoutExceededCode, "TIMEOUT_EXCEEDED") {
+		return "", false
+	}
+
+	match := ddlTaskRegex.FindStringSubmatch(err.Error())
+	if match == nil {
+		return "", false
+	}
+
+	return match[1], true
+}
+
+// waitDDLQueue polls system.distributed_ddl_queue until every host finished entry, returning the
+// finished hosts and the ones still pending when ddlQueuePollTimeout or ctx expires. When the queue
+// has no rows for entry, the hosts seen in the previous poll are returned, if any.
+func (i *impl) waitDDLQueue(ctx context.Context, clusterName string, entry string) ([]DDLHostResult, []DDLHostResult, error) {
+	pollCtx, cancel := context.WithTimeout(ctx, ddlQueuePollTimeout)
+	defer cancel()
+
+	var seenFinished, seenPending []DDLHostResult
+
+	backoff := ddlQueuePollInitialBackoff
+	for {
+		finished, pending, err := i.queryDDLQueue(pollCtx, clusterName, entry)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(pending) == 0 && len(finished) == 0 {
+			return seenFinished, seenPending, nil
+		}
+		if len(pending) == 0 {
+			return finished, nil, nil
+		}
+		seenFinished, seenPending = finished, pending
+
+		tflog.Debug(ctx, "Distributed DDL task not finished on all hosts", map[string]any{
+			"entry":   entry,
+			"pending": len(pending),
+			"backoff": backoff.String(),
+		})
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-pollCtx.Done():
+			timer.Stop()
+			return finished, pending, nil
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, ddlQueuePollMaxBackoff)
+	}
+}
+
+func (i *impl) queryDDLQueue(ctx context.Context, clusterName string, entry string) ([]DDLHostResult, []DDLHostResult, error) {
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("host"),
+			querybuilder.NewField("port"),
+			querybuilder.NewField("status").ToString(),
+			querybuilder.NewRawField("ifNull(exception_code, 0)", "exception_number"),
+			querybuilder.NewRawField("ifNull(exception_text, '')", "exception_message"),
+		},
+		"system.distributed_ddl_queue",
+	).Where(
+		querybuilder.WhereEquals("entry", entry),
+		querybuilder.WhereEquals("cluster", clusterName),
+	)
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error building query")
+	}
+
+	finished := make([]DDLHostResult, 0)
+	pending := make([]DDLHostResult, 0)
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		host, err := data.GetString("host")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'host' field")
+		}
+		port, err := data.GetUInt64("port")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'port' field")
+		}
+		status, err := data.GetString("status")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'status' field")
+		}
+		code, err := data.GetUInt64("exception_number")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'exception_number' field")
+		}
+		message, err := data.GetString("exception_message")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'exception_message' field")
+		}
+
+		result := DDLHostResult{Host: host, Port: port}
+		if status != "Finished" {
+			pending = append(pending, result)
+			return nil
+		}
+		if code != 0 {
+			result.Error = fmt.Sprintf("Code: %d", code)
+			if message != "" {
+				result.Error = message
+			}
+		}
+		finished = append(finished, result)
+
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error running query")
+	}
+
+	return finished, pending, nil
+}
+
+// partiallyCreated handles the error of a CREATE statement. When the statement was applied on some
+// hosts of the cluster, the entity is looked up with find and returned along with the error, so that
+// the caller can track it for replacement instead of leaving it behind.
+func partiallyCreated[T any](err error, find func() (*T, error)) (*T, error) {
+	wrapped := errors.WithMessage(err, "error running query")
+	if !IsPartiallyApplied(err) {
+		return nil, wrapped
+	}
+
+	created, findErr := find()
+	if findErr != nil || created == nil {
+		return nil, wrapped
+	}
+
+	return created, wrapped
+}
@@ -0,0 +1,209 @@
+package dbops
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+type ddlResponse struct {
+	rows []map[string]any
+	err  error
+}
+
+// ddlClient answers every SELECT, including statements ON CLUSTER, with the next scripted response.
+type ddlClient struct {
+	responses []ddlResponse
+	queries   []string
+	execs     int
+	// executed are the statements run by Exec, in order.
+	executed []string
+}
+
+func (c *ddlClient) Exec(_ context.Context, sql string, _ ...map[string]string) error {
+	c.execs++
+	c.executed = append(c.executed, sql)
+	return nil
+}
+
+func (c *ddlClient) Select(_ context.Context, sql string, callback func(clickhouseclient.Row) error, _ ...map[string]string) error {
+	c.queries = append(c.queries, sql)
+	if len(c.responses) == 0 {
+		return errors.New("unexpected query: " + sql)
+	}
+
+	response := c.responses[0]
+	c.responses = c.responses[1:]
+
+	for _, fields := range response.rows {
+		row := clickhouseclient.Row{}
+		for field, value := range fields {
+			row.Set(field, value)
+		}
+		if err := callback(row); err != nil {
+			return err
+		}
+	}
+
+	return response.err
+}
+
+func ddlRow(host string, status int64, message string) map[string]any {
+	return map[string]any{"host": host, "port": uint64(9000), "status": status, "error": message, "num_hosts_remaining": uint64(0), "num_hosts_active": uint64(0)}
+}
+
+func queueRow(host string, status string, code uint64, message string) map[string]any {
+	return map[string]any{"host": host, "port": uint64(9000), "status": status, "exception_number": code, "exception_message": message}
+}
+
+func Test_exec(t *testing.T) {
+	cluster := "default"
+	timeout := errors.New("Code: 159. DB::Exception: Distributed DDL task /clickhouse/task_queue/ddl/query-0000000012 is not finished on 1 of 2 hosts (0 of them are currently executing the task, 0 are inactive). (TIMEOUT_EXCEEDED)")
+	hostFailure := errors.New("Code: 493. DB::Exception: There was an error on [ch-2:9000]: role `r` already exists. (ACCESS_ENTITY_ALREADY_EXISTS)")
+
+	tests := []struct {
+		name        string
+		clusterName *string
+		responses   []ddlResponse
+		wantErr     bool
+		want        *DistributedDDLError
+		wantPartial bool
+		wantExecs   int
+	}{
+		{
+			name:      "Without cluster",
+			wantExecs: 1,
+		},
+		{
+			name:        "Succeeded on all hosts",
+			clusterName: &cluster,
+			responses: []ddlResponse{
+				{rows: []map[string]any{ddlRow("ch-1", 0, ""), ddlRow("ch-2", 0, "")}},
+			},
+		},
+		{
+			name:        "Failed on one host",
+			clusterName: &cluster,
+			responses: []ddlResponse{
+				{rows: []map[string]any{ddlRow("ch-1", 0, ""), ddlRow("ch-2", 493, "role `r` already exists")}, err: hostFailure},
+			},
+			wantErr: true,
+			want: &DistributedDDLError{
+				Cluster:   cluster,
+				Succeeded: []DDLHostResult{{Host: "ch-1", Port: 9000}},
+				Failed:    []DDLHostResult{{Host: "ch-2", Port: 9000, Error: "role `r` already exists"}},
+			},
+			wantPartial: true,
+		},
+		{
+			name:        "Failed before any host reported",
+			clusterName: &cluster,
+			responses: []ddlResponse{
+				{err: errors.New("Code: 62. DB::Exception: Syntax error. (SYNTAX_ERROR)")},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "Timed out, then finished",
+			clusterName: &cluster,
+			responses: []ddlResponse{
+				{rows: []map[string]any{ddlRow("ch-1", 0, "")}, err: timeout},
+				{rows: []map[string]any{queueRow("ch-1", "Finished", 0, ""), queueRow("ch-2", "Active", 0, "")}},
+				{rows: []map[string]any{queueRow("ch-1", "Finished", 0, ""), queueRow("ch-2", "Finished", 0, "")}},
+			},
+		},
+		{
+			name:        "Timed out, then failed",
+			clusterName: &cluster,
+			responses: []ddlResponse{
+				{rows: []map[string]any{ddlRow("ch-1", 0, "")}, err: timeout},
+				{rows: []map[string]any{queueRow("ch-1", "Finished", 0, ""), queueRow("ch-2", "Finished", 36, "bad arguments")}},
+			},
+			wantErr: true,
+			want: &DistributedDDLError{
+				Cluster:   cluster,
+				Succeeded: []DDLHostResult{{Host: "ch-1", Port: 9000}},
+				Failed:    []DDLHostResult{{Host: "ch-2", Port: 9000, Error: "bad arguments"}},
+			},
+			wantPartial: true,
+		},
+		{
+			name:        "Timed out, entry not in the queue",
+			clusterName: &cluster,
+			responses: []ddlResponse{
+				{rows: []map[string]any{ddlRow("ch-1", 0, "")}, err: timeout},
+				{},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &ddlClient{responses: tt.responses}
+			i := &impl{clickhouseClient: fake}
+
+			err := i.exec(context.Background(), "CREATE ROLE `r`", nil, tt.clusterName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("exec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var got *DistributedDDLError
+			if clickhouseclient.FindInChain(err, func(e error) bool {
+				got, _ = e.(*DistributedDDLError)
+				return got != nil
+			}) {
+				got.cause = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("exec() want = %+v, got %+v", tt.want, got)
+			}
+			if partial := IsPartiallyApplied(err); partial != tt.wantPartial {
+				t.Errorf("IsPartiallyApplied() want = %v, got %v", tt.wantPartial, partial)
+			}
+			if fake.execs != tt.wantExecs {
+				t.Errorf("want %d execs, got %d", tt.wantExecs, fake.execs)
+			}
+			if len(fake.responses) != 0 {
+				t.Errorf("%d scripted responses were not used", len(fake.responses))
+			}
+		})
+	}
+}
+
+func Test_ddlTimeoutEntry(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "Distributed DDL timeout",
+			err:    errors.New("Code: 159. DB::Exception: Distributed DDL task /clickhouse/task_queue/ddl/query-0000000012 is not finished on 1 of 3 hosts. (TIMEOUT_EXCEEDED)"),
+			want:   "query-0000000012",
+			wantOk: true,
+		},
+		{
+			name: "Other timeout",
+			err:  errors.New("Code: 159. DB::Exception: Timeout exceeded: elapsed 10 seconds. (TIMEOUT_EXCEEDED)"),
+		},
+		{
+			name: "Other error",
+			err:  errors.New("Code: 62. DB::Exception: Syntax error. (SYNTAX_ERROR)"),
+		},
+		{
+			name: "No error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ddlTimeoutEntry(tt.err)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ddlTimeoutEntry() want = %q, %v, got %q, %v", tt.want, tt.wantOk, got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+)
+
+// ClusterMode controls when the default cluster applies to a resource without its own cluster name.
+type ClusterMode string
+
+const (
+	// ClusterModeAlways runs every statement ON CLUSTER and reads through cluster().
+	ClusterModeAlways ClusterMode = "always"
+	// ClusterModeAuto only uses the cluster where the server does not replicate the entity itself:
+	// access entities unless they are in replicated storage, and databases on clusters with more
+	// than one host.
+	ClusterModeAuto ClusterMode = "auto"
+)
+
+// AvailableClusterModes lists the valid values of ClusterMode.
+var AvailableClusterModes = []string{string(ClusterModeAlways), string(ClusterModeAuto)}
+
+type defaultCluster struct {
+	name string
+	mode ClusterMode
+}
+
+// WithDefaultCluster sets the cluster used by resources that do not set a cluster name of their own.
+func WithDefaultCluster(name string, mode ClusterMode) ClientOption {
+	return func(i *impl) {
+		i.defaultCluster = &defaultCluster{name: name, mode: mode}
+	}
+}
+
+// accessCluster returns the cluster for statements on users, roles, grants, row policies and
+// settings profiles: clusterName if set, otherwise the default cluster if it applies.
+func (i *impl) accessCluster(ctx context.Context, clusterName *string) (*string, error) {
+	if clusterName != nil || i.defaultCluster == nil {
+		return clusterName, nil
+	}
+
+	if i.defaultCluster.mode == ClusterModeAuto {
+		replicated, err := i.IsReplicatedStorage(ctx)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error checking the storage of access entities")
+		}
+		if replicated {
+			// All replicas share the entities already, ON CLUSTER would fail.
+			return nil, nil
+		}
+	}
+
+	return &i.defaultCluster.name, nil
+}
+
+// databaseCluster returns the cluster for statements on databases: clusterName if set, otherwise
+// the default cluster if it applies.
+func (i *impl) databaseCluster(ctx context.Context, clusterName *string) (*string, error) {
+	if clusterName != nil || i.defaultCluster == nil {
+		return clusterName, nil
+	}
+
+	if i.defaultCluster.mode == ClusterModeAuto {
+		cluster, err := i.GetCluster(ctx, i.defaultCluster.name)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error getting the cluster topology")
+		}
+		if cluster == nil {
+			return nil, errors.New(fmt.Sprintf("cluster %q is not defined on the server", i.defaultCluster.name))
+		}
+		if len(cluster.Hosts) < 2 {
+			return nil, nil
+		}
+	}
+
+	return &i.defaultCluster.name, nil
+}
@@ -0,0 +1,110 @@
+package dbops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/nilcompare"
+)
+
+func TestDefaultCluster(t *testing.T) {
+	replicated := []map[string]any{
+		{"type": "local_directory", "precedence": uint64(1)},
+		{"type": "replicated", "precedence": uint64(0)},
+	}
+	local := []map[string]any{
+		{"type": "local_directory", "precedence": uint64(0)},
+	}
+	twoHosts := []map[string]any{
+		{"shard_num": uint64(1), "replica_num": uint64(1), "host_name": "ch-1", "port": uint64(9000)},
+		{"shard_num": uint64(1), "replica_num": uint64(2), "host_name": "ch-2", "port": uint64(9000)},
+	}
+	oneHost := twoHosts[:1]
+
+	tests := []struct {
+		name            string
+		options         []ClientOption
+		clusterName     *string
+		directories     []map[string]any
+		hosts           []map[string]any
+		wantAccess      *string
+		wantDatabase    *string
+		wantDatabaseErr bool
+	}{
+		{
+			name:        "No default cluster",
+			directories: local,
+			hosts:       twoHosts,
+		},
+		{
+			name:         "Resource cluster wins",
+			options:      []ClientOption{WithDefaultCluster("main", ClusterModeAuto)},
+			clusterName:  new("other"),
+			directories:  replicated,
+			hosts:        oneHost,
+			wantAccess:   new("other"),
+			wantDatabase: new("other"),
+		},
+		{
+			name:         "Always",
+			options:      []ClientOption{WithDefaultCluster("main", ClusterModeAlways)},
+			directories:  replicated,
+			hosts:        oneHost,
+			wantAccess:   new("main"),
+			wantDatabase: new("main"),
+		},
+		{
+			name:         "Auto with replicated storage on many hosts",
+			options:      []ClientOption{WithDefaultCluster("main", ClusterModeAuto)},
+			directories:  replicated,
+			hosts:        twoHosts,
+			wantDatabase: new("main"),
+		},
+		{
+			name:        "Auto with local storage on a single host",
+			options:     []ClientOption{WithDefaultCluster("main", ClusterModeAuto)},
+			directories: local,
+			hosts:       oneHost,
+			wantAccess:  new("main"),
+		},
+		{
+			name:            "Auto with undefined cluster",
+			options:         []ClientOption{WithDefaultCluster("main", ClusterModeAuto)},
+			directories:     local,
+			wantAccess:      new("main"),
+			wantDatabaseErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := &tableClient{
+				tables: map[snapshotTable][]map[string]any{
+					"system.user_directories": tt.directories,
+					"system.clusters":         tt.hosts,
+				},
+			}
+			client, err := NewClient(fake, tt.options...)
+			if err != nil {
+				t.Fatalf("NewClient returned error: %v", err)
+			}
+			i := client.(*impl)
+
+			gotAccess, err := i.accessCluster(ctx, tt.clusterName)
+			if err != nil {
+				t.Fatalf("accessCluster() error = %v", err)
+			}
+			if !nilcompare.NilCompare(gotAccess, tt.wantAccess) {
+				t.Errorf("accessCluster() want = %v, got %v", tt.wantAccess, gotAccess)
+			}
+
+			gotDatabase, err := i.databaseCluster(ctx, tt.clusterName)
+			if (err != nil) != tt.wantDatabaseErr {
+				t.Fatalf("databaseCluster() error = %v, wantErr %v", err, tt.wantDatabaseErr)
+			}
+			if !nilcompare.NilCompare(gotDatabase, tt.wantDatabase) {
+				t.Errorf("databaseCluster() want = %v, got %v", tt.wantDatabase, gotDatabase)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+type Dictionary struct {
+	Database string
+	Name     string
+	// Keys are the attributes making the primary key of the dictionary.
+	Keys       []DictionaryAttribute
+	Attributes []DictionaryAttribute
+	// Source is not returned by GetDictionary.
+	Source DictionaryFunction
+	// Only the name of the layout, such as `ComplexKeyHashed`, is returned by GetDictionary.
+	Layout DictionaryFunction
+	// LifetimeMin and LifetimeMax are the update interval of the dictionary, in seconds.
+	LifetimeMin *int64
+	LifetimeMax *int64
+	// RangeMin, RangeMax and Settings are not returned by GetDictionary.
+	RangeMin *string
+	RangeMax *string
+	Settings map[string]string
+	Comment  string
+}
+
+type DictionaryAttribute struct {
+	Name string
+	Type string
+	// Default, Expression and the flags are not returned by GetDictionary.
+	Default      *string
+	Expression   *string
+	Hierarchical bool
+	Injective    bool
+	IsObjectID   bool
+}
+
+func (a DictionaryAttribute) definition() querybuilder.DictionaryAttribute {
+	return querybuilder.DictionaryAttribute{
+		Name:         a.Name,
+		Type:         a.Type,
+		Default:      a.Default,
+		Expression:   a.Expression,
+		Hierarchical: a.Hierarchical,
+		Injective:    a.Injective,
+		IsObjectID:   a.IsObjectID,
+	}
+}
+
+// DictionaryFunction is the source or the layout of a dictionary, such as `CLICKHOUSE` with its
+// `host` and `port` parameters.
+type DictionaryFunction struct {
+	Name       string
+	Parameters map[string]string
+}
+
+func (f DictionaryFunction) definition() querybuilder.DictionaryFunction {
+	return querybuilder.DictionaryFunction{
+		Name:       f.Name,
+		Parameters: f.Parameters,
+	}
+}
+
+func (i *impl) CreateDictionary(ctx context.Context, dictionary Dictionary, clusterName *string) (*Dictionary, error) {
+	return i.createDictionary(ctx, dictionary, false, clusterName)
+}
+
+// UpdateDictionary replaces the dictionary atomically, which keeps the grants on it.
+func (i *impl) UpdateDictionary(ctx context.Context, dictionary Dictionary, clusterName *string) (*Dictionary, error) {
+	return i.createDictionary(ctx, dictionary, true, clusterName)
+}
+
+func (i *impl) createDictionary(ctx context.Context, dictionary Dictionary, orReplace bool, clusterName *string) (*Dictionary, error) {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]querybuilder.DictionaryAttribute, 0, len(dictionary.Keys))
+	for _, k := range dictionary.Keys {
+		keys = append(keys, k.definition())
+	}
+	attributes := make([]querybuilder.DictionaryAttribute, 0, len(dictionary.Attributes))
+	for _, a := range dictionary.Attributes {
+		attributes = append(attributes, a.definition())
+	}
+
+	builder := querybuilder.NewCreateDictionary(dictionary.Database, dictionary.Name).
+		WithCluster(clusterName).
+		WithOrReplace(orReplace).
+		WithKeys(keys).
+		WithAttributes(attributes).
+		WithSource(dictionary.Source.definition()).
+		WithLayout(dictionary.Layout.definition()).
+		WithLifetime(dictionary.LifetimeMin, dictionary.LifetimeMax).
+		WithRange(dictionary.RangeMin, dictionary.RangeMax).
+		WithSettings(dictionary.Settings).
+		WithComment(nonEmpty(dictionary.Comment))
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		if orReplace {
+			return nil, errors.WithMessage(err, "error running query")
+		}
+		return partiallyCreated(err, func() (*Dictionary, error) {
+			return i.GetDictionary(ctx, dictionary.Database, dictionary.Name, clusterName)
+		})
+	}
+
+	return i.GetDictionary(ctx, dictionary.Database, dictionary.Name, clusterName)
+}
+
+// GetDictionary returns the dictionary, or nil if it does not exist. Like tables, dictionaries are
+// read from the replica hit by the query.
+// The layout and the lifetime are only known once the dictionary is loaded: until then they are
+// returned empty.
+func (i *impl) GetDictionary(ctx context.Context, database string, name string, _ *string) (*Dictionary, error) {
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("type"),
+			querybuilder.NewRawField("`key.names`", "key_names"),
+			querybuilder.NewRawField("`key.types`", "key_types"),
+			querybuilder.NewRawField("`attribute.names`", "attribute_names"),
+			querybuilder.NewRawField("`attribute.types`", "attribute_types"),
+			querybuilder.NewField("lifetime_min"),
+			querybuilder.NewField("lifetime_max"),
+			querybuilder.NewField("comment"),
+		},
+		"system.dictionaries",
+	).Where(
+		querybuilder.WhereEquals("database", database),
+		querybuilder.WhereEquals("name", name),
+	)
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var dictionary *Dictionary
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		layout, err := data.GetString("type")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'type' field")
+		}
+		keys, err := scanDictionaryAttributes(data, "key")
+		if err != nil {
+			return err
+		}
+		attributes, err := scanDictionaryAttributes(data, "attribute")
+		if err != nil {
+			return err
+		}
+		lifetimeMin, err := data.GetUInt64("lifetime_min")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'lifetime_min' field")
+		}
+		lifetimeMax, err := data.GetUInt64("lifetime_max")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'lifetime_max' field")
+		}
+		comment, err := data.GetString("comment")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'comment' field")
+		}
+
+		dictionary = &Dictionary{
+			Database:   database,
+			Name:       name,
+			Keys:       keys,
+			Attributes: attributes,
+			Layout:     DictionaryFunction{Name: layout},
+			Comment:    comment,
+		}
+		if layout != "" {
+			dictionary.LifetimeMin = new(int64(lifetimeMin))
+			dictionary.LifetimeMax = new(int64(lifetimeMax))
+		}
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return dictionary, nil
+}
+
+func (i *impl) DeleteDictionary(ctx context.Context, database string, name string, clusterName *string) error {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	builder := querybuilder.NewDropDictionary(database, name).WithCluster(clusterName)
+	sql, err := builder.Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// scanDictionaryAttributes returns the names and types of the keys or attributes of a dictionary.
+func scanDictionaryAttributes(data clickhouseclient.Row, prefix string) ([]DictionaryAttribute, error) {
+	names, err := data.GetStringArray(prefix + "_names")
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error scanning query result, missing '%s_names' field", prefix))
+	}
+	types, err := data.GetStringArray(prefix + "_types")
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error scanning query result, missing '%s_types' field", prefix))
+	}
+	if len(names) != len(types) {
+		return nil, errors.Errorf("got %d %s names and %d types", len(names), prefix, len(types))
+	}
+
+	attributes := make([]DictionaryAttribute, 0, len(names))
+	for idx := range names {
+		attributes = append(attributes, DictionaryAttribute{Name: names[idx], Type: types[idx]})
+	}
+
+	return attributes, nil
+}
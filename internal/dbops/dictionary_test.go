@@ -0,0 +1,80 @@
+package dbops
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGetDictionary(t *testing.T) {
+	row := func(layout string) map[string]any {
+		return map[string]any{
+			"type":            layout,
+			"key_names":       []string{"id"},
+			"key_types":       []string{"UInt64"},
+			"attribute_names": []string{"name", "parent_id"},
+			"attribute_types": []string{"String", "UInt64"},
+			"lifetime_min":    uint64(300),
+			"lifetime_max":    uint64(600),
+			"comment":         "countries",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		rows    []map[string]any
+		want    *Dictionary
+		wantErr bool
+	}{
+		{
+			name: "Loaded",
+			rows: []map[string]any{row("Hashed")},
+			want: &Dictionary{
+				Database:    "db",
+				Name:        "countries",
+				Keys:        []DictionaryAttribute{{Name: "id", Type: "UInt64"}},
+				Attributes:  []DictionaryAttribute{{Name: "name", Type: "String"}, {Name: "parent_id", Type: "UInt64"}},
+				Layout:      DictionaryFunction{Name: "Hashed"},
+				LifetimeMin: new(int64(300)),
+				LifetimeMax: new(int64(600)),
+				Comment:     "countries",
+			},
+		},
+		{
+			name: "Not loaded",
+			rows: []map[string]any{row("")},
+			want: &Dictionary{
+				Database:   "db",
+				Name:       "countries",
+				Keys:       []DictionaryAttribute{{Name: "id", Type: "UInt64"}},
+				Attributes: []DictionaryAttribute{{Name: "name", Type: "String"}, {Name: "parent_id", Type: "UInt64"}},
+				Comment:    "countries",
+			},
+		},
+		{
+			name: "Not found",
+		},
+		{
+			name: "Mismatched attribute types",
+			rows: []map[string]any{func() map[string]any {
+				r := row("Hashed")
+				r["attribute_types"] = []string{"String"}
+				return r
+			}()},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &impl{clickhouseClient: &ddlClient{responses: []ddlResponse{{rows: tt.rows}}}}
+
+			got, err := i.GetDictionary(context.Background(), "db", "countries", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDictionary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDictionary() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,50 +1,45 @@
 package dbops
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
 )
 
 // ClickHouse error code 493 (ACCESS_ENTITY_ALREADY_EXISTS).
 const accessEntityAlreadyExistsCode = 493
 
-// findInChain follows both Cause() and Unwrap() chains: pingcap/errors wrappers implement only Cause(), so stdlib errors.Is/As cannot traverse them.
-func findInChain(err error, match func(error) bool) bool {
-	for err != nil {
-		if match(err) {
-			return true
-		}
-
-		switch x := err.(type) {
-		case interface{ Cause() error }:
-			err = x.Cause()
-		case interface{ Unwrap() error }:
-			err = x.Unwrap()
-		default:
-			return false
-		}
-	}
-
-	return false
-}
+// ClickHouse error code 82 (DATABASE_ALREADY_EXISTS).
+const databaseAlreadyExistsCode = 82
 
 // isAlreadyExistsError reports whether err represents ClickHouse error code 493 (ACCESS_ENTITY_ALREADY_EXISTS).
 func isAlreadyExistsError(err error) bool {
+	return hasExceptionCode(err, accessEntityAlreadyExistsCode, "ACCESS_ENTITY_ALREADY_EXISTS")
+}
+
+// isDatabaseAlreadyExistsError reports whether err represents ClickHouse error code 82 (DATABASE_ALREADY_EXISTS).
+func isDatabaseAlreadyExistsError(err error) bool {
+	return hasExceptionCode(err, databaseAlreadyExistsCode, "DATABASE_ALREADY_EXISTS")
+}
+
+func hasExceptionCode(err error, code int32, name string) bool {
 	if err == nil {
 		return false
 	}
 
-	typed := findInChain(err, func(e error) bool {
+	typed := clickhouseclient.FindInChain(err, func(e error) bool {
 		ex, ok := e.(*clickhouse.Exception)
-		return ok && ex.Code == accessEntityAlreadyExistsCode
+		return ok && ex.Code == code
 	})
 	if typed {
 		return true
 	}
 
-	// The HTTP client returns the raw response body as an opaque error, so the code is only matchable as text.
+	// Exceptions ClickHouse reports after it started streaming an HTTP response are only available as text.
 	msg := err.Error()
-	return strings.Contains(msg, "Code: 493.") ||
-		strings.Contains(msg, "ACCESS_ENTITY_ALREADY_EXISTS")
+	return strings.Contains(msg, fmt.Sprintf("Code: %d.", code)) ||
+		strings.Contains(msg, name)
 }
@@ -14,13 +14,19 @@ import (
 
 // Retrieves the ClickHouse version returns it as a CHVersion struct.
 func (i *impl) GetVersion(ctx context.Context) (string, error) {
-	sql, err := querybuilder.NewSelect(
+	return i.version.get(func() (string, error) {
+		return i.queryVersion(ctx)
+	})
+}
+
+func (i *impl) queryVersion(ctx context.Context) (string, error) {
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{
 			querybuilder.NewField("value"),
 		},
 		"system.build_options").
-		Where(querybuilder.WhereEquals("name", "VERSION_FULL")).
-		Build()
+		Where(querybuilder.WhereEquals("name", "VERSION_FULL"))
+	sql, err := builder.Build()
 	if err != nil {
 		return "", errors.WithMessage(err, "error building query")
 	}
@@ -33,7 +39,7 @@ func (i *impl) GetVersion(ctx context.Context) (string, error) {
 			return errors.WithMessage(err, "error scanning query result")
 		}
 		return nil
-	})
+	}, builder.Parameters())
 	if err != nil {
 		return "", errors.WithMessage(err, "error running query")
 	}
@@ -42,6 +42,10 @@ type GrantPrivilege struct {
 	GranteeUserName *string `json:"user_name"`
 	GranteeRoleName *string `json:"role_name"`
 	GrantOption     bool    `json:"grant_option"`
+	// NamedCollection and TableEngine are the targets of NAMED_COLLECTION and TABLE_ENGINE scoped
+	// privileges. Like AccessObject, system.grants lists them in its access_object column.
+	NamedCollection *string `json:"-"`
+	TableEngine     *string `json:"-"`
 	// ExpandedAccessTypes includes AccessType and all its descendants.
 	// ClickHouse may expand a parent privilege (e.g. CREATE, ACCESS MANAGEMENT)
 	// into its children in system.grants instead of storing a single parent row.
@@ -60,15 +64,34 @@ func (g GrantPrivilege) AsGrant() grants.Grant {
 		Database:     g.DatabaseName,
 		Table:        g.TableName,
 		Column:       g.ColumnName,
-		AccessObject: g.AccessObject,
+		AccessObject: g.parameter(),
 		GrantOption:  g.GrantOption,
 	}
 }
 
-// Defines the signature for a function that checks if privileges are granted.
-type MatcherFunc func(ctx context.Context, priv *GrantPrivilege, clusterName *string, i *impl) (bool, error)
+// parameter returns the target of a privilege scoped to an access object, a named collection or a
+// table engine, as found in the access_object column of system.grants.
+func (g GrantPrivilege) parameter() *string {
+	switch {
+	case g.NamedCollection != nil:
+		return g.NamedCollection
+	case g.TableEngine != nil:
+		return g.TableEngine
+	default:
+		return g.AccessObject
+	}
+}
+
+// Defines the signature for a function that checks if privileges are granted. It returns the grant
+// with the grant option found on the server, or nil if it is not granted.
+type MatcherFunc func(ctx context.Context, priv *GrantPrivilege, clusterName *string, i *impl) (*GrantPrivilege, error)
 
 func (i *impl) GrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege, clusterName *string) (*GrantPrivilege, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	var to string
 	{
 		if grantPrivilege.GranteeUserName != nil {
@@ -80,31 +103,26 @@ func (i *impl) GrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege
 		}
 	}
 
-	sql, err := querybuilder.GrantPrivilege(grantPrivilege.AccessType, to).
+	builder := querybuilder.GrantPrivilege(grantPrivilege.AccessType, to).
 		WithDatabase(grantPrivilege.DatabaseName).
 		WithTable(grantPrivilege.TableName).
 		WithColumn(grantPrivilege.ColumnName).
 		WithAccessObject(grantPrivilege.AccessObject).
+		WithNamedCollection(grantPrivilege.NamedCollection).
+		WithTableEngine(grantPrivilege.TableEngine).
 		WithGrantOption(grantPrivilege.GrantOption).
 		WithCluster(clusterName).
-		WithCurrentGrants(grantPrivilege.CurrentGrants).
-		Build()
+		WithCurrentGrants(grantPrivilege.CurrentGrants)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	identifier := grantPrivilege.AccessType
-	if grantPrivilege.GranteeUserName != nil {
-		identifier += " to user " + *grantPrivilege.GranteeUserName
-	} else if grantPrivilege.GranteeRoleName != nil {
-		identifier += " to role " + *grantPrivilege.GranteeRoleName
-	}
-
 	// The grant succeeded. If a matching row is already visible we're done.
 	found, err := i.GetGrantPrivilege(ctx, &grantPrivilege, clusterName)
 	if err != nil {
@@ -123,19 +141,32 @@ func (i *impl) GrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege
 		return nil, nil
 	}
 
-	return retryWithBackoff(ctx, "grant privilege", identifier, func() (*GrantPrivilege, error) {
+	return retryWithBackoff(ctx, "grant privilege", grantPrivilege.identifier(), func(ctx context.Context) (*GrantPrivilege, error) {
 		return i.GetGrantPrivilege(ctx, &grantPrivilege, clusterName)
 	}, i.readAfterWriteTimeoutArgs()...)
 }
 
+// identifier names the grant in retry messages.
+func (g GrantPrivilege) identifier() string {
+	identifier := g.AccessType
+	if g.GranteeUserName != nil {
+		identifier += " to user " + *g.GranteeUserName
+	} else if g.GranteeRoleName != nil {
+		identifier += " to role " + *g.GranteeRoleName
+	}
+
+	return identifier
+}
+
 func (i *impl) isGrantCovered(ctx context.Context, grantPrivilege *GrantPrivilege, clusterName *string) (bool, error) {
 	existing, err := i.GetAllGrantsForGrantee(ctx, grantPrivilege.GranteeUserName, grantPrivilege.GranteeRoleName, clusterName)
 	if err != nil {
 		return false, err
 	}
 
+	catalog := i.PrivilegeCatalog(ctx)
 	for idx := range existing {
-		if grants.Covers(existing[idx].AsGrant(), grantPrivilege.AsGrant()) {
+		if catalog.Covers(existing[idx].AsGrant(), grantPrivilege.AsGrant()) {
 			return true, nil
 		}
 	}
@@ -143,7 +174,7 @@ func (i *impl) isGrantCovered(ctx context.Context, grantPrivilege *GrantPrivileg
 }
 
 // Matcher function to handle classic grants: https://clickhouse.com/docs/sql-reference/statements/grant#granting-privilege-syntax
-func ClassicGrantMatcher(ctx context.Context, priv *GrantPrivilege, clusterName *string, i *impl) (bool, error) {
+func ClassicGrantMatcher(ctx context.Context, priv *GrantPrivilege, clusterName *string, i *impl) (*GrantPrivilege, error) {
 	// ClickHouse stores wildcard (prefix) grants in system.grants with the
 	// trailing '*' stripped (e.g. GRANT ON dbt_*.* stores database='dbt_').
 	// See: https://github.com/ClickHouse/ClickHouse/issues/92835
@@ -155,7 +186,7 @@ func ClassicGrantMatcher(ctx context.Context, priv *GrantPrivilege, clusterName
 	if tblName != nil && strings.HasSuffix(*tblName, "*") {
 		tblName = new(strings.TrimSuffix(*tblName, "*"))
 	}
-	accessName := priv.AccessObject
+	accessName := priv.parameter()
 	if accessName != nil && strings.HasSuffix(*accessName, "*") {
 		stripped := strings.TrimSuffix(*accessName, "*")
 		accessName = &stripped
@@ -174,15 +205,29 @@ func ClassicGrantMatcher(ctx context.Context, priv *GrantPrivilege, clusterName
 		valOrEmptyString("access_object", accessName),
 		valOrNullWhere("column", priv.ColumnName),
 	}
+	// Snapshot rows hold access_object as access_object_nullable, where '' reads as NULL.
+	accessObjectNullable := accessName
+	if accessObjectNullable != nil && *accessObjectNullable == "" {
+		accessObjectNullable = nil
+	}
+	conditions := []rowCondition{
+		fieldIn("access_type", accessTypes),
+		fieldEqualsOrNull("database", dbName),
+		fieldEqualsOrNull("table", tblName),
+		fieldEqualsOrNull("access_object_nullable", accessObjectNullable),
+		fieldEqualsOrNull("column", priv.ColumnName),
+	}
 	if priv.GranteeUserName != nil {
 		where = append(where, querybuilder.WhereEquals("user_name", *priv.GranteeUserName))
+		conditions = append(conditions, fieldEquals("user_name", *priv.GranteeUserName))
 	} else if priv.GranteeRoleName != nil {
 		where = append(where, querybuilder.WhereEquals("role_name", *priv.GranteeRoleName))
+		conditions = append(conditions, fieldEquals("role_name", *priv.GranteeRoleName))
 	} else {
-		return false, errors.New("either GranteeUserName or GranteeRoleName must be set")
+		return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
 	}
 
-	sql, err := querybuilder.NewSelect(
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{
 			querybuilder.NewField("access_type").ToString(),
 			querybuilder.NewField("database"),
@@ -195,13 +240,16 @@ func ClassicGrantMatcher(ctx context.Context, priv *GrantPrivilege, clusterName
 			querybuilder.NewField("grant_option"),
 		},
 		"system.grants",
-	).WithCluster(clusterName).Where(where...).Build()
+	).WithCluster(clusterName).Where(where...)
+	sql, err := builder.Build()
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
+	// An expanded privilege has the grant option when all its rows have it.
 	found := false
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	grantOption := true
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotGrants, clusterName, conditions, func(data clickhouseclient.Row) error {
 		_, err = data.GetString("access_type")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'access_type' field")
@@ -230,26 +278,33 @@ func ClassicGrantMatcher(ctx context.Context, priv *GrantPrivilege, clusterName
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'role_name' field")
 		}
-		_, err = data.GetBool("grant_option")
+		option, err := data.GetBool("grant_option")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'grant_option' field")
 		}
 		found = true
+		grantOption = grantOption && option
 		return nil
 	})
 	if err != nil {
-		return false, err
+		return nil, err
 	}
+	if !found {
+		return nil, nil
+	}
+
+	matched := *priv
+	matched.GrantOption = grantOption
 
-	return found, nil
+	return &matched, nil
 }
 
 // Matcher function to handle sources grants, applied via `clickhousedbops_grant_privilege` resource with READ/WRITE access:
 // https://clickhouse.com/docs/sql-reference/statements/grant#sources
 // TODO: grants for sources should be refactored to use separate resource.
-func SourcesReadWriteGrantMatcher(ctx context.Context, priv *GrantPrivilege, clusterName *string, i *impl) (bool, error) {
+func SourcesReadWriteGrantMatcher(ctx context.Context, priv *GrantPrivilege, clusterName *string, i *impl) (*GrantPrivilege, error) {
 	if !sourcesFamily[priv.AccessType] {
-		return false, errors.New("incorrect query: sources matcher requires a source access type")
+		return nil, errors.New("incorrect query: sources matcher requires a source access type")
 	}
 	where := []querybuilder.Where{
 		querybuilder.WhereEquals("access_object", priv.AccessType),
@@ -259,15 +314,24 @@ func SourcesReadWriteGrantMatcher(ctx context.Context, priv *GrantPrivilege, clu
 		valOrNullWhere("table", priv.TableName),
 		valOrNullWhere("column", priv.ColumnName),
 	}
+	conditions := []rowCondition{
+		fieldEquals("access_object_nullable", priv.AccessType),
+		fieldIn("access_type", []string{"READ", "WRITE"}),
+		fieldEqualsOrNull("database", priv.DatabaseName),
+		fieldEqualsOrNull("table", priv.TableName),
+		fieldEqualsOrNull("column", priv.ColumnName),
+	}
 	if priv.GranteeUserName != nil {
 		where = append(where, querybuilder.WhereEquals("user_name", *priv.GranteeUserName))
+		conditions = append(conditions, fieldEquals("user_name", *priv.GranteeUserName))
 	} else if priv.GranteeRoleName != nil {
 		where = append(where, querybuilder.WhereEquals("role_name", *priv.GranteeRoleName))
+		conditions = append(conditions, fieldEquals("role_name", *priv.GranteeRoleName))
 	} else {
-		return false, errors.New("incorrect query: either user_name or role_name must be set")
+		return nil, errors.New("incorrect query: either user_name or role_name must be set")
 	}
 
-	sql, err := querybuilder.NewSelect(
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{
 			querybuilder.NewField("access_type").ToString(),
 			querybuilder.NewField("access_object"),
@@ -276,21 +340,34 @@ func SourcesReadWriteGrantMatcher(ctx context.Context, priv *GrantPrivilege, clu
 			querybuilder.NewField("grant_option"),
 		},
 		"system.grants",
-	).WithCluster(clusterName).Where(where...).Build()
+	).WithCluster(clusterName).Where(where...)
+	sql, err := builder.Build()
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	// We expect 2 rows for both READ and WRITE grants.
 	rowsCount := 0
-	err = i.clickhouseClient.Select(ctx, sql, func(_ clickhouseclient.Row) error {
+	grantOption := true
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotGrants, clusterName, conditions, func(data clickhouseclient.Row) error {
+		option, err := data.GetBool("grant_option")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'grant_option' field")
+		}
 		rowsCount++
+		grantOption = grantOption && option
 		return nil
 	})
 	if err != nil {
-		return false, err
+		return nil, err
 	}
+	if rowsCount != 2 {
+		return nil, nil
+	}
+
+	matched := *priv
+	matched.GrantOption = grantOption
 
-	return rowsCount == 2, nil
+	return &matched, nil
 }
 
 // Helper function: Null or value clause
@@ -310,6 +387,11 @@ func valOrEmptyString(field string, value *string) querybuilder.Where {
 }
 
 func (i *impl) GetGrantPrivilege(ctx context.Context, grantPrivilege *GrantPrivilege, clusterName *string) (*GrantPrivilege, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	var matcher MatcherFunc
 	capabilityFlags, err := i.GetCapabilityFlags(ctx)
 	if err != nil {
@@ -322,18 +404,15 @@ func (i *impl) GetGrantPrivilege(ctx context.Context, grantPrivilege *GrantPrivi
 		matcher = ClassicGrantMatcher
 	}
 
-	ok, err := matcher(ctx, grantPrivilege, clusterName, i)
-	if err != nil {
-		return nil, err
-	}
-	if !ok {
-		return nil, nil
-	}
-
-	return grantPrivilege, nil
+	return matcher(ctx, grantPrivilege, clusterName, i)
 }
 
 func (i *impl) RevokeGrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege, clusterName *string) error {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	var from string
 	{
 		switch {
@@ -346,18 +425,20 @@ func (i *impl) RevokeGrantPrivilege(ctx context.Context, grantPrivilege GrantPri
 		}
 	}
 
-	sql, err := querybuilder.RevokePrivilege(grantPrivilege.AccessType, from).
+	builder := querybuilder.RevokePrivilege(grantPrivilege.AccessType, from).
 		WithDatabase(grantPrivilege.DatabaseName).
 		WithTable(grantPrivilege.TableName).
 		WithColumn(grantPrivilege.ColumnName).
 		WithAccessObject(grantPrivilege.AccessObject).
-		WithCluster(clusterName).
-		Build()
+		WithNamedCollection(grantPrivilege.NamedCollection).
+		WithTableEngine(grantPrivilege.TableEngine).
+		WithCluster(clusterName)
+	sql, err := builder.Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
@@ -365,20 +446,92 @@ func (i *impl) RevokeGrantPrivilege(ctx context.Context, grantPrivilege GrantPri
 	return nil
 }
 
+// UpdateGrantPrivilege changes the grant option of an existing grant in place, without revoking the
+// privilege: it is added by granting the privilege again WITH GRANT OPTION, and removed with
+// REVOKE GRANT OPTION FOR.
+func (i *impl) UpdateGrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege, clusterName *string) (*GrantPrivilege, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var grantee string
+	{
+		switch {
+		case grantPrivilege.GranteeUserName != nil:
+			grantee = *grantPrivilege.GranteeUserName
+		case grantPrivilege.GranteeRoleName != nil:
+			grantee = *grantPrivilege.GranteeRoleName
+		default:
+			return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
+		}
+	}
+
+	var builder querybuilder.QueryBuilder
+	if grantPrivilege.GrantOption {
+		builder = querybuilder.GrantPrivilege(grantPrivilege.AccessType, grantee).
+			WithDatabase(grantPrivilege.DatabaseName).
+			WithTable(grantPrivilege.TableName).
+			WithColumn(grantPrivilege.ColumnName).
+			WithAccessObject(grantPrivilege.AccessObject).
+			WithNamedCollection(grantPrivilege.NamedCollection).
+			WithTableEngine(grantPrivilege.TableEngine).
+			WithGrantOption(true).
+			WithCluster(clusterName).
+			WithCurrentGrants(grantPrivilege.CurrentGrants)
+	} else {
+		builder = querybuilder.RevokePrivilege(grantPrivilege.AccessType, grantee).
+			WithDatabase(grantPrivilege.DatabaseName).
+			WithTable(grantPrivilege.TableName).
+			WithColumn(grantPrivilege.ColumnName).
+			WithAccessObject(grantPrivilege.AccessObject).
+			WithNamedCollection(grantPrivilege.NamedCollection).
+			WithTableEngine(grantPrivilege.TableEngine).
+			WithCluster(clusterName).
+			GrantOptionOnly()
+	}
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	// Wait for the grant to show the new grant option.
+	return retryWithBackoff(ctx, "grant privilege", grantPrivilege.identifier(), func(ctx context.Context) (*GrantPrivilege, error) {
+		updated, err := i.GetGrantPrivilege(ctx, &grantPrivilege, clusterName)
+		if err != nil || updated == nil || updated.GrantOption != grantPrivilege.GrantOption {
+			return nil, err
+		}
+		return updated, nil
+	}, i.readAfterWriteTimeoutArgs()...)
+}
+
 func (i *impl) GetAllGrantsForGrantee(ctx context.Context, granteeUsername *string, granteeRoleName *string, clusterName *string) ([]GrantPrivilege, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all grants for the same grantee.
 	where := []querybuilder.Where{querybuilder.WhereEquals("is_partial_revoke", 0)}
+	var conditions []rowCondition
 	{
 		if granteeUsername != nil {
 			where = append(where, querybuilder.WhereEquals("user_name", *granteeUsername))
+			conditions = append(conditions, fieldEquals("user_name", *granteeUsername))
 		} else if granteeRoleName != nil {
 			where = append(where, querybuilder.WhereEquals("role_name", *granteeRoleName))
+			conditions = append(conditions, fieldEquals("role_name", *granteeRoleName))
 		} else {
 			return nil, errors.New("either granteeUsername or GranteeRoleName must be set")
 		}
 	}
 
-	sql, err := querybuilder.NewSelect([]querybuilder.Field{
+	builder := querybuilder.NewSelect([]querybuilder.Field{
 		querybuilder.NewField("access_type").ToString(),
 		querybuilder.NewField("database"),
 		querybuilder.NewField("table"),
@@ -390,15 +543,15 @@ func (i *impl) GetAllGrantsForGrantee(ctx context.Context, granteeUsername *stri
 		querybuilder.NewField("grant_option"),
 	}, "system.grants").
 		WithCluster(clusterName).
-		Where(querybuilder.AndWhere(where...)).
-		Build()
+		Where(querybuilder.AndWhere(where...))
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	ret := make([]GrantPrivilege, 0)
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotGrants, clusterName, conditions, func(data clickhouseclient.Row) error {
 		accessType, err := data.GetString("access_type")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'access_type' field")
@@ -0,0 +1,65 @@
+package dbops
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func grantRow(grantOption bool) map[string]any {
+	return map[string]any{"access_type": "SELECT", "database": "db", "table": nil, "column": nil, "access_object_nullable": nil, "user_name": "alice", "role_name": nil, "grant_option": grantOption}
+}
+
+func TestUpdateGrantPrivilege(t *testing.T) {
+	tests := []struct {
+		name        string
+		grantOption bool
+		responses   []ddlResponse
+		wantQuery   string
+	}{
+		{
+			name:        "Add the grant option",
+			grantOption: true,
+			// The first read still shows the grant without the option.
+			responses: []ddlResponse{
+				{rows: []map[string]any{grantRow(false)}},
+				{rows: []map[string]any{grantRow(true)}},
+			},
+			wantQuery: "WITH GRANT OPTION",
+		},
+		{
+			name:        "Remove the grant option",
+			grantOption: false,
+			responses: []ddlResponse{
+				{rows: []map[string]any{grantRow(true)}},
+				{rows: []map[string]any{grantRow(false)}},
+			},
+			wantQuery: "GRANT OPTION FOR",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &ddlClient{responses: tt.responses}
+			i := &impl{clickhouseClient: fake, capabilities: memo[CapabilityFlags]{done: true}}
+
+			got, err := i.UpdateGrantPrivilege(context.Background(), GrantPrivilege{
+				AccessType:      "SELECT",
+				DatabaseName:    new("db"),
+				GranteeUserName: new("alice"),
+				GrantOption:     tt.grantOption,
+			}, nil)
+			if err != nil {
+				t.Fatalf("UpdateGrantPrivilege() error = %v", err)
+			}
+			if got == nil || got.GrantOption != tt.grantOption {
+				t.Errorf("UpdateGrantPrivilege() want grant option %v, got %+v", tt.grantOption, got)
+			}
+			if len(fake.executed) != 1 || !strings.Contains(fake.executed[0], tt.wantQuery) {
+				t.Errorf("UpdateGrantPrivilege() want a query with %q, got %q", tt.wantQuery, fake.executed)
+			}
+			if len(fake.responses) != 0 {
+				t.Errorf("want all %d reads, %d left", len(tt.responses), len(fake.responses))
+			}
+		})
+	}
+}
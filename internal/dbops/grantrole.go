@@ -17,6 +17,11 @@ type GrantRole struct {
 }
 
 func (i *impl) GrantRole(ctx context.Context, grantRole GrantRole, clusterName *string) (*GrantRole, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	var to string
 	{
 		if grantRole.GranteeUserName != nil {
@@ -28,12 +33,13 @@ func (i *impl) GrantRole(ctx context.Context, grantRole GrantRole, clusterName *
 		}
 	}
 
-	sql, err := querybuilder.GrantRole(grantRole.RoleName, to).WithCluster(clusterName).WithAdminOption(grantRole.AdminOption).Build()
+	builder := querybuilder.GrantRole(grantRole.RoleName, to).WithCluster(clusterName).WithAdminOption(grantRole.AdminOption)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
@@ -45,24 +51,78 @@ func (i *impl) GrantRole(ctx context.Context, grantRole GrantRole, clusterName *
 		identifier += " to role " + *grantRole.GranteeRoleName
 	}
 
-	return retryWithBackoff(ctx, "grant role", identifier, func() (*GrantRole, error) {
+	return retryWithBackoff(ctx, "grant role", identifier, func(ctx context.Context) (*GrantRole, error) {
 		return i.GetGrantRole(ctx, grantRole.RoleName, grantRole.GranteeUserName, grantRole.GranteeRoleName, clusterName)
 	}, i.readAfterWriteTimeoutArgs()...)
 }
 
+// UpdateGrantRole changes the admin option of an existing role grant in place, without revoking the
+// role: it is added by granting the role again WITH ADMIN OPTION, and removed with
+// REVOKE ADMIN OPTION FOR.
+func (i *impl) UpdateGrantRole(ctx context.Context, grantRole GrantRole, clusterName *string) (*GrantRole, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var grantee string
+	{
+		if grantRole.GranteeUserName != nil {
+			grantee = *grantRole.GranteeUserName
+		} else if grantRole.GranteeRoleName != nil {
+			grantee = *grantRole.GranteeRoleName
+		} else {
+			return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
+		}
+	}
+
+	var builder querybuilder.QueryBuilder
+	if grantRole.AdminOption {
+		builder = querybuilder.GrantRole(grantRole.RoleName, grantee).WithCluster(clusterName).WithAdminOption(true)
+	} else {
+		builder = querybuilder.RevokeRole(grantRole.RoleName, grantee).WithCluster(clusterName).AdminOptionOnly()
+	}
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	// Wait for the grant to show the new admin option.
+	return retryWithBackoff(ctx, "grant role", grantRole.RoleName+" to "+grantee, func(ctx context.Context) (*GrantRole, error) {
+		updated, err := i.GetGrantRole(ctx, grantRole.RoleName, grantRole.GranteeUserName, grantRole.GranteeRoleName, clusterName)
+		if err != nil || updated == nil || updated.AdminOption != grantRole.AdminOption {
+			return nil, err
+		}
+		return updated, nil
+	}, i.readAfterWriteTimeoutArgs()...)
+}
+
 func (i *impl) GetGrantRole(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GrantRole, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	var granteeWhere querybuilder.Where
+	var granteeCondition rowCondition
 	{
 		if granteeUserName != nil {
 			granteeWhere = querybuilder.WhereEquals("user_name", *granteeUserName)
+			granteeCondition = fieldEquals("user_name", *granteeUserName)
 		} else if granteeRoleName != nil {
 			granteeWhere = querybuilder.WhereEquals("role_name", *granteeRoleName)
+			granteeCondition = fieldEquals("role_name", *granteeRoleName)
 		} else {
 			return nil, errors.New("either GranteeUserName or GranteeRoleName must be set")
 		}
 	}
 
-	sql, err := querybuilder.NewSelect(
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{
 			querybuilder.NewField("granted_role_name"),
 			querybuilder.NewField("user_name"),
@@ -71,38 +131,18 @@ func (i *impl) GetGrantRole(ctx context.Context, grantedRoleName string, grantee
 		},
 		"system.role_grants").
 		WithCluster(clusterName).
-		Where(querybuilder.WhereEquals("granted_role_name", grantedRoleName), granteeWhere).
-		Build()
+		Where(querybuilder.WhereEquals("granted_role_name", grantedRoleName), granteeWhere)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var grantRole *GrantRole
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
-		roleName, err := data.GetString("granted_role_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
-		}
-		granteeUserName, err := data.GetNullableString("user_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'user_name' field")
-		}
-		granteeRoleName, err := data.GetNullableString("role_name")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'role_name' field")
-		}
-		adminOption, err := data.GetBool("with_admin_option")
-		if err != nil {
-			return errors.WithMessage(err, "error scanning query result, missing 'with_admin_option' field")
-		}
-		grantRole = &GrantRole{
-			RoleName:        roleName,
-			GranteeUserName: granteeUserName,
-			GranteeRoleName: granteeRoleName,
-			AdminOption:     adminOption,
-		}
-		return nil
+	conditions := []rowCondition{fieldEquals("granted_role_name", grantedRoleName), granteeCondition}
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotRoleGrants, clusterName, conditions, func(data clickhouseclient.Row) error {
+		grantRole, err = scanGrantRole(data)
+		return err
 	})
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
@@ -116,7 +156,78 @@ func (i *impl) GetGrantRole(ctx context.Context, grantedRoleName string, grantee
 	return grantRole, nil
 }
 
+// GetRoleMembers returns every user and role grantedRoleName is granted to.
+func (i *impl) GetRoleMembers(ctx context.Context, grantedRoleName string, clusterName *string) ([]GrantRole, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("granted_role_name"),
+			querybuilder.NewField("user_name"),
+			querybuilder.NewField("role_name"),
+			querybuilder.NewField("with_admin_option"),
+		},
+		"system.role_grants").
+		WithCluster(clusterName).
+		Where(querybuilder.WhereEquals("granted_role_name", grantedRoleName))
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	members := make([]GrantRole, 0)
+
+	conditions := []rowCondition{fieldEquals("granted_role_name", grantedRoleName)}
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotRoleGrants, clusterName, conditions, func(data clickhouseclient.Row) error {
+		member, err := scanGrantRole(data)
+		if err != nil {
+			return err
+		}
+		members = append(members, *member)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return members, nil
+}
+
+func scanGrantRole(data clickhouseclient.Row) (*GrantRole, error) {
+	roleName, err := data.GetString("granted_role_name")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'name' field")
+	}
+	granteeUserName, err := data.GetNullableString("user_name")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'user_name' field")
+	}
+	granteeRoleName, err := data.GetNullableString("role_name")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'role_name' field")
+	}
+	adminOption, err := data.GetBool("with_admin_option")
+	if err != nil {
+		return nil, errors.WithMessage(err, "error scanning query result, missing 'with_admin_option' field")
+	}
+
+	return &GrantRole{
+		RoleName:        roleName,
+		GranteeUserName: granteeUserName,
+		GranteeRoleName: granteeRoleName,
+		AdminOption:     adminOption,
+	}, nil
+}
+
 func (i *impl) RevokeGrantRole(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName *string) error {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	var grantee string
 	{
 		if granteeUserName != nil {
@@ -127,12 +238,13 @@ func (i *impl) RevokeGrantRole(ctx context.Context, grantedRoleName string, gran
 			return errors.New("either GranteeUserName or GranteeRoleName must be set")
 		}
 	}
-	sql, err := querybuilder.RevokeRole(grantedRoleName, grantee).WithCluster(clusterName).Build()
+	builder := querybuilder.RevokeRole(grantedRoleName, grantee).WithCluster(clusterName)
+	sql, err := builder.Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
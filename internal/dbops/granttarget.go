@@ -0,0 +1,126 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// MissingGrantTarget returns a description of the first of database, table and column that does not
+// exist on the server, or an empty string when they all exist. Names ending with the `*` wildcard
+// are patterns and are not checked, nor is anything nested in them.
+func (i *impl) MissingGrantTarget(ctx context.Context, database *string, table *string, column *string) (string, error) {
+	if database == nil || strings.HasSuffix(*database, "*") {
+		return "", nil
+	}
+
+	found, err := i.systemRowExists(ctx, "system.databases", querybuilder.WhereEquals("name", *database))
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("database %q", *database), nil
+	}
+
+	if table == nil || strings.HasSuffix(*table, "*") {
+		return "", nil
+	}
+
+	found, err = i.systemRowExists(ctx, "system.tables",
+		querybuilder.WhereEquals("database", *database),
+		querybuilder.WhereEquals("name", *table),
+	)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("table %q in the %q database", *table, *database), nil
+	}
+
+	if column == nil {
+		return "", nil
+	}
+
+	found, err = i.systemRowExists(ctx, "system.columns",
+		querybuilder.WhereEquals("database", *database),
+		querybuilder.WhereEquals("table", *table),
+		querybuilder.WhereEquals("name", *column),
+	)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("column %q of table %q in the %q database", *column, *table, *database), nil
+	}
+
+	return "", nil
+}
+
+// ListTables returns the names of the tables of database, sorted. Temporary tables are not included.
+// On a cluster, the tables of all its replicas are listed.
+func (i *impl) ListTables(ctx context.Context, database string, clusterName *string) ([]string, error) {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("name")},
+		"system.tables",
+	).WithCluster(clusterName).AllReplicas().Where(
+		querybuilder.WhereEquals("database", database),
+		querybuilder.WhereEquals("is_temporary", 0),
+	)
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	tables := make([]string, 0)
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		name, err := data.GetString("name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+		tables = append(tables, name)
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+	slices.Sort(tables)
+
+	return slices.Compact(tables), nil
+}
+
+// systemRowExists reports whether a row of the system table matches where.
+func (i *impl) systemRowExists(ctx context.Context, table string, where ...querybuilder.Where) (bool, error) {
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewRawField("count()", "matches")},
+		table,
+	).Where(where...)
+	sql, err := builder.Build()
+	if err != nil {
+		return false, errors.WithMessage(err, "error building query")
+	}
+
+	var matches uint64
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		matches, err = data.GetUInt64("matches")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'matches' field")
+		}
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return false, errors.WithMessage(err, "error running query")
+	}
+
+	return matches > 0, nil
+}
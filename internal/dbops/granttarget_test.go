@@ -0,0 +1,121 @@
+package dbops
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMissingGrantTarget(t *testing.T) {
+	count := func(n uint64) ddlResponse {
+		return ddlResponse{rows: []map[string]any{{"matches": n}}}
+	}
+
+	tests := []struct {
+		name      string
+		database  *string
+		table     *string
+		column    *string
+		responses []ddlResponse
+		want      string
+	}{
+		{
+			name: "Global grant",
+		},
+		{
+			name:      "Database exists",
+			database:  new("db"),
+			responses: []ddlResponse{count(1)},
+		},
+		{
+			name:      "Database missing",
+			database:  new("db"),
+			table:     new("t"),
+			responses: []ddlResponse{count(0)},
+			want:      `database "db"`,
+		},
+		{
+			name:      "Table missing",
+			database:  new("db"),
+			table:     new("t"),
+			column:    new("c"),
+			responses: []ddlResponse{count(1), count(0)},
+			want:      `table "t" in the "db" database`,
+		},
+		{
+			name:      "Column missing",
+			database:  new("db"),
+			table:     new("t"),
+			column:    new("c"),
+			responses: []ddlResponse{count(1), count(1), count(0)},
+			want:      `column "c" of table "t" in the "db" database`,
+		},
+		{
+			name:      "Table pattern",
+			database:  new("db"),
+			table:     new("events_*"),
+			responses: []ddlResponse{count(1)},
+		},
+		{
+			name:     "Database pattern",
+			database: new("team_*"),
+			table:    new("t"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &ddlClient{responses: tt.responses}
+			i := &impl{clickhouseClient: fake}
+
+			got, err := i.MissingGrantTarget(context.Background(), tt.database, tt.table, tt.column)
+			if err != nil {
+				t.Fatalf("MissingGrantTarget() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MissingGrantTarget() want = %q, got %q", tt.want, got)
+			}
+			if len(fake.responses) != 0 {
+				t.Errorf("%d scripted responses were not used", len(fake.responses))
+			}
+		})
+	}
+}
+
+func TestListTables(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterName *string
+		rows        []map[string]any
+		wantFrom    string
+	}{
+		{
+			name:     "Single server",
+			rows:     []map[string]any{{"name": "events_2"}, {"name": "events_1"}, {"name": "users"}},
+			wantFrom: "FROM `system`.`tables`",
+		},
+		{
+			name:        "Cluster, tables of all replicas",
+			clusterName: new("cluster1"),
+			rows:        []map[string]any{{"name": "events_2"}, {"name": "users"}, {"name": "events_1"}, {"name": "users"}},
+			wantFrom:    "FROM clusterAllReplicas(",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &ddlClient{responses: []ddlResponse{{rows: tt.rows}}}
+			i := &impl{clickhouseClient: fake}
+
+			got, err := i.ListTables(context.Background(), "db", tt.clusterName)
+			if err != nil {
+				t.Fatalf("ListTables() error = %v", err)
+			}
+			if want := []string{"events_1", "events_2", "users"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("ListTables() want = %v, got %v", want, got)
+			}
+			if !strings.Contains(fake.queries[0], tt.wantFrom) {
+				t.Errorf("ListTables() want query reading %q, got %s", tt.wantFrom, fake.queries[0])
+			}
+		})
+	}
+}
@@ -1,19 +1,31 @@
 package dbops
 
 import (
-	"context"
-	"sync"
 	"time"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
 )
 
 type impl struct {
 	clickhouseClient      clickhouseclient.ClickhouseClient
-	CapabilityFlags       *CapabilityFlags
 	readAfterWriteTimeout time.Duration
-	initOnce              sync.Once
-	initErr               error
+	snapshots             *snapshotCache
+	defaultCluster        *defaultCluster
+
+	// PlannedRoleGrants are the role grants planned by the resources using this client.
+	PlannedRoleGrants
+
+	// Server introspection, looked up once per provider run.
+	version           memo[string]
+	replicatedStorage memo[bool]
+	capabilities      memo[CapabilityFlags]
+	privileges        memo[grants.Catalog]
+	clusters          memoMap[string, *Cluster]
+	hosts             memoMap[string, []string]
+
+	// privilegeCatalog is privileges, or the embedded catalog once they failed to load.
+	privilegeCatalog memo[grants.Catalog]
 }
 
 // ClientOption configures optional behaviour of the dbops client.
@@ -27,10 +39,17 @@ func WithReadAfterWriteTimeout(d time.Duration) ClientOption {
 	}
 }
 
+// WithSnapshotCache serves lookups of grants, role grants, users, roles and settings profile
+// elements from snapshots of the system tables, loaded once and dropped after every write.
+func WithSnapshotCache() ClientOption {
+	return func(i *impl) {
+		i.snapshots = newSnapshotCache()
+	}
+}
+
 func NewClient(clickhouseClient clickhouseclient.ClickhouseClient, opts ...ClientOption) (Client, error) {
 	c := &impl{
 		clickhouseClient: clickhouseClient,
-		CapabilityFlags:  nil,
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -46,25 +65,3 @@ func (i *impl) readAfterWriteTimeoutArgs() []time.Duration {
 	}
 	return nil
 }
-
-func (i *impl) initCapabilities(ctx context.Context) {
-	i.initOnce.Do(func() {
-		version, err := i.GetVersion(ctx)
-		if err != nil {
-			i.initErr = err
-			return
-		}
-		i.CapabilityFlags = NewCapabilityFlags(version)
-	})
-}
-
-// Returns initialized capability flags for the connected ClickHouse server.
-func (i *impl) GetCapabilityFlags(ctx context.Context) (CapabilityFlags, error) {
-	i.initCapabilities(ctx)
-
-	if i.CapabilityFlags == nil {
-		return CapabilityFlags{}, i.initErr
-	}
-
-	return *i.CapabilityFlags, i.initErr
-}
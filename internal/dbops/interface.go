@@ -3,6 +3,9 @@ package dbops
 import (
 	"context"
 	"time"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/rolegraph"
 )
 
 type Client interface {
@@ -11,6 +14,24 @@ type Client interface {
 	DeleteDatabase(ctx context.Context, uuid string, clusterName *string) error
 	FindDatabaseByName(ctx context.Context, name string, clusterName *string) (*Database, error)
 
+	CreateTable(ctx context.Context, table Table, clusterName *string) (*Table, error)
+	GetTable(ctx context.Context, database string, name string, clusterName *string) (*Table, error)
+	UpdateTable(ctx context.Context, current Table, desired Table, clusterName *string) (*Table, error)
+	DeleteTable(ctx context.Context, database string, name string, clusterName *string) error
+
+	CreateView(ctx context.Context, view View, clusterName *string) (*View, error)
+	GetView(ctx context.Context, database string, name string, clusterName *string) (*View, error)
+	UpdateView(ctx context.Context, view View, clusterName *string) (*View, error)
+	DeleteView(ctx context.Context, database string, name string, clusterName *string) error
+	CreateMaterializedView(ctx context.Context, view MaterializedView, clusterName *string) (*MaterializedView, error)
+	GetMaterializedView(ctx context.Context, database string, name string, clusterName *string) (*MaterializedView, error)
+	UpdateMaterializedView(ctx context.Context, current MaterializedView, desired MaterializedView, clusterName *string) (*MaterializedView, error)
+
+	CreateDictionary(ctx context.Context, dictionary Dictionary, clusterName *string) (*Dictionary, error)
+	GetDictionary(ctx context.Context, database string, name string, clusterName *string) (*Dictionary, error)
+	UpdateDictionary(ctx context.Context, dictionary Dictionary, clusterName *string) (*Dictionary, error)
+	DeleteDictionary(ctx context.Context, database string, name string, clusterName *string) error
+
 	CreateRole(ctx context.Context, role Role, clusterName *string) (*Role, error)
 	GetRole(ctx context.Context, id string, clusterName *string) (*Role, error)
 	DeleteRole(ctx context.Context, id string, clusterName *string) error
@@ -24,13 +45,20 @@ type Client interface {
 	UpdateUser(ctx context.Context, user User, clusterName *string) (*User, error)
 
 	GrantRole(ctx context.Context, grantRole GrantRole, clusterName *string) (*GrantRole, error)
+	UpdateGrantRole(ctx context.Context, grantRole GrantRole, clusterName *string) (*GrantRole, error)
 	GetGrantRole(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName *string) (*GrantRole, error)
+	GetRoleMembers(ctx context.Context, grantedRoleName string, clusterName *string) ([]GrantRole, error)
+	GetRoleGraph(ctx context.Context, clusterName *string) (*rolegraph.Graph, error)
+	PlanRoleGrants(clusterName *string, resource string, plan RoleGrantPlan, accept func(others []RoleGrantPlan) bool)
 	RevokeGrantRole(ctx context.Context, grantedRoleName string, granteeUserName *string, granteeRoleName *string, clusterName *string) error
 
 	GrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege, clusterName *string) (*GrantPrivilege, error)
 	GetGrantPrivilege(ctx context.Context, grantPrivilege *GrantPrivilege, clusterName *string) (*GrantPrivilege, error)
+	UpdateGrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege, clusterName *string) (*GrantPrivilege, error)
 	RevokeGrantPrivilege(ctx context.Context, grantPrivilege GrantPrivilege, clusterName *string) error
 	GetAllGrantsForGrantee(ctx context.Context, granteeUsername *string, granteeRoleName *string, clusterName *string) ([]GrantPrivilege, error)
+	MissingGrantTarget(ctx context.Context, database *string, table *string, column *string) (string, error)
+	ListTables(ctx context.Context, database string, clusterName *string) ([]string, error)
 
 	CreateRowPolicy(ctx context.Context, rp RowPolicy, clusterName *string) (*RowPolicy, error)
 	GetRowPolicy(ctx context.Context, rp *RowPolicy, clusterName *string) (*RowPolicy, error)
@@ -57,6 +85,9 @@ type Client interface {
 	DeleteSetting(ctx context.Context, settingsProfileID string, name string, clusterName *string) error
 
 	IsReplicatedStorage(ctx context.Context) (bool, error)
+	GetCluster(ctx context.Context, clusterName string) (*Cluster, error)
 	GetCapabilityFlags(ctx context.Context) (CapabilityFlags, error)
+	PrivilegeCatalog(ctx context.Context) grants.Catalog
 	NormalizeExpression(ctx context.Context, expression string) (string, error)
+	SameQuery(ctx context.Context, database string, a string, b string) (bool, error)
 }
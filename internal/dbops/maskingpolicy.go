@@ -3,7 +3,6 @@ package dbops
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -47,22 +46,22 @@ func whereOrNil(s string) *string {
 // name on the same table surfaces an "already exists" error instead of being silently overwritten;
 // the resource layer turns that error into an import hint.
 func (i *impl) CreateMaskingPolicy(ctx context.Context, mp MaskingPolicy) (*MaskingPolicy, error) {
-	sql, err := querybuilder.NewCreateMaskingPolicy(mp.Name, mp.Database, mp.Table, mp.Masks).
+	builder := querybuilder.NewCreateMaskingPolicy(mp.Name, mp.Database, mp.Table, mp.Masks).
 		WithWhere(whereOrNil(mp.Where)).
 		GranteeNames(mp.GranteeNames).
 		GranteeAll(mp.GranteeAll).
 		GranteeAllExcept(mp.GranteeAllExcept).
-		WithPriority(mp.Priority).
-		Build()
+		WithPriority(mp.Priority)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	if err := i.clickhouseClient.Exec(ctx, sql); err != nil {
+	if err := i.exec(ctx, sql, builder.Parameters(), nil); err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	return retryWithBackoff(ctx, "masking policy", mp.identifier(), func() (*MaskingPolicy, error) {
+	return retryWithBackoff(ctx, "masking policy", mp.identifier(), func(ctx context.Context) (*MaskingPolicy, error) {
 		return i.GetMaskingPolicy(ctx, &mp)
 	}, i.readAfterWriteTimeoutArgs()...)
 }
@@ -77,23 +76,23 @@ func (i *impl) UpdateMaskingPolicy(ctx context.Context, mp MaskingPolicy) (*Mask
 		return nil, errors.Errorf("masking policy with id %q not found", mp.ID)
 	}
 
-	sql, err := querybuilder.NewAlterMaskingPolicy(existing.Name, existing.Database, existing.Table, mp.Masks).
+	builder := querybuilder.NewAlterMaskingPolicy(existing.Name, existing.Database, existing.Table, mp.Masks).
 		RenameTo(mp.Name).
 		WithWhere(whereOrNil(mp.Where)).
 		GranteeNames(mp.GranteeNames).
 		GranteeAll(mp.GranteeAll).
 		GranteeAllExcept(mp.GranteeAllExcept).
-		WithPriority(mp.Priority).
-		Build()
+		WithPriority(mp.Priority)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	if err := i.clickhouseClient.Exec(ctx, sql); err != nil {
+	if err := i.exec(ctx, sql, builder.Parameters(), nil); err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	return retryWithBackoff(ctx, "masking policy", mp.identifier(), func() (*MaskingPolicy, error) {
+	return retryWithBackoff(ctx, "masking policy", mp.identifier(), func(ctx context.Context) (*MaskingPolicy, error) {
 		return i.GetMaskingPolicyByID(ctx, mp.ID)
 	}, i.readAfterWriteTimeoutArgs()...)
 }
@@ -113,7 +112,7 @@ func (i *impl) GetMaskingPolicyByID(ctx context.Context, id string) (*MaskingPol
 }
 
 func (i *impl) getMaskingPolicyWhere(ctx context.Context, where []querybuilder.Where) (*MaskingPolicy, error) {
-	sql, err := querybuilder.NewSelect(
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{
 			querybuilder.NewField("id").ToString(),
 			querybuilder.NewField("short_name"),
@@ -121,13 +120,14 @@ func (i *impl) getMaskingPolicyWhere(ctx context.Context, where []querybuilder.W
 			querybuilder.NewField("table"),
 			querybuilder.NewRawField("toString(cityHash64(ifNull(update_assignments, '')))", "update_assignments_hash"),
 			querybuilder.NewField("where_condition"),
-			querybuilder.NewRawField("toString(priority)", "priority"),
+			querybuilder.NewField("priority"),
 			querybuilder.NewField("apply_to_all"),
-			querybuilder.NewRawField("arrayStringConcat(apply_to_list, '\\n')", "apply_to_list"),
-			querybuilder.NewRawField("arrayStringConcat(apply_to_except, '\\n')", "apply_to_except"),
+			querybuilder.NewField("apply_to_list"),
+			querybuilder.NewField("apply_to_except"),
 		},
 		"system.masking_policies",
-	).Where(where...).Build()
+	).Where(where...)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
@@ -168,26 +168,22 @@ func (i *impl) getMaskingPolicyWhere(ctx context.Context, where []querybuilder.W
 			whereCondition = *wherePtr
 		}
 
-		priorityStr, err := data.GetString("priority")
+		priority, err := data.GetInt64("priority")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'priority' field")
 		}
-		priority, err := strconv.ParseInt(priorityStr, 10, 64)
-		if err != nil {
-			return errors.WithMessage(err, "error parsing 'priority' field")
-		}
 
 		applyToAll, err := data.GetBool("apply_to_all")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_all' field")
 		}
 
-		applyToList, err := data.GetString("apply_to_list")
+		applyToList, err := data.GetStringArray("apply_to_list")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_list' field")
 		}
 
-		applyToExcept, err := data.GetString("apply_to_except")
+		applyToExcept, err := data.GetStringArray("apply_to_except")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_except' field")
 		}
@@ -199,14 +195,14 @@ func (i *impl) getMaskingPolicyWhere(ctx context.Context, where []querybuilder.W
 			Table:            table,
 			AssignmentsHash:  assignmentsHash,
 			Where:            whereCondition,
-			GranteeNames:     splitNonEmpty(applyToList),
+			GranteeNames:     nilIfEmpty(applyToList),
 			GranteeAll:       applyToAll,
-			GranteeAllExcept: splitNonEmpty(applyToExcept),
+			GranteeAllExcept: nilIfEmpty(applyToExcept),
 			Priority:         &priority,
 		}
 
 		return nil
-	})
+	}, builder.Parameters())
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
@@ -225,12 +221,13 @@ func (i *impl) DeleteMaskingPolicy(ctx context.Context, id string) error {
 		return nil
 	}
 
-	sql, err := querybuilder.NewDropMaskingPolicy(mp.Name, mp.Database, mp.Table).IfExists(true).Build()
+	builder := querybuilder.NewDropMaskingPolicy(mp.Name, mp.Database, mp.Table).IfExists(true)
+	sql, err := builder.Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	if err := i.clickhouseClient.Exec(ctx, sql); err != nil {
+	if err := i.exec(ctx, sql, builder.Parameters(), nil); err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
 
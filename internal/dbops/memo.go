@@ -0,0 +1,54 @@
+package dbops
+
+import (
+	"sync"
+)
+
+// memo holds the result of a server lookup that does not change while the provider runs, such as
+// the server version. Failed lookups are not remembered, so that the next call tries again.
+type memo[T any] struct {
+	mu    sync.Mutex
+	done  bool
+	value T
+}
+
+// get returns the remembered value, calling load if there is none yet.
+func (m *memo[T]) get(load func() (T, error)) (T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.done {
+		return m.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	m.value = value
+	m.done = true
+
+	return value, nil
+}
+
+// memoMap is a memo per key, for lookups with an argument.
+type memoMap[K comparable, T any] struct {
+	mu    sync.Mutex
+	memos map[K]*memo[T]
+}
+
+func (m *memoMap[K, T]) get(key K, load func() (T, error)) (T, error) {
+	m.mu.Lock()
+	if m.memos == nil {
+		m.memos = make(map[K]*memo[T])
+	}
+	entry, ok := m.memos[key]
+	if !ok {
+		entry = &memo[T]{}
+		m.memos[key] = entry
+	}
+	m.mu.Unlock()
+
+	return entry.get(load)
+}
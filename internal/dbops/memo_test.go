@@ -0,0 +1,74 @@
+package dbops
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemo_get(t *testing.T) {
+	var m memo[string]
+	loads := 0
+	load := func(value string, err error) func() (string, error) {
+		return func() (string, error) {
+			loads++
+			return value, err
+		}
+	}
+
+	if _, err := m.get(load("", errors.New("connection refused"))); err == nil {
+		t.Fatalf("get() want error, got nil")
+	}
+
+	got, err := m.get(load("v25.8.1", nil))
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if got != "v25.8.1" {
+		t.Errorf("get() want = v25.8.1, got %s", got)
+	}
+
+	got, err = m.get(load("v25.9.1", nil))
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if got != "v25.8.1" {
+		t.Errorf("get() want remembered value v25.8.1, got %s", got)
+	}
+
+	if loads != 2 {
+		t.Errorf("want 2 loads, got %d", loads)
+	}
+}
+
+func TestMemoMap_get(t *testing.T) {
+	var m memoMap[string, int]
+	loads := 0
+	load := func(value int) func() (int, error) {
+		return func() (int, error) {
+			loads++
+			return value, nil
+		}
+	}
+
+	for _, tc := range []struct {
+		key  string
+		load int
+		want int
+	}{
+		{key: "a", load: 1, want: 1},
+		{key: "b", load: 2, want: 2},
+		{key: "a", load: 3, want: 1},
+	} {
+		got, err := m.get(tc.key, load(tc.load))
+		if err != nil {
+			t.Fatalf("get(%s) error = %v", tc.key, err)
+		}
+		if got != tc.want {
+			t.Errorf("get(%s) want = %d, got %d", tc.key, tc.want, got)
+		}
+	}
+
+	if loads != 2 {
+		t.Errorf("want 2 loads, got %d", loads)
+	}
+}
@@ -2,7 +2,6 @@ package dbops
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -13,31 +12,105 @@ import (
 // NormalizeExpression normalizes expression using ClickHouse formatQuerySingleLine function.
 func (i *impl) NormalizeExpression(ctx context.Context, expression string) (string, error) {
 	const prefix = "SELECT "
-	sql := fmt.Sprintf("SELECT formatQuerySingleLine(concat('%s', %s)) AS formatted", prefix, chStringLiteral(expression))
+	formatted, err := i.formatQuerySingleLine(ctx, prefix+expression)
+	if err != nil {
+		return expression, err
+	}
+	if formatted == nil || !strings.HasPrefix(*formatted, prefix) {
+		return expression, nil
+	}
+	return strings.TrimPrefix(*formatted, prefix), nil
+}
+
+// SameQuery reports whether two queries of a view of database are equal once formatted by the
+// server. ClickHouse stores the query of a view with the database of its tables: tables of database
+// are compared without it.
+func (i *impl) SameQuery(ctx context.Context, database string, a string, b string) (bool, error) {
+	if a == b {
+		return true, nil
+	}
+
+	normalizedA, err := i.normalizeQuery(ctx, database, a)
+	if err != nil {
+		return false, err
+	}
+	normalizedB, err := i.normalizeQuery(ctx, database, b)
+	if err != nil {
+		return false, err
+	}
+	return normalizedA == normalizedB, nil
+}
+
+// normalizeQuery formats query using ClickHouse formatQuerySingleLine function and removes database
+// from the names qualified with it.
+func (i *impl) normalizeQuery(ctx context.Context, database string, query string) (string, error) {
+	formatted, err := i.formatQuerySingleLine(ctx, query)
+	if err != nil {
+		return query, err
+	}
+	if formatted == nil {
+		return query, nil
+	}
+	return unqualify(*formatted, database), nil
+}
+
+// unqualify removes the database prefix, quoted or not, from the names of a formatted query. String
+// literals are left as they are.
+func unqualify(query string, database string) string {
+	prefixes := []string{database + ".", "`" + strings.ReplaceAll(database, "`", "\\`") + "`."}
+
+	var b strings.Builder
+	inString := false
+	for idx := 0; idx < len(query); idx++ {
+		c := query[idx]
+		switch {
+		case inString:
+			if c == '\\' && idx+1 < len(query) {
+				b.WriteByte(c)
+				idx++
+				c = query[idx]
+			} else if c == '\'' {
+				inString = false
+			}
+		case c == '\'':
+			inString = true
+		case idx == 0 || !isNamePart(query[idx-1]):
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(query[idx:], prefix) {
+					idx += len(prefix)
+					break
+				}
+			}
+			if idx == len(query) {
+				return b.String()
+			}
+			c = query[idx]
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
 
-	var formatted string
-	found := false
+// isNamePart reports whether c can be part of a possibly qualified name.
+func isNamePart(c byte) bool {
+	return c == '_' || c == '.' || c == '`' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (i *impl) formatQuerySingleLine(ctx context.Context, query string) (*string, error) {
+	const sql = "SELECT formatQuerySingleLine({query:String}) AS formatted"
+	params := map[string]string{"query": query}
+
+	var formatted *string
 	err := i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
 		v, err := data.GetString("formatted")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'formatted' field")
 		}
-		formatted = v
-		found = true
+		formatted = &v
 		return nil
-	})
+	}, params)
 	if err != nil {
-		return expression, errors.WithMessage(err, "error running query")
+		return nil, errors.WithMessage(err, "error running query")
 	}
-	if !found || !strings.HasPrefix(formatted, prefix) {
-		return expression, nil
-	}
-	return strings.TrimPrefix(formatted, prefix), nil
-}
-
-// chStringLiteral renders s as a single-quoted ClickHouse string literal with backslash escaping.
-func chStringLiteral(s string) string {
-	s = strings.ReplaceAll(s, "\\", "\\\\")
-	s = strings.ReplaceAll(s, "'", "\\'")
-	return "'" + s + "'"
+	return formatted, nil
 }
@@ -0,0 +1,50 @@
+package dbops
+
+import "testing"
+
+func Test_unqualify(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		database string
+		want     string
+	}{
+		{
+			name:     "Table of the database",
+			query:    "SELECT id FROM db.events",
+			database: "db",
+			want:     "SELECT id FROM events",
+		},
+		{
+			name:     "Quoted database",
+			query:    "SELECT id FROM `my-db`.events JOIN `my-db`.`users` USING (id)",
+			database: "my-db",
+			want:     "SELECT id FROM events JOIN `users` USING (id)",
+		},
+		{
+			name:     "Table of another database",
+			query:    "SELECT number FROM system.numbers JOIN dbx.t USING (number)",
+			database: "db",
+			want:     "SELECT number FROM system.numbers JOIN dbx.t USING (number)",
+		},
+		{
+			name:     "Column of a table named like the database",
+			query:    "SELECT t.db.x FROM db.t",
+			database: "db",
+			want:     "SELECT t.db.x FROM t",
+		},
+		{
+			name:     "String literals are kept",
+			query:    "SELECT 'db.events', 'it\\'s db.x' FROM db.events",
+			database: "db",
+			want:     "SELECT 'db.events', 'it\\'s db.x' FROM events",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unqualify(tt.query, tt.database); got != tt.want {
+				t.Errorf("unqualify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
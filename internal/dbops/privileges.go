@@ -0,0 +1,92 @@
+package dbops
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// PrivilegeCatalog returns the privileges supported by the server, loaded from system.privileges
+// once per provider run. The catalog embedded in the provider is returned when the server cannot be
+// queried, for example while planning without network access. The fallback is remembered, so that
+// only the first caller waits for the server.
+func (i *impl) PrivilegeCatalog(ctx context.Context) grants.Catalog {
+	catalog, _ := i.privilegeCatalog.get(func() (grants.Catalog, error) {
+		catalog, err := i.loadPrivilegeCatalog(ctx)
+		if err != nil {
+			tflog.Warn(ctx, "Could not load the privileges of the server, using the catalog embedded in the provider", map[string]any{
+				"error": err.Error(),
+			})
+			return grants.Parsed(), nil
+		}
+
+		return catalog, nil
+	})
+
+	return catalog
+}
+
+func (i *impl) loadPrivilegeCatalog(ctx context.Context) (grants.Catalog, error) {
+	return i.privileges.get(func() (grants.Catalog, error) {
+		return i.queryPrivilegeCatalog(ctx)
+	})
+}
+
+func (i *impl) queryPrivilegeCatalog(ctx context.Context) (grants.Catalog, error) {
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("privilege").ToString(),
+			querybuilder.NewField("aliases"),
+			querybuilder.NewField("level").ToString(),
+			querybuilder.NewField("parent_group").ToString(),
+		},
+		"system.privileges",
+	)
+	sql, err := builder.Build()
+	if err != nil {
+		return grants.Catalog{}, errors.WithMessage(err, "error building query")
+	}
+
+	entries := make([]grants.Entry, 0)
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		privilege, err := data.GetString("privilege")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'privilege' field")
+		}
+		aliases, err := data.GetStringArray("aliases")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'aliases' field")
+		}
+		level, err := data.GetNullableString("level")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'level' field")
+		}
+		parentGroup, err := data.GetNullableString("parent_group")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'parent_group' field")
+		}
+
+		entries = append(entries, grants.Entry{
+			Privilege:   privilege,
+			Aliases:     aliases,
+			Level:       level,
+			ParentGroup: parentGroup,
+		})
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return grants.Catalog{}, errors.WithMessage(err, "error running query")
+	}
+
+	if len(entries) == 0 {
+		return grants.Catalog{}, errors.New("system.privileges is empty")
+	}
+
+	return grants.NewCatalog(entries), nil
+}
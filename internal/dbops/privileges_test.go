@@ -0,0 +1,82 @@
+package dbops
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
+)
+
+func privilegeRow(privilege string, level string, parentGroup string) map[string]any {
+	row := map[string]any{"privilege": privilege, "aliases": []string{}, "level": nil, "parent_group": nil}
+	if level != "" {
+		row["level"] = level
+	}
+	if parentGroup != "" {
+		row["parent_group"] = parentGroup
+	}
+	return row
+}
+
+func TestPrivilegeCatalog(t *testing.T) {
+	showTables := privilegeRow("SHOW TABLES", "TABLE", "SHOW")
+	showTables["aliases"] = []string{"SHOW TABLES"}
+
+	tests := []struct {
+		name      string
+		responses []ddlResponse
+		want      grants.Catalog
+	}{
+		{
+			name: "Loaded from the server",
+			responses: []ddlResponse{
+				{rows: []map[string]any{
+					privilegeRow("ALL", "", ""),
+					privilegeRow("SHOW", "", "ALL"),
+					showTables,
+					privilegeRow("NEW PRIVILEGE", "GLOBAL", "ALL"),
+				}},
+			},
+			want: grants.Catalog{
+				Aliases: map[string]string{},
+				Groups:  map[string][]string{"ALL": {"SHOW", "NEW PRIVILEGE"}, "SHOW": {"SHOW TABLES"}},
+				Scopes:  map[string]string{"SHOW TABLES": "TABLE", "NEW PRIVILEGE": "GLOBAL"},
+			},
+		},
+		{
+			name: "Embedded when offline",
+			responses: []ddlResponse{
+				{err: errors.New("dial tcp 127.0.0.1:9000: connect: connection refused")},
+			},
+			want: grants.Parsed(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &impl{clickhouseClient: &ddlClient{responses: tt.responses}}
+
+			got := i.PrivilegeCatalog(context.Background())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PrivilegeCatalog() want = %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPrivilegeCatalog_RemembersFallback(t *testing.T) {
+	fake := &ddlClient{responses: []ddlResponse{
+		{err: errors.New("dial tcp 127.0.0.1:9000: connect: connection refused")},
+	}}
+	i := &impl{clickhouseClient: fake}
+
+	for range 3 {
+		if got := i.PrivilegeCatalog(context.Background()); !reflect.DeepEqual(got, grants.Parsed()) {
+			t.Errorf("PrivilegeCatalog() want the embedded catalog, got %+v", got)
+		}
+	}
+	if len(fake.queries) != 1 {
+		t.Errorf("want 1 query, got %d", len(fake.queries))
+	}
+}
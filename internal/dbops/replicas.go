@@ -0,0 +1,213 @@
+package dbops
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// ReplicaDivergence describes an entity that is not the same on all the replicas of its cluster.
+type ReplicaDivergence struct {
+	// Missing are the hosts where the entity does not exist.
+	Missing []string
+	// Different are the hosts where the entity differs from the majority of the replicas.
+	Different []string
+}
+
+// Detail describes the divergence for a diagnostic.
+func (d *ReplicaDivergence) Detail(kind string, name string) string {
+	var sb strings.Builder
+	if len(d.Missing) > 0 {
+		sb.WriteString(fmt.Sprintf("The %s %q is missing on %s. ", kind, name, strings.Join(d.Missing, ", ")))
+	}
+	if len(d.Different) > 0 {
+		sb.WriteString(fmt.Sprintf("The %s %q is different on %s. ", kind, name, strings.Join(d.Different, ", ")))
+	}
+	if len(d.Missing) > 0 {
+		sb.WriteString("The next apply creates it again on the replicas missing it.")
+	} else {
+		sb.WriteString("The next apply replaces it to create it again identically on all replicas.")
+	}
+
+	return sb.String()
+}
+
+// replicaCheck compares one entity of a system table across replicas.
+type replicaCheck struct {
+	table string
+	name  string
+	// columns are compared between replicas. They must not include per-replica values such as IDs.
+	columns []string
+}
+
+// replicaHosts returns the hostName() of every replica of clusterName.
+func (i *impl) replicaHosts(ctx context.Context, clusterName string) ([]string, error) {
+	return i.hosts.get(clusterName, func() ([]string, error) {
+		builder := querybuilder.NewSelect(
+			[]querybuilder.Field{querybuilder.NewRawField("hostName()", "replica_host")},
+			"system.one",
+		).WithCluster(&clusterName).AllReplicas()
+		sql, err := builder.Build()
+		if err != nil {
+			return nil, errors.WithMessage(err, "error building query")
+		}
+
+		hosts := make([]string, 0)
+		err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+			host, err := data.GetString("replica_host")
+			if err != nil {
+				return errors.WithMessage(err, "error scanning query result, missing 'replica_host' field")
+			}
+			hosts = append(hosts, host)
+			return nil
+		}, builder.Parameters())
+		if err != nil {
+			return nil, errors.WithMessage(err, "error running query")
+		}
+
+		slices.Sort(hosts)
+		return hosts, nil
+	})
+}
+
+// checkReplicas compares check on all replicas of clusterName, returning nil when they agree or no
+// cluster is used. With the snapshot cache enabled, the fingerprints of all the entities of the table
+// are loaded once and shared by all the checks on it.
+func (i *impl) checkReplicas(ctx context.Context, clusterName *string, check replicaCheck) (*ReplicaDivergence, error) {
+	if clusterName == nil {
+		return nil, nil
+	}
+
+	hosts, err := i.replicaHosts(ctx, *clusterName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error listing the replicas of the cluster")
+	}
+
+	columns := make([]string, 0, len(check.columns))
+	for _, c := range check.columns {
+		columns = append(columns, "`"+c+"`")
+	}
+
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewRawField("hostName()", "replica_host"),
+			querybuilder.NewField("name"),
+			querybuilder.NewRawField(fmt.Sprintf("toString(tuple(%s))", strings.Join(columns, ", ")), "fingerprint"),
+		},
+		check.table,
+	).WithCluster(clusterName).AllReplicas()
+
+	var rows []clickhouseclient.Row
+	if i.snapshots == nil || ctx.Value(withoutSnapshotKey{}) != nil {
+		rows, err = i.selectRows(ctx, builder.Where(querybuilder.WhereEquals("name", check.name)))
+	} else {
+		key := snapshotKey{table: snapshotTable(fmt.Sprintf("%s(%s)", check.table, strings.Join(columns, ", "))), cluster: *clusterName}
+		rows, err = i.snapshots.load(key, func() ([]clickhouseclient.Row, error) {
+			return i.selectRows(ctx, builder)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints := make(map[string]string)
+	for _, data := range rows {
+		name, err := data.GetString("name")
+		if err != nil {
+			return nil, errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+		if name != check.name {
+			continue
+		}
+		host, err := data.GetString("replica_host")
+		if err != nil {
+			return nil, errors.WithMessage(err, "error scanning query result, missing 'replica_host' field")
+		}
+		fingerprint, err := data.GetString("fingerprint")
+		if err != nil {
+			return nil, errors.WithMessage(err, "error scanning query result, missing 'fingerprint' field")
+		}
+		fingerprints[host] = fingerprint
+	}
+
+	return compareReplicas(hosts, fingerprints), nil
+}
+
+// selectRows runs the query of builder and returns all the rows.
+func (i *impl) selectRows(ctx context.Context, builder querybuilder.SelectQueryBuilder) ([]clickhouseclient.Row, error) {
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	rows := make([]clickhouseclient.Row, 0)
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		rows = append(rows, data)
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return rows, nil
+}
+
+// compareReplicas returns how fingerprints, by host, diverge across hosts. The fingerprint found on
+// most hosts is taken as the reference.
+func compareReplicas(hosts []string, fingerprints map[string]string) *ReplicaDivergence {
+	counts := make(map[string]int)
+	for _, f := range fingerprints {
+		counts[f]++
+	}
+
+	reference := ""
+	for f, n := range counts {
+		if n > counts[reference] || (n == counts[reference] && f < reference) {
+			reference = f
+		}
+	}
+
+	divergence := &ReplicaDivergence{}
+	for _, host := range hosts {
+		f, ok := fingerprints[host]
+		switch {
+		case !ok:
+			divergence.Missing = append(divergence.Missing, host)
+		case f != reference:
+			divergence.Different = append(divergence.Different, host)
+		}
+	}
+
+	if len(divergence.Missing) == 0 && len(divergence.Different) == 0 {
+		return nil
+	}
+
+	return divergence
+}
+
+// existsOnSomeReplicas reports whether the entity called name in table exists on some, but not all,
+// replicas of clusterName. Creating it ON CLUSTER then adds it to the replicas missing it, while the
+// others fail because it already exists.
+func (i *impl) existsOnSomeReplicas(ctx context.Context, clusterName *string, table string, name string) (bool, error) {
+	if clusterName == nil {
+		return false, nil
+	}
+
+	hosts, err := i.replicaHosts(ctx, *clusterName)
+	if err != nil {
+		return false, errors.WithMessage(err, "error listing the replicas of the cluster")
+	}
+
+	divergence, err := i.checkReplicas(ctx, clusterName, replicaCheck{table: table, name: name, columns: []string{"name"}})
+	if err != nil {
+		return false, err
+	}
+
+	return divergence != nil && len(divergence.Missing) > 0 && len(divergence.Missing) < len(hosts), nil
+}
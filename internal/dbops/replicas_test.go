@@ -0,0 +1,48 @@
+package dbops
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_compareReplicas(t *testing.T) {
+	hosts := []string{"ch-1", "ch-2", "ch-3"}
+
+	tests := []struct {
+		name         string
+		fingerprints map[string]string
+		want         *ReplicaDivergence
+	}{
+		{
+			name:         "Same on all replicas",
+			fingerprints: map[string]string{"ch-1": "a", "ch-2": "a", "ch-3": "a"},
+		},
+		{
+			name:         "Missing on one replica",
+			fingerprints: map[string]string{"ch-1": "a", "ch-3": "a"},
+			want:         &ReplicaDivergence{Missing: []string{"ch-2"}},
+		},
+		{
+			name:         "Different from the majority",
+			fingerprints: map[string]string{"ch-1": "a", "ch-2": "b", "ch-3": "a"},
+			want:         &ReplicaDivergence{Different: []string{"ch-2"}},
+		},
+		{
+			name:         "Missing and different",
+			fingerprints: map[string]string{"ch-1": "b", "ch-2": "a"},
+			want:         &ReplicaDivergence{Missing: []string{"ch-3"}, Different: []string{"ch-1"}},
+		},
+		{
+			name: "Missing everywhere",
+			want: &ReplicaDivergence{Missing: hosts},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compareReplicas(hosts, tt.fingerprints)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compareReplicas() want = %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -11,10 +11,16 @@ import (
 
 // IsReplicatedStorage queries system tables and checks if the highest priority storage system for users and roles is 'replicated'.
 func (i *impl) IsReplicatedStorage(ctx context.Context) (bool, error) {
-	sql, err := querybuilder.
+	return i.replicatedStorage.get(func() (bool, error) {
+		return i.queryReplicatedStorage(ctx)
+	})
+}
+
+func (i *impl) queryReplicatedStorage(ctx context.Context) (bool, error) {
+	builder := querybuilder.
 		NewSelect([]querybuilder.Field{querybuilder.NewField("type"), querybuilder.NewField("precedence")}, "system.user_directories").
-		Where(querybuilder.WhereDiffers("type", "users_xml")).
-		Build()
+		Where(querybuilder.WhereDiffers("type", "users_xml"))
+	sql, err := builder.Build()
 	if err != nil {
 		return false, errors.WithMessage(err, "error building query")
 	}
@@ -38,7 +44,7 @@ func (i *impl) IsReplicatedStorage(ctx context.Context) (bool, error) {
 		}
 
 		return nil
-	})
+	}, builder.Parameters())
 	if err != nil {
 		return false, errors.WithMessage(err, "error running query")
 	}
@@ -20,7 +20,8 @@ import (
 //   - ctx: Context for cancellation
 //   - resourceType: Human-readable resource type name (e.g., "user", "role")
 //   - resourceIdentifier: The specific identifier being looked up (e.g., user name)
-//   - retrievalFunc: Function that attempts to retrieve the resource
+//   - retrievalFunc: Function that attempts to retrieve the resource. Its context bypasses the
+//     snapshot cache: a snapshot loaded before the write is visible would be reused by every retry.
 //   - timeout: Optional timeout duration. If not provided, defaults to 30 seconds.
 //
 // Returns the retrieved resource or an error if all retries are exhausted.
@@ -28,7 +29,7 @@ func retryWithBackoff[T any](
 	ctx context.Context,
 	resourceType string,
 	resourceIdentifier string,
-	retrievalFunc func() (*T, error),
+	retrievalFunc func(ctx context.Context) (*T, error),
 	timeout ...time.Duration,
 ) (*T, error) {
 	const defaultTimeout = 30 * time.Second
@@ -46,7 +47,7 @@ func retryWithBackoff[T any](
 
 	backoff := initialBackoff
 	for {
-		result, err := retrievalFunc()
+		result, err := retrievalFunc(withoutSnapshot(ctx))
 		if err != nil {
 			return nil, fmt.Errorf("error retrieving created %s: %w", resourceType, err)
 		}
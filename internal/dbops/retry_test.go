@@ -17,7 +17,7 @@ func TestRetryWithBackoff_ImmediateSuccess(t *testing.T) {
 		ctx,
 		"test resource",
 		"test-123",
-		func() (*testResource, error) {
+		func(_ context.Context) (*testResource, error) {
 			callCount++
 			return expectedResult, nil
 		},
@@ -44,7 +44,7 @@ func TestRetryWithBackoff_EventualSuccess(t *testing.T) {
 		ctx,
 		"test resource",
 		"test-456",
-		func() (*testResource, error) {
+		func(_ context.Context) (*testResource, error) {
 			callCount++
 			// Return nil for first 3 calls to simulate lag
 			if callCount < 3 {
@@ -75,7 +75,7 @@ func TestRetryWithBackoff_ImmediateError(t *testing.T) {
 		ctx,
 		"test resource",
 		"test-789",
-		func() (*testResource, error) {
+		func(_ context.Context) (*testResource, error) {
 			callCount++
 			return nil, expectedError
 		},
@@ -106,7 +106,7 @@ func TestRetryWithBackoff_Timeout(t *testing.T) {
 		ctx,
 		"test resource",
 		"never-found",
-		func() (*testResource, error) {
+		func(_ context.Context) (*testResource, error) {
 			callCount++
 			return nil, nil // Always return not found
 		},
@@ -3,6 +3,7 @@ package dbops
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/pingcap/errors"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
@@ -13,6 +14,8 @@ type Role struct {
 	ID               string   `json:"id" ch:"id"`
 	Name             string   `json:"name" ch:"name"`
 	SettingsProfiles []string `json:"-"`
+	// Divergence is set when the role differs between the replicas of its cluster.
+	Divergence *ReplicaDivergence `json:"-"`
 }
 
 func (r *Role) HasSettingProfile(profileName string) bool {
@@ -26,33 +29,59 @@ func (r *Role) HasSettingProfile(profileName string) bool {
 }
 
 func (i *impl) CreateRole(ctx context.Context, role Role, clusterName *string) (*Role, error) {
-	sql, err := querybuilder.NewCreateRole(role.Name).WithCluster(clusterName).Build()
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.NewCreateRole(role.Name).WithCluster(clusterName)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	repair, err := i.existsOnSomeReplicas(ctx, clusterName, "system.roles", role.Name)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error running query")
+		return nil, errors.WithMessage(err, "error comparing replicas")
 	}
 
-	return retryWithBackoff(ctx, "role", role.Name, func() (*Role, error) {
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		if !repair || !isAlreadyExistsError(err) {
+			return partiallyCreated(err, func() (*Role, error) {
+				return i.FindRoleByName(ctx, role.Name, clusterName)
+			})
+		}
+
+		// The replicas that already had the role refused to create it again, the others now have it.
+		tflog.Warn(ctx, "role existed on some replicas only, created it on the others", map[string]any{
+			"name": role.Name,
+		})
+	}
+
+	return retryWithBackoff(ctx, "role", role.Name, func(ctx context.Context) (*Role, error) {
 		return i.FindRoleByName(ctx, role.Name, clusterName)
 	}, i.readAfterWriteTimeoutArgs()...)
 }
 
 func (i *impl) GetRole(ctx context.Context, id string, clusterName *string) (*Role, error) { // nolint:dupl
-	sql, err := querybuilder.NewSelect(
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{querybuilder.NewField("name")},
 		"system.roles",
-	).WithCluster(clusterName).Where(querybuilder.WhereEquals("id", id)).Build()
+	).WithCluster(clusterName).Where(querybuilder.WhereEquals("id", id))
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var role *Role
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotRoles, clusterName, []rowCondition{fieldEquals("id", id)}, func(data clickhouseclient.Row) error {
 		n, err := data.GetString("name")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
@@ -74,17 +103,17 @@ func (i *impl) GetRole(ctx context.Context, id string, clusterName *string) (*Ro
 
 	// Check if role has settings profile associated.
 	{
-		sql, err = querybuilder.
+		builder := querybuilder.
 			NewSelect([]querybuilder.Field{querybuilder.NewField("inherit_profile")}, "system.settings_profile_elements").
 			WithCluster(clusterName).
-			Where(querybuilder.WhereEquals("role_name", role.Name)).
-			Build()
+			Where(querybuilder.WhereEquals("role_name", role.Name))
+		sql, err := builder.Build()
 		if err != nil {
 			return nil, errors.WithMessage(err, "error building query")
 		}
 
 		profiles := make([]string, 0)
-		err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotSettingsProfileElements, clusterName, []rowCondition{fieldEquals("role_name", role.Name)}, func(data clickhouseclient.Row) error {
 			profile, err := data.GetNullableString("inherit_profile")
 			if err != nil {
 				return errors.WithMessage(err, "error scanning query result, missing 'inherit_profile' field")
@@ -102,10 +131,25 @@ func (i *impl) GetRole(ctx context.Context, id string, clusterName *string) (*Ro
 		role.SettingsProfiles = profiles
 	}
 
+	// Roles have no attributes besides their name, only missing replicas can be found.
+	role.Divergence, err = i.checkReplicas(ctx, clusterName, replicaCheck{
+		table:   "system.roles",
+		name:    role.Name,
+		columns: []string{"name"},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error comparing replicas")
+	}
+
 	return role, nil
 }
 
 func (i *impl) DeleteRole(ctx context.Context, id string, clusterName *string) error {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	role, err := i.GetRole(ctx, id, clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error getting role")
@@ -116,12 +160,13 @@ func (i *impl) DeleteRole(ctx context.Context, id string, clusterName *string) e
 		return nil
 	}
 
-	sql, err := querybuilder.NewDropRole(role.Name).WithCluster(clusterName).Build()
+	builder := querybuilder.NewDropRole(role.Name).WithCluster(clusterName)
+	sql, err := builder.Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
@@ -130,17 +175,23 @@ func (i *impl) DeleteRole(ctx context.Context, id string, clusterName *string) e
 }
 
 func (i *impl) FindRoleByName(ctx context.Context, name string, clusterName *string) (*Role, error) {
-	sql, err := querybuilder.NewSelect(
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{querybuilder.NewField("id").ToString()},
 		"system.roles",
-	).Where(querybuilder.WhereEquals("name", name)).WithCluster(clusterName).Build()
+	).Where(querybuilder.WhereEquals("name", name)).WithCluster(clusterName)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var uuid string
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotRoles, clusterName, []rowCondition{fieldEquals("name", name)}, func(data clickhouseclient.Row) error {
 		uuid, err = data.GetString("id")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'id' field")
@@ -161,22 +212,27 @@ func (i *impl) FindRoleByName(ctx context.Context, name string, clusterName *str
 }
 
 func (i *impl) UpdateRole(ctx context.Context, role Role, clusterName *string) (*Role, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Retrieve current role
 	existing, err := i.GetRole(ctx, role.ID, clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "Unable to get existing role")
 	}
 
-	sql, err := querybuilder.
+	builder := querybuilder.
 		NewAlterRole(existing.Name).
 		WithCluster(clusterName).
-		RenameTo(&role.Name).
-		Build()
+		RenameTo(&role.Name)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
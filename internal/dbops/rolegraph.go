@@ -0,0 +1,133 @@
+package dbops
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/rolegraph"
+)
+
+// GetRoleGraph returns every user and role of the server and the roles granted to them.
+func (i *impl) GetRoleGraph(ctx context.Context, clusterName *string) (*rolegraph.Graph, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := rolegraph.New()
+
+	for table, kind := range map[snapshotTable]string{snapshotUsers: rolegraph.KindUser, snapshotRoles: rolegraph.KindRole} {
+		builder := querybuilder.NewSelect([]querybuilder.Field{querybuilder.NewField("name")}, string(table)).WithCluster(clusterName)
+		sql, err := builder.Build()
+		if err != nil {
+			return nil, errors.WithMessage(err, "error building query")
+		}
+
+		err = i.selectAccess(ctx, sql, builder.Parameters(), table, clusterName, nil, func(data clickhouseclient.Row) error {
+			name, err := data.GetString("name")
+			if err != nil {
+				return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+			}
+			graph.AddNode(rolegraph.Node{Name: name, Kind: kind})
+			return nil
+		})
+		if err != nil {
+			return nil, errors.WithMessage(err, "error running query")
+		}
+	}
+
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("granted_role_name"),
+			querybuilder.NewField("user_name"),
+			querybuilder.NewField("role_name"),
+			querybuilder.NewField("with_admin_option"),
+		},
+		string(snapshotRoleGrants)).
+		WithCluster(clusterName)
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotRoleGrants, clusterName, nil, func(data clickhouseclient.Row) error {
+		grant, err := scanGrantRole(data)
+		if err != nil {
+			return err
+		}
+
+		edge := rolegraph.Edge{Role: grant.RoleName, AdminOption: grant.AdminOption}
+		switch {
+		case grant.GranteeUserName != nil:
+			edge.Grantee = rolegraph.Node{Name: *grant.GranteeUserName, Kind: rolegraph.KindUser}
+		case grant.GranteeRoleName != nil:
+			edge.Grantee = rolegraph.Node{Name: *grant.GranteeRoleName, Kind: rolegraph.KindRole}
+		default:
+			return nil
+		}
+		graph.AddEdge(edge)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return graph, nil
+}
+
+// RoleGrantPlan is the change a resource plans to the role grants of the server.
+type RoleGrantPlan struct {
+	// ReplacedRoles are roles whose grants are all replaced by Added.
+	ReplacedRoles []string
+	// Removed are grants the resource revokes, as when it is destroyed or replaced.
+	Removed []rolegraph.Edge
+	Added   []rolegraph.Edge
+}
+
+// PlannedRoleGrants holds the role grant changes planned so far by the resources of a plan, by
+// cluster, so that a cycle formed by the grants of several resources is caught too. The zero value
+// is ready to use.
+type PlannedRoleGrants struct {
+	mu    sync.Mutex
+	plans map[string]map[string]RoleGrantPlan
+}
+
+// PlanRoleGrants records plan as the change of resource, replacing the one it planned before. accept
+// is called with the changes planned by the other resources of the same cluster: plan is only
+// recorded when it returns true. A nil accept always records plan.
+func (p *PlannedRoleGrants) PlanRoleGrants(clusterName *string, resource string, plan RoleGrantPlan, accept func(others []RoleGrantPlan) bool) {
+	cluster := ""
+	if clusterName != nil {
+		cluster = *clusterName
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.plans == nil {
+		p.plans = make(map[string]map[string]RoleGrantPlan)
+	}
+	if p.plans[cluster] == nil {
+		p.plans[cluster] = make(map[string]RoleGrantPlan)
+	}
+	plans := p.plans[cluster]
+	delete(plans, resource)
+
+	if accept != nil {
+		others := make([]RoleGrantPlan, 0, len(plans))
+		for _, name := range slices.Sorted(maps.Keys(plans)) {
+			others = append(others, plans[name])
+		}
+		if !accept(others) {
+			return
+		}
+	}
+
+	plans[resource] = plan
+}
@@ -3,7 +3,6 @@ package dbops
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/pingcap/errors"
 
@@ -24,31 +23,43 @@ type RowPolicy struct {
 }
 
 func (i *impl) CreateRowPolicy(ctx context.Context, rp RowPolicy, clusterName *string) (*RowPolicy, error) {
-	sql, err := querybuilder.NewCreateRowPolicy(rp.Name, rp.Database, rp.Table).
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.NewCreateRowPolicy(rp.Name, rp.Database, rp.Table).
 		WithCluster(clusterName).
 		SelectFilter(rp.SelectFilter).
 		IsRestrictive(rp.IsRestrictive).
 		GranteeNames(rp.GranteeNames).
 		GranteeAll(rp.GranteeAll).
-		GranteeAllExcept(rp.GranteeAllExcept).
-		Build()
+		GranteeAllExcept(rp.GranteeAllExcept)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error running query")
+		return partiallyCreated(err, func() (*RowPolicy, error) {
+			return i.GetRowPolicy(ctx, &rp, clusterName)
+		})
 	}
 
 	identifier := fmt.Sprintf("%s ON %s.%s", rp.Name, rp.Database, rp.Table)
 
-	return retryWithBackoff(ctx, "row policy", identifier, func() (*RowPolicy, error) {
+	return retryWithBackoff(ctx, "row policy", identifier, func(ctx context.Context) (*RowPolicy, error) {
 		return i.GetRowPolicy(ctx, &rp, clusterName)
 	})
 }
 
 func (i *impl) GetRowPolicy(ctx context.Context, rp *RowPolicy, clusterName *string) (*RowPolicy, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	return i.getRowPolicyWhere(ctx, []querybuilder.Where{
 		querybuilder.WhereEquals("short_name", rp.Name),
 		querybuilder.WhereEquals("database", rp.Database),
@@ -57,13 +68,18 @@ func (i *impl) GetRowPolicy(ctx context.Context, rp *RowPolicy, clusterName *str
 }
 
 func (i *impl) GetRowPolicyByID(ctx context.Context, id string, clusterName *string) (*RowPolicy, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	return i.getRowPolicyWhere(ctx, []querybuilder.Where{
 		querybuilder.WhereEquals("id", id),
 	}, clusterName)
 }
 
 func (i *impl) getRowPolicyWhere(ctx context.Context, where []querybuilder.Where, clusterName *string) (*RowPolicy, error) {
-	sql, err := querybuilder.NewSelect(
+	builder := querybuilder.NewSelect(
 		[]querybuilder.Field{
 			querybuilder.NewField("id").ToString(),
 			querybuilder.NewField("short_name"),
@@ -72,14 +88,12 @@ func (i *impl) getRowPolicyWhere(ctx context.Context, where []querybuilder.Where
 			querybuilder.NewField("select_filter"),
 			querybuilder.NewField("is_restrictive"),
 			querybuilder.NewField("apply_to_all"),
-			// apply_to_list/apply_to_except are Array(String); flatten to a newline-joined scalar so
-			// they read back as a plain String on both the native and http transports (the http
-			// JSONCompactStrings parser has no Array(String) case).
-			querybuilder.NewRawField("arrayStringConcat(apply_to_list, '\\n')", "apply_to_list"),
-			querybuilder.NewRawField("arrayStringConcat(apply_to_except, '\\n')", "apply_to_except"),
+			querybuilder.NewField("apply_to_list"),
+			querybuilder.NewField("apply_to_except"),
 		},
 		"system.row_policies",
-	).WithCluster(clusterName).Where(where...).Build()
+	).WithCluster(clusterName).Where(where...)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
@@ -128,12 +142,12 @@ func (i *impl) getRowPolicyWhere(ctx context.Context, where []querybuilder.Where
 			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_all' field")
 		}
 
-		applyToList, err := data.GetString("apply_to_list")
+		applyToList, err := data.GetStringArray("apply_to_list")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_list' field")
 		}
 
-		applyToExcept, err := data.GetString("apply_to_except")
+		applyToExcept, err := data.GetStringArray("apply_to_except")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'apply_to_except' field")
 		}
@@ -146,12 +160,12 @@ func (i *impl) getRowPolicyWhere(ctx context.Context, where []querybuilder.Where
 			SelectFilter:     selectFilter,
 			IsRestrictive:    isRestrictive,
 			GranteeAll:       applyToAll,
-			GranteeNames:     splitNonEmpty(applyToList),
-			GranteeAllExcept: splitNonEmpty(applyToExcept),
+			GranteeNames:     nilIfEmpty(applyToList),
+			GranteeAllExcept: nilIfEmpty(applyToExcept),
 		}
 
 		return nil
-	})
+	}, builder.Parameters())
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
@@ -159,17 +173,21 @@ func (i *impl) getRowPolicyWhere(ctx context.Context, where []querybuilder.Where
 	return result, nil
 }
 
-// splitNonEmpty splits a newline-joined string into its parts, returning nil for the empty string
-// (so an empty grantee list maps to a null Terraform set rather than [""]).
-func splitNonEmpty(s string) []string {
-	if s == "" {
+// nilIfEmpty returns nil for an empty list (so an empty grantee list maps to a null Terraform set).
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
 		return nil
 	}
-	return strings.Split(s, "\n")
+	return s
 }
 
 // UpdateRowPolicy re-asserts the full desired policy (name, filter, restrictiveness and grantees) in a single ALTER ROW POLICY.
 func (i *impl) UpdateRowPolicy(ctx context.Context, rp RowPolicy, clusterName *string) (*RowPolicy, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	existing, err := i.GetRowPolicyByID(ctx, rp.ID, clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "unable to get existing row policy")
@@ -198,17 +216,22 @@ func (i *impl) UpdateRowPolicy(ctx context.Context, rp RowPolicy, clusterName *s
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	return retryWithBackoff(ctx, "row policy", rp.ID, func() (*RowPolicy, error) {
+	return retryWithBackoff(ctx, "row policy", rp.ID, func(ctx context.Context) (*RowPolicy, error) {
 		return i.GetRowPolicyByID(ctx, rp.ID, clusterName)
 	})
 }
 
 func (i *impl) DeleteRowPolicy(ctx context.Context, id string, clusterName *string) error {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	rp, err := i.GetRowPolicyByID(ctx, id, clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error getting row policy")
@@ -219,15 +242,15 @@ func (i *impl) DeleteRowPolicy(ctx context.Context, id string, clusterName *stri
 		return nil
 	}
 
-	sql, err := querybuilder.NewDropRowPolicy(rp.Name, rp.Database, rp.Table).
+	builder := querybuilder.NewDropRowPolicy(rp.Name, rp.Database, rp.Table).
 		WithCluster(clusterName).
-		IfExists(true).
-		Build()
+		IfExists(true)
+	sql, err := builder.Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
@@ -20,6 +20,11 @@ type Setting struct {
 }
 
 func (i *impl) CreateSetting(ctx context.Context, settingsProfileID string, setting Setting, clusterName *string, timeout time.Duration) (*Setting, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	settingsProfile, err := i.GetSettingsProfile(ctx, settingsProfileID, clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error getting settings profile")
@@ -29,25 +34,30 @@ func (i *impl) CreateSetting(ctx context.Context, settingsProfileID string, sett
 		return nil, errors.New(fmt.Sprintf("settings profile with id %q was not found", settingsProfileID))
 	}
 
-	sql, err := querybuilder.NewAlterSettingsProfile(settingsProfile.Name).
+	builder := querybuilder.NewAlterSettingsProfile(settingsProfile.Name).
 		WithCluster(clusterName).
-		AddSetting(setting.Name, setting.Value, setting.Min, setting.Max, setting.Writability).
-		Build()
+		AddSetting(setting.Name, setting.Value, setting.Min, setting.Max, setting.Writability)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
 
-	return retryWithBackoff(ctx, "setting", setting.Name, func() (*Setting, error) {
+	return retryWithBackoff(ctx, "setting", setting.Name, func(ctx context.Context) (*Setting, error) {
 		return i.GetSetting(ctx, settingsProfileID, setting.Name, clusterName)
 	}, timeout)
 }
 
 func (i *impl) GetSetting(ctx context.Context, settingsProfileID string, name string, clusterName *string) (*Setting, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	settingsProfile, err := i.GetSettingsProfile(ctx, settingsProfileID, clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error getting settings profile")
@@ -58,7 +68,7 @@ func (i *impl) GetSetting(ctx context.Context, settingsProfileID string, name st
 		return nil, nil
 	}
 
-	sql, err := querybuilder.NewSelect([]querybuilder.Field{
+	builder := querybuilder.NewSelect([]querybuilder.Field{
 		querybuilder.NewField("value"),
 		querybuilder.NewField("min"),
 		querybuilder.NewField("max"),
@@ -68,15 +78,19 @@ func (i *impl) GetSetting(ctx context.Context, settingsProfileID string, name st
 		Where(querybuilder.AndWhere(
 			querybuilder.WhereEquals("profile_name", settingsProfile.Name),
 			querybuilder.WhereEquals("setting_name", name),
-		)).
-		Build()
+		))
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var setting *Setting
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	conditions := []rowCondition{
+		fieldEquals("profile_name", settingsProfile.Name),
+		fieldEquals("setting_name", name),
+	}
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotSettingsProfileElements, clusterName, conditions, func(data clickhouseclient.Row) error {
 		value, err := data.GetNullableString("value")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'value' field")
@@ -117,6 +131,11 @@ func (i *impl) GetSetting(ctx context.Context, settingsProfileID string, name st
 }
 
 func (i *impl) DeleteSetting(ctx context.Context, settingsProfileID string, name string, clusterName *string) error {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	settingsProfile, err := i.GetSettingsProfile(ctx, settingsProfileID, clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error getting settings profile")
@@ -126,15 +145,15 @@ func (i *impl) DeleteSetting(ctx context.Context, settingsProfileID string, name
 		return errors.New(fmt.Sprintf("settings profile with id %q was not found", settingsProfileID))
 	}
 
-	sql, err := querybuilder.NewAlterSettingsProfile(settingsProfile.Name).
+	builder := querybuilder.NewAlterSettingsProfile(settingsProfile.Name).
 		WithCluster(clusterName).
-		RemoveSetting(name).
-		Build()
+		RemoveSetting(name)
+	sql, err := builder.Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
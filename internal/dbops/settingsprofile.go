@@ -14,22 +14,31 @@ type SettingsProfile struct {
 	ID          string   `json:"id"`
 	Name        string   `json:"name"`
 	InheritFrom []string `json:"-"`
+	// Divergence is set when the settings profile differs between the replicas of its cluster.
+	Divergence *ReplicaDivergence `json:"-"`
 }
 
 func (i *impl) CreateSettingsProfile(ctx context.Context, profile SettingsProfile, clusterName *string) (*SettingsProfile, error) {
-	sql, err := querybuilder.
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.
 		NewCreateSettingsProfile(profile.Name).
 		WithCluster(clusterName).
-		InheritFrom(profile.InheritFrom).
-		Build()
+		InheritFrom(profile.InheritFrom)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		if !isAlreadyExistsError(err) {
-			return nil, errors.WithMessage(err, "error running query")
+			return partiallyCreated(err, func() (*SettingsProfile, error) {
+				return i.FindSettingsProfileByName(ctx, profile.Name, clusterName)
+			})
 		}
 
 		// The settings profile already exists in ClickHouse, importing it instead of failing.
@@ -38,15 +47,20 @@ func (i *impl) CreateSettingsProfile(ctx context.Context, profile SettingsProfil
 		})
 	}
 
-	return retryWithBackoff(ctx, "settings profile", profile.Name, func() (*SettingsProfile, error) {
+	return retryWithBackoff(ctx, "settings profile", profile.Name, func(ctx context.Context) (*SettingsProfile, error) {
 		return i.FindSettingsProfileByName(ctx, profile.Name, clusterName)
 	}, i.readAfterWriteTimeoutArgs()...)
 }
 
 func (i *impl) GetSettingsProfile(ctx context.Context, id string, clusterName *string) (*SettingsProfile, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	var profile *SettingsProfile
 
-	sql, err := querybuilder.
+	builder := querybuilder.
 		NewSelect(
 			[]querybuilder.Field{
 				querybuilder.NewField("name"),
@@ -54,8 +68,8 @@ func (i *impl) GetSettingsProfile(ctx context.Context, id string, clusterName *s
 			"system.settings_profiles",
 		).
 		WithCluster(clusterName).
-		Where(querybuilder.WhereEquals("id", id)).
-		Build()
+		Where(querybuilder.WhereEquals("id", id))
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
@@ -74,7 +88,7 @@ func (i *impl) GetSettingsProfile(ctx context.Context, id string, clusterName *s
 		}
 
 		return nil
-	})
+	}, builder.Parameters())
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
@@ -86,15 +100,15 @@ func (i *impl) GetSettingsProfile(ctx context.Context, id string, clusterName *s
 
 	// Check roles this profile is inheriting from.
 	{
-		sql, err := querybuilder.
+		builder := querybuilder.
 			NewSelect([]querybuilder.Field{querybuilder.NewField("inherit_profile")}, "system.settings_profile_elements").
 			Where(querybuilder.WhereEquals("profile_name", profile.Name)).
-			OrderBy(querybuilder.NewField("index"), querybuilder.ASC).
-			Build()
+			OrderBy(querybuilder.NewField("index"), querybuilder.ASC)
+		sql, err := builder.Build()
 		if err != nil {
 			return nil, errors.WithMessage(err, "error building query")
 		}
-		err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotSettingsProfileElements, nil, []rowCondition{fieldEquals("profile_name", profile.Name)}, func(data clickhouseclient.Row) error {
 			inheritedProfileName, err := data.GetNullableString("inherit_profile")
 			if err != nil {
 				return errors.WithMessage(err, "error scanning query result, missing 'profile_name' field")
@@ -111,10 +125,24 @@ func (i *impl) GetSettingsProfile(ctx context.Context, id string, clusterName *s
 		}
 	}
 
+	profile.Divergence, err = i.checkReplicas(ctx, clusterName, replicaCheck{
+		table:   "system.settings_profiles",
+		name:    profile.Name,
+		columns: []string{"num_elements", "apply_to_all", "apply_to_list", "apply_to_except"},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error comparing replicas")
+	}
+
 	return profile, nil
 }
 
 func (i *impl) DeleteSettingsProfile(ctx context.Context, id string, clusterName *string) error {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	profile, err := i.GetSettingsProfile(ctx, id, clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error looking up settings profile name")
@@ -125,12 +153,13 @@ func (i *impl) DeleteSettingsProfile(ctx context.Context, id string, clusterName
 		return nil
 	}
 
-	sql, err := querybuilder.NewDropSettingsProfile(profile.Name).WithCluster(clusterName).Build()
+	builder := querybuilder.NewDropSettingsProfile(profile.Name).WithCluster(clusterName)
+	sql, err := builder.Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
@@ -139,6 +168,11 @@ func (i *impl) DeleteSettingsProfile(ctx context.Context, id string, clusterName
 }
 
 func (i *impl) UpdateSettingsProfile(ctx context.Context, settingsProfile SettingsProfile, clusterName *string) (*SettingsProfile, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Retrieve current setting profile
 	existing, err := i.GetSettingsProfile(ctx, settingsProfile.ID, clusterName)
 	if err != nil {
@@ -149,17 +183,17 @@ func (i *impl) UpdateSettingsProfile(ctx context.Context, settingsProfile Settin
 		return nil, nil
 	}
 
-	sql, err := querybuilder.
+	builder := querybuilder.
 		NewAlterSettingsProfile(existing.Name).
 		WithCluster(clusterName).
 		InheritFrom(settingsProfile.InheritFrom).
-		RenameTo(&settingsProfile.Name).
-		Build()
+		RenameTo(&settingsProfile.Name)
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
@@ -168,6 +202,11 @@ func (i *impl) UpdateSettingsProfile(ctx context.Context, settingsProfile Settin
 }
 
 func (i *impl) AssociateSettingsProfile(ctx context.Context, id string, roleId *string, userId *string, clusterName *string) error {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	profile, err := i.GetSettingsProfile(ctx, id, clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error looking up settings profile name")
@@ -186,16 +225,16 @@ func (i *impl) AssociateSettingsProfile(ctx context.Context, id string, roleId *
 		if role == nil {
 			return errors.New("role not found")
 		}
-		sql, err := querybuilder.
+		builder := querybuilder.
 			NewAlterRole(role.Name).
 			WithCluster(clusterName).
-			AddSettingsProfile(&profile.Name).
-			Build()
+			AddSettingsProfile(&profile.Name)
+		sql, err := builder.Build()
 		if err != nil {
 			return errors.WithMessage(err, "Error building query")
 		}
 
-		err = i.clickhouseClient.Exec(ctx, sql)
+		err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 		if err != nil {
 			return errors.WithMessage(err, "error running query")
 		}
@@ -211,16 +250,16 @@ func (i *impl) AssociateSettingsProfile(ctx context.Context, id string, roleId *
 			return errors.New("user not found")
 		}
 
-		sql, err := querybuilder.
+		builder := querybuilder.
 			NewAlterUser(user.Name).
 			WithCluster(clusterName).
-			AddSettingsProfile(&profile.Name).
-			Build()
+			AddSettingsProfile(&profile.Name)
+		sql, err := builder.Build()
 		if err != nil {
 			return errors.WithMessage(err, "Error building query")
 		}
 
-		err = i.clickhouseClient.Exec(ctx, sql)
+		err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 		if err != nil {
 			return errors.WithMessage(err, "error running query")
 		}
@@ -232,6 +271,11 @@ func (i *impl) AssociateSettingsProfile(ctx context.Context, id string, roleId *
 }
 
 func (i *impl) DisassociateSettingsProfile(ctx context.Context, id string, roleId *string, userId *string, clusterName *string) error {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	profile, err := i.GetSettingsProfile(ctx, id, clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error looking up settings profile name")
@@ -251,16 +295,16 @@ func (i *impl) DisassociateSettingsProfile(ctx context.Context, id string, roleI
 			return errors.New("role not found")
 		}
 
-		sql, err := querybuilder.
+		builder := querybuilder.
 			NewAlterRole(role.Name).
 			WithCluster(clusterName).
-			DropSettingsProfile(&profile.Name).
-			Build()
+			DropSettingsProfile(&profile.Name)
+		sql, err := builder.Build()
 		if err != nil {
 			return errors.WithMessage(err, "Error building query")
 		}
 
-		err = i.clickhouseClient.Exec(ctx, sql)
+		err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 		if err != nil {
 			return errors.WithMessage(err, "error running query")
 		}
@@ -276,16 +320,16 @@ func (i *impl) DisassociateSettingsProfile(ctx context.Context, id string, roleI
 			return errors.New("user not found")
 		}
 
-		sql, err := querybuilder.
+		builder := querybuilder.
 			NewAlterUser(user.Name).
 			WithCluster(clusterName).
-			DropSettingsProfile(&profile.Name).
-			Build()
+			DropSettingsProfile(&profile.Name)
+		sql, err := builder.Build()
 		if err != nil {
 			return errors.WithMessage(err, "Error building query")
 		}
 
-		err = i.clickhouseClient.Exec(ctx, sql)
+		err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 		if err != nil {
 			return errors.WithMessage(err, "error running query")
 		}
@@ -297,7 +341,12 @@ func (i *impl) DisassociateSettingsProfile(ctx context.Context, id string, roleI
 }
 
 func (i *impl) FindSettingsProfileByName(ctx context.Context, name string, clusterName *string) (*SettingsProfile, error) {
-	sql, err := querybuilder.
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.
 		NewSelect(
 			[]querybuilder.Field{
 				querybuilder.NewField("id").ToString(),
@@ -305,8 +354,8 @@ func (i *impl) FindSettingsProfileByName(ctx context.Context, name string, clust
 			"system.settings_profiles",
 		).
 		WithCluster(clusterName).
-		Where(querybuilder.WhereEquals("name", name)).
-		Build()
+		Where(querybuilder.WhereEquals("name", name))
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
@@ -322,7 +371,7 @@ func (i *impl) FindSettingsProfileByName(ctx context.Context, name string, clust
 		settingsProfileID = id
 
 		return nil
-	})
+	}, builder.Parameters())
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
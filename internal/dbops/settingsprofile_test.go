@@ -3,6 +3,7 @@ package dbops
 import (
 	"context"
 	"fmt"
+	"maps"
 	"testing"
 	"time"
 
@@ -19,9 +20,14 @@ const (
 
 const (
 	createProfileSQL = "CREATE SETTINGS PROFILE `test-14xyz-use1_readwrite_default`;"
-	findByNameSQL    = "SELECT toString(`id`) AS `id` FROM `system`.`settings_profiles` WHERE (`name` = 'test-14xyz-use1_readwrite_default');"
-	getByIDSQL       = "SELECT `name` FROM `system`.`settings_profiles` WHERE (`id` = '0e4a8a40-dd60-b0d9-7baa-d44f89302d8d');"
-	inheritSQL       = "SELECT `inherit_profile` FROM `system`.`settings_profile_elements` WHERE (`profile_name` = 'test-14xyz-use1_readwrite_default') ORDER BY `index` ASC;"
+	findByNameSQL    = "SELECT toString(`id`) AS `id` FROM `system`.`settings_profiles` WHERE (`name` = {value_0:String});"
+	getByIDSQL       = "SELECT `name` FROM `system`.`settings_profiles` WHERE (`id` = {value_0:String});"
+	inheritSQL       = "SELECT `inherit_profile` FROM `system`.`settings_profile_elements` WHERE (`profile_name` = {value_0:String}) ORDER BY `index` ASC;"
+)
+
+var (
+	nameParams = map[string]string{"value_0": testProfileName}
+	idParams   = map[string]string{"value_0": testProfileID}
 )
 
 var httpAlreadyExistsErr = errors.WithMessage(
@@ -42,9 +48,10 @@ var nativeAlreadyExistsErr = errors.WithMessage(
 )
 
 type step struct {
-	wantSQL string
-	rows    map[string]string
-	err     error
+	wantSQL    string
+	wantParams map[string]string
+	rows       map[string]string
+	err        error
 }
 
 // scriptedClient serves queries from an ordered script and fails the test on any deviation.
@@ -53,7 +60,7 @@ type scriptedClient struct {
 	steps []step
 }
 
-func (c *scriptedClient) next(sql string) step {
+func (c *scriptedClient) next(sql string, params []map[string]string) step {
 	c.t.Helper()
 	if len(c.steps) == 0 {
 		c.t.Fatalf("unexpected query: %s", sql)
@@ -63,15 +70,22 @@ func (c *scriptedClient) next(sql string) step {
 	if sql != s.wantSQL {
 		c.t.Fatalf("query mismatch:\nwant: %s\ngot:  %s", s.wantSQL, sql)
 	}
+	var got map[string]string
+	if len(params) > 0 {
+		got = params[0]
+	}
+	if !maps.Equal(got, s.wantParams) {
+		c.t.Fatalf("parameters mismatch for %s:\nwant: %v\ngot:  %v", sql, s.wantParams, got)
+	}
 	return s
 }
 
-func (c *scriptedClient) Exec(_ context.Context, sql string, _ ...map[string]string) error {
-	return c.next(sql).err
+func (c *scriptedClient) Exec(_ context.Context, sql string, params ...map[string]string) error {
+	return c.next(sql, params).err
 }
 
-func (c *scriptedClient) Select(_ context.Context, sql string, callback func(clickhouseclient.Row) error) error {
-	s := c.next(sql)
+func (c *scriptedClient) Select(_ context.Context, sql string, callback func(clickhouseclient.Row) error, params ...map[string]string) error {
+	s := c.next(sql, params)
 	if s.err != nil {
 		return s.err
 	}
@@ -98,11 +112,11 @@ func newTestClient(t *testing.T, script []step) (Client, *scriptedClient) {
 }
 
 func TestCreateSettingsProfile(t *testing.T) {
-	miss := step{wantSQL: findByNameSQL}
+	miss := step{wantSQL: findByNameSQL, wantParams: nameParams}
 	found := []step{
-		{wantSQL: findByNameSQL, rows: map[string]string{"id": testProfileID}},
-		{wantSQL: getByIDSQL, rows: map[string]string{"name": testProfileName}},
-		{wantSQL: inheritSQL},
+		{wantSQL: findByNameSQL, wantParams: nameParams, rows: map[string]string{"id": testProfileID}},
+		{wantSQL: getByIDSQL, wantParams: idParams, rows: map[string]string{"name": testProfileName}},
+		{wantSQL: inheritSQL, wantParams: nameParams},
 	}
 
 	tests := []struct {
@@ -197,7 +211,7 @@ func TestIsAlreadyExistsError(t *testing.T) {
 // TestFindSettingsProfileByName_NotFound verifies the not-found contract used by retryWithBackoff:
 // a lookup miss returns (nil, nil) rather than an error, so callers keep retrying within their backoff.
 func TestFindSettingsProfileByName_NotFound(t *testing.T) {
-	client, _ := newTestClient(t, []step{{wantSQL: findByNameSQL}})
+	client, _ := newTestClient(t, []step{{wantSQL: findByNameSQL, wantParams: nameParams}})
 
 	profile, err := client.FindSettingsProfileByName(context.Background(), testProfileName, nil)
 	if err != nil {
@@ -0,0 +1,252 @@
+package dbops
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+// snapshotTable is a system table of access entities that can be served from the snapshot cache.
+type snapshotTable string
+
+const (
+	snapshotGrants                  snapshotTable = "system.grants"
+	snapshotRoleGrants              snapshotTable = "system.role_grants"
+	snapshotUsers                   snapshotTable = "system.users"
+	snapshotRoles                   snapshotTable = "system.roles"
+	snapshotSettingsProfileElements snapshotTable = "system.settings_profile_elements"
+)
+
+// snapshotQueries build the query loading each table. Columns have the names and types used by the
+// individual lookups, so that their scan callbacks work unchanged on snapshot rows.
+var snapshotQueries = map[snapshotTable]func() querybuilder.SelectQueryBuilder{
+	snapshotGrants: func() querybuilder.SelectQueryBuilder {
+		return querybuilder.NewSelect([]querybuilder.Field{
+			querybuilder.NewField("access_type").ToString(),
+			querybuilder.NewField("database"),
+			querybuilder.NewField("table"),
+			querybuilder.NewField("column"),
+			// Alias must differ from the column name or ClickHouse substitutes it into WHERE.
+			querybuilder.NewRawField("nullIf(access_object, '')", "access_object_nullable"),
+			querybuilder.NewField("user_name"),
+			querybuilder.NewField("role_name"),
+			querybuilder.NewField("grant_option"),
+		}, string(snapshotGrants)).Where(querybuilder.WhereEquals("is_partial_revoke", 0))
+	},
+	snapshotRoleGrants: func() querybuilder.SelectQueryBuilder {
+		return querybuilder.NewSelect([]querybuilder.Field{
+			querybuilder.NewField("granted_role_name"),
+			querybuilder.NewField("user_name"),
+			querybuilder.NewField("role_name"),
+			querybuilder.NewField("with_admin_option"),
+		}, string(snapshotRoleGrants))
+	},
+	snapshotUsers: func() querybuilder.SelectQueryBuilder {
+		return querybuilder.NewSelect([]querybuilder.Field{
+			querybuilder.NewField("id").ToString(),
+			querybuilder.NewField("name"),
+		}, string(snapshotUsers))
+	},
+	snapshotRoles: func() querybuilder.SelectQueryBuilder {
+		return querybuilder.NewSelect([]querybuilder.Field{
+			querybuilder.NewField("id").ToString(),
+			querybuilder.NewField("name"),
+		}, string(snapshotRoles))
+	},
+	snapshotSettingsProfileElements: func() querybuilder.SelectQueryBuilder {
+		return querybuilder.NewSelect([]querybuilder.Field{
+			querybuilder.NewField("profile_name"),
+			querybuilder.NewField("user_name"),
+			querybuilder.NewField("role_name"),
+			querybuilder.NewField("setting_name"),
+			querybuilder.NewField("value"),
+			querybuilder.NewField("min"),
+			querybuilder.NewField("max"),
+			querybuilder.NewField("writability").ToString(),
+			querybuilder.NewField("inherit_profile"),
+		}, string(snapshotSettingsProfileElements)).OrderBy(querybuilder.NewField("index"), querybuilder.ASC)
+	},
+}
+
+type snapshotKey struct {
+	table   snapshotTable
+	cluster string
+}
+
+type snapshotEntry struct {
+	once sync.Once
+	rows []clickhouseclient.Row
+	err  error
+}
+
+// snapshotCache holds complete copies of the system access tables, loaded on first use, so that
+// refreshing thousands of resources costs one query per table instead of one query per resource.
+// Any write drops all copies: lookups following it load the tables again. Lookups waiting for a write
+// to be visible query the server instead, see withoutSnapshot.
+type snapshotCache struct {
+	mu      sync.Mutex
+	entries map[snapshotKey]*snapshotEntry
+}
+
+func newSnapshotCache() *snapshotCache {
+	return &snapshotCache{
+		entries: make(map[snapshotKey]*snapshotEntry),
+	}
+}
+
+// rows returns the rows of table, loading them with client if they are not cached yet.
+func (c *snapshotCache) rows(ctx context.Context, client clickhouseclient.ClickhouseClient, table snapshotTable, clusterName *string) ([]clickhouseclient.Row, error) {
+	key := snapshotKey{table: table}
+	if clusterName != nil {
+		key.cluster = *clusterName
+	}
+
+	return c.load(key, func() ([]clickhouseclient.Row, error) {
+		return loadSnapshot(ctx, client, table, clusterName)
+	})
+}
+
+// load returns the rows cached under key, calling loader if they are not cached yet.
+func (c *snapshotCache) load(key snapshotKey, loader func() ([]clickhouseclient.Row, error)) ([]clickhouseclient.Row, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &snapshotEntry{}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.rows, entry.err = loader()
+		if entry.err != nil {
+			// Let the next lookup try again.
+			c.mu.Lock()
+			if c.entries[key] == entry {
+				delete(c.entries, key)
+			}
+			c.mu.Unlock()
+		}
+	})
+
+	return entry.rows, entry.err
+}
+
+// invalidate drops all cached tables.
+func (c *snapshotCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[snapshotKey]*snapshotEntry)
+}
+
+func loadSnapshot(ctx context.Context, client clickhouseclient.ClickhouseClient, table snapshotTable, clusterName *string) ([]clickhouseclient.Row, error) {
+	builder := snapshotQueries[table]().WithCluster(clusterName)
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	rows := make([]clickhouseclient.Row, 0)
+	err = client.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		rows = append(rows, data)
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error loading snapshot of "+string(table))
+	}
+
+	return rows, nil
+}
+
+// rowCondition is the counterpart of a WHERE condition, applied to rows served from the snapshot.
+type rowCondition func(row clickhouseclient.Row) (bool, error)
+
+// fieldEquals matches rows where field is not NULL and equals value.
+func fieldEquals(field string, value string) rowCondition {
+	return func(row clickhouseclient.Row) (bool, error) {
+		got, err := row.GetNullableString(field)
+		if err != nil {
+			return false, err
+		}
+		return got != nil && *got == value, nil
+	}
+}
+
+// fieldIn matches rows where field is one of values.
+func fieldIn(field string, values []string) rowCondition {
+	return func(row clickhouseclient.Row) (bool, error) {
+		got, err := row.GetNullableString(field)
+		if err != nil {
+			return false, err
+		}
+		if got == nil {
+			return false, nil
+		}
+		for _, v := range values {
+			if *got == v {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// fieldEqualsOrNull is the counterpart of valOrNullWhere.
+func fieldEqualsOrNull(field string, value *string) rowCondition {
+	if value != nil {
+		return fieldEquals(field, *value)
+	}
+
+	return func(row clickhouseclient.Row) (bool, error) {
+		got, err := row.GetNullableString(field)
+		if err != nil {
+			return false, err
+		}
+		return got == nil, nil
+	}
+}
+
+type withoutSnapshotKey struct{}
+
+// withoutSnapshot returns a context whose lookups query the server even when the snapshot cache is
+// enabled. Lookups retried until a write is visible use it.
+func withoutSnapshot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutSnapshotKey{}, true)
+}
+
+// selectAccess runs sql and calls callback with every row, or, when the snapshot cache is enabled,
+// calls callback with the rows of the snapshot of table matching all conditions. The conditions must
+// select the same rows as the WHERE clause of sql.
+func (i *impl) selectAccess(ctx context.Context, sql string, params map[string]string, table snapshotTable, clusterName *string, conditions []rowCondition, callback func(clickhouseclient.Row) error) error {
+	if i.snapshots == nil || ctx.Value(withoutSnapshotKey{}) != nil {
+		return i.clickhouseClient.Select(ctx, sql, callback, params)
+	}
+
+	rows, err := i.snapshots.rows(ctx, i.clickhouseClient, table, clusterName)
+	if err != nil {
+		return err
+	}
+
+rows:
+	for _, row := range rows {
+		for _, condition := range conditions {
+			ok, err := condition(row)
+			if err != nil {
+				return errors.WithMessage(err, "error filtering snapshot of "+string(table))
+			}
+			if !ok {
+				continue rows
+			}
+		}
+
+		if err := callback(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
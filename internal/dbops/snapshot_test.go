@@ -0,0 +1,248 @@
+package dbops
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+)
+
+// tableClient serves every SELECT from the rows of the system table it reads and counts queries.
+type tableClient struct {
+	tables  map[snapshotTable][]map[string]any
+	selects int
+	execs   int
+	// queries are the SELECTs run, in order.
+	queries []string
+	// failNext makes the next SELECT fail.
+	failNext bool
+	// lagging rows are added to tables after lag SELECTs, like a write the server does not show yet.
+	lagging map[snapshotTable][]map[string]any
+	lag     int
+}
+
+func (c *tableClient) Exec(_ context.Context, _ string, _ ...map[string]string) error {
+	c.execs++
+	return nil
+}
+
+func (c *tableClient) Select(_ context.Context, sql string, callback func(clickhouseclient.Row) error, _ ...map[string]string) error {
+	c.selects++
+	c.queries = append(c.queries, sql)
+	if c.lagging != nil && c.selects > c.lag {
+		for table, rows := range c.lagging {
+			c.tables[table] = append(c.tables[table], rows...)
+		}
+		c.lagging = nil
+	}
+	if c.failNext {
+		c.failNext = false
+		return errors.New("Code: 159. DB::Exception: Timeout exceeded (TIMEOUT_EXCEEDED)")
+	}
+
+	for table, rows := range c.tables {
+		tokens := strings.Split(string(table), ".")
+		if !strings.Contains(sql, "`"+tokens[0]+"`.`"+tokens[1]+"`") {
+			continue
+		}
+		for _, fields := range rows {
+			row := clickhouseclient.Row{}
+			for field, value := range fields {
+				row.Set(field, value)
+			}
+			if err := callback(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func newSnapshotTestClient(t *testing.T, fake *tableClient) Client {
+	t.Helper()
+
+	client, err := NewClient(fake, WithSnapshotCache())
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	return client
+}
+
+func TestSnapshotCache_GetGrantRole(t *testing.T) {
+	ctx := context.Background()
+	fake := &tableClient{
+		tables: map[snapshotTable][]map[string]any{
+			snapshotRoleGrants: {
+				{"granted_role_name": "reader", "user_name": "alice", "role_name": nil, "with_admin_option": uint64(0)},
+				{"granted_role_name": "writer", "user_name": nil, "role_name": "ops", "with_admin_option": uint64(1)},
+			},
+		},
+	}
+	client := newSnapshotTestClient(t, fake)
+
+	got, err := client.GetGrantRole(ctx, "writer", nil, new("ops"), nil)
+	if err != nil {
+		t.Fatalf("GetGrantRole() error = %v", err)
+	}
+	want := &GrantRole{RoleName: "writer", GranteeRoleName: new("ops"), AdminOption: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetGrantRole() want = %v, got %v", want, got)
+	}
+
+	got, err = client.GetGrantRole(ctx, "reader", new("bob"), nil, nil)
+	if err != nil {
+		t.Fatalf("GetGrantRole() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("GetGrantRole() want = nil, got %v", got)
+	}
+
+	if fake.selects != 1 {
+		t.Errorf("want 1 query loading the snapshot, got %d", fake.selects)
+	}
+
+	// A write drops the snapshot, the next lookup loads it again.
+	if err := client.RevokeGrantRole(ctx, "reader", new("alice"), nil, nil); err != nil {
+		t.Fatalf("RevokeGrantRole() error = %v", err)
+	}
+	if _, err := client.GetGrantRole(ctx, "reader", new("alice"), nil, nil); err != nil {
+		t.Fatalf("GetGrantRole() error = %v", err)
+	}
+	if fake.selects != 2 {
+		t.Errorf("want 2 queries after a write, got %d", fake.selects)
+	}
+}
+
+func TestSnapshotCache_GetAllGrantsForGrantee(t *testing.T) {
+	ctx := context.Background()
+	fake := &tableClient{
+		tables: map[snapshotTable][]map[string]any{
+			snapshotGrants: {
+				{"access_type": "SELECT", "database": "db", "table": nil, "column": nil, "access_object_nullable": nil, "user_name": "alice", "role_name": nil, "grant_option": uint64(0)},
+				{"access_type": "INSERT", "database": "db", "table": "t", "column": nil, "access_object_nullable": nil, "user_name": nil, "role_name": "ops", "grant_option": uint64(1)},
+			},
+		},
+	}
+	client := newSnapshotTestClient(t, fake)
+
+	got, err := client.GetAllGrantsForGrantee(ctx, nil, new("ops"), nil)
+	if err != nil {
+		t.Fatalf("GetAllGrantsForGrantee() error = %v", err)
+	}
+	want := []GrantPrivilege{
+		{AccessType: "INSERT", DatabaseName: new("db"), TableName: new("t"), GranteeRoleName: new("ops"), GrantOption: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllGrantsForGrantee() want = %v, got %v", want, got)
+	}
+
+	// Snapshots are kept per cluster.
+	if _, err := client.GetAllGrantsForGrantee(ctx, new("alice"), nil, new("default")); err != nil {
+		t.Fatalf("GetAllGrantsForGrantee() error = %v", err)
+	}
+	if fake.selects != 2 {
+		t.Errorf("want 2 queries for 2 clusters, got %d", fake.selects)
+	}
+}
+
+func TestSnapshotCache_FailedLoadIsRetried(t *testing.T) {
+	ctx := context.Background()
+	fake := &tableClient{
+		tables: map[snapshotTable][]map[string]any{
+			snapshotUsers: {
+				{"id": "d3b6c6a0-0000-4000-8000-000000000001", "name": "alice"},
+			},
+			snapshotSettingsProfileElements: {
+				{"profile_name": nil, "user_name": "alice", "role_name": nil, "setting_name": nil, "value": nil, "min": nil, "max": nil, "writability": nil, "inherit_profile": "readonly"},
+			},
+		},
+		failNext: true,
+	}
+	client := newSnapshotTestClient(t, fake)
+
+	if _, err := client.FindUserByName(ctx, "alice", nil); err == nil {
+		t.Fatalf("FindUserByName() want error, got nil")
+	}
+
+	got, err := client.FindUserByName(ctx, "alice", nil)
+	if err != nil {
+		t.Fatalf("FindUserByName() error = %v", err)
+	}
+	want := &User{ID: "d3b6c6a0-0000-4000-8000-000000000001", Name: "alice", SettingsProfiles: []string{"readonly"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindUserByName() want = %v, got %v", want, got)
+	}
+}
+
+func TestSnapshotCache_ReadAfterWriteBypassesSnapshot(t *testing.T) {
+	ctx := context.Background()
+	fake := &tableClient{
+		tables: map[snapshotTable][]map[string]any{
+			snapshotRoles: {},
+		},
+		// The role is only visible from the third query.
+		lagging: map[snapshotTable][]map[string]any{
+			snapshotRoles: {{"id": "d3b6c6a0-0000-4000-8000-000000000001", "name": "reader"}},
+		},
+		lag: 2,
+	}
+	client := newSnapshotTestClient(t, fake)
+
+	got, err := client.CreateRole(ctx, Role{Name: "reader"}, nil)
+	if err != nil {
+		t.Fatalf("CreateRole() error = %v", err)
+	}
+	if got == nil || got.Name != "reader" {
+		t.Errorf("CreateRole() want role reader, got %v", got)
+	}
+}
+
+func TestSnapshotCache_ReplicaCheckSharesOneQuery(t *testing.T) {
+	ctx := context.Background()
+	fake := &tableClient{
+		tables: map[snapshotTable][]map[string]any{
+			"system.one": {
+				{"replica_host": "ch-1"},
+				{"replica_host": "ch-2"},
+			},
+			snapshotRoles: {
+				{"id": "d3b6c6a0-0000-4000-8000-000000000001", "name": "reader", "replica_host": "ch-1", "fingerprint": "('reader')"},
+				{"id": "d3b6c6a0-0000-4000-8000-000000000001", "name": "reader", "replica_host": "ch-2", "fingerprint": "('reader')"},
+				{"id": "d3b6c6a0-0000-4000-8000-000000000002", "name": "writer", "replica_host": "ch-1", "fingerprint": "('writer')"},
+			},
+		},
+	}
+	client := newSnapshotTestClient(t, fake)
+
+	reader, err := client.GetRole(ctx, "d3b6c6a0-0000-4000-8000-000000000001", new("c"))
+	if err != nil {
+		t.Fatalf("GetRole() error = %v", err)
+	}
+	if reader == nil || reader.Divergence != nil {
+		t.Errorf("GetRole() want reader on all replicas, got %+v", reader)
+	}
+
+	writer, err := client.GetRole(ctx, "d3b6c6a0-0000-4000-8000-000000000002", new("c"))
+	if err != nil {
+		t.Fatalf("GetRole() error = %v", err)
+	}
+	want := &ReplicaDivergence{Missing: []string{"ch-2"}}
+	if writer == nil || !reflect.DeepEqual(writer.Divergence, want) {
+		t.Errorf("GetRole() want divergence %+v, got %+v", want, writer)
+	}
+
+	checks := 0
+	for _, sql := range fake.queries {
+		if strings.Contains(sql, "fingerprint") {
+			checks++
+		}
+	}
+	if checks != 1 {
+		t.Errorf("want 1 replica check query, got %d: %v", checks, fake.queries)
+	}
+}
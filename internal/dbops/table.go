@@ -0,0 +1,499 @@
+package dbops
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+type Table struct {
+	Database string
+	Name     string
+	Columns  []TableColumn
+	// Engine is the engine with its arguments, such as `ReplicatedMergeTree('/path', '{replica}')`.
+	// GetTable only returns the name of the engine.
+	Engine string
+	// GetTable returns OrderBy, PartitionBy, PrimaryKey, SampleBy and TTL as formatted by ClickHouse, and
+	// Settings with the defaults ClickHouse adds, such as index_granularity.
+	OrderBy     *string
+	PartitionBy *string
+	PrimaryKey  *string
+	SampleBy    *string
+	TTL         *string
+	Settings    map[string]string
+	Comment     string
+}
+
+type TableColumn struct {
+	Name string
+	Type string
+	// DefaultKind is one of DEFAULT, MATERIALIZED, ALIAS or EPHEMERAL. DEFAULT applies when it is nil.
+	DefaultKind *string
+	Default     *string
+	// Codec is the list of codecs, without the CODEC(...) wrapper.
+	Codec   *string
+	Comment *string
+	// TTL is not returned by GetTable.
+	TTL *string
+}
+
+func (c TableColumn) definition() querybuilder.ColumnDefinition {
+	return querybuilder.ColumnDefinition{
+		Name:        c.Name,
+		Type:        c.Type,
+		DefaultKind: c.DefaultKind,
+		Default:     c.Default,
+		Codec:       c.Codec,
+		Comment:     c.Comment,
+		TTL:         c.TTL,
+	}
+}
+
+func (i *impl) CreateTable(ctx context.Context, table Table, clusterName *string) (*Table, error) {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]querybuilder.ColumnDefinition, 0, len(table.Columns))
+	for _, c := range table.Columns {
+		columns = append(columns, c.definition())
+	}
+
+	var comment *string
+	if table.Comment != "" {
+		comment = &table.Comment
+	}
+
+	builder := querybuilder.NewCreateTable(table.Database, table.Name).
+		WithCluster(clusterName).
+		WithColumns(columns).
+		WithEngine(table.Engine).
+		WithOrderBy(table.OrderBy).
+		WithPartitionBy(table.PartitionBy).
+		WithPrimaryKey(table.PrimaryKey).
+		WithSampleBy(table.SampleBy).
+		WithTTL(table.TTL).
+		WithSettings(table.Settings).
+		WithComment(comment)
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		return partiallyCreated(err, func() (*Table, error) {
+			return i.GetTable(ctx, table.Database, table.Name, clusterName)
+		})
+	}
+
+	return i.GetTable(ctx, table.Database, table.Name, clusterName)
+}
+
+// GetTable returns the table, or nil if it does not exist. It is read from the replica hit by the
+// query: reading through the cluster would return the table once per shard.
+func (i *impl) GetTable(ctx context.Context, database string, name string, _ *string) (*Table, error) {
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{querybuilder.NewField("engine"), querybuilder.NewField("engine_full"), querybuilder.NewField("comment")},
+		"system.tables",
+	).Where(
+		querybuilder.WhereEquals("database", database),
+		querybuilder.WhereEquals("name", name),
+	)
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var table *Table
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		engine, err := data.GetString("engine")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'engine' field")
+		}
+		engineFull, err := data.GetString("engine_full")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'engine_full' field")
+		}
+		comment, err := data.GetString("comment")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'comment' field")
+		}
+		table = &Table{
+			Database: database,
+			Name:     name,
+			Engine:   engine,
+			Comment:  comment,
+		}
+		table.setEngineClauses(engineFull)
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	if table == nil {
+		// Table not found
+		return nil, nil
+	}
+
+	table.Columns, err = i.getTableColumns(ctx, database, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// engineClauses are the keywords of the clauses following the engine in system.tables.engine_full.
+var engineClauses = []string{"PARTITION BY", "PRIMARY KEY", "ORDER BY", "SAMPLE BY", "TTL", "SETTINGS"}
+
+// setEngineClauses sets the keys, TTL and settings of the table from engineFull, such as
+// `MergeTree PARTITION BY toYYYYMM(ts) ORDER BY (id, ts) SETTINGS index_granularity = 8192`. It is read
+// rather than sorting_key and the like, which drop the parentheses around a tuple.
+func (t *Table) setEngineClauses(engineFull string) {
+	top := topLevel(engineFull)
+
+	type clause struct {
+		keyword string
+		start   int
+	}
+	clauses := make([]clause, 0)
+	for pos := range engineFull {
+		if !top[pos] || pos == 0 || engineFull[pos-1] != ' ' {
+			continue
+		}
+		for _, keyword := range engineClauses {
+			if strings.HasPrefix(engineFull[pos:], keyword+" ") {
+				clauses = append(clauses, clause{keyword: keyword, start: pos})
+				break
+			}
+		}
+	}
+
+	values := make(map[string]*string)
+	for idx, c := range clauses {
+		end := len(engineFull)
+		if idx+1 < len(clauses) {
+			end = clauses[idx+1].start
+		}
+		values[c.keyword] = new(strings.TrimSpace(engineFull[c.start+len(c.keyword) : end]))
+	}
+
+	t.PartitionBy = values["PARTITION BY"]
+	t.PrimaryKey = values["PRIMARY KEY"]
+	t.OrderBy = values["ORDER BY"]
+	t.SampleBy = values["SAMPLE BY"]
+	t.TTL = values["TTL"]
+
+	t.Settings = nil
+	if settings := values["SETTINGS"]; settings != nil {
+		t.Settings = make(map[string]string)
+		top := topLevel(*settings)
+		start := 0
+		for pos := 0; pos <= len(*settings); pos++ {
+			if pos < len(*settings) && (!top[pos] || (*settings)[pos] != ',') {
+				continue
+			}
+			name, value, _ := strings.Cut((*settings)[start:pos], "=")
+			value = strings.TrimSpace(value)
+			if unquoted, ok := strings.CutPrefix(value, "'"); ok && strings.HasSuffix(unquoted, "'") {
+				value = strings.ReplaceAll(strings.TrimSuffix(unquoted, "'"), "\\'", "'")
+			}
+			t.Settings[strings.TrimSpace(name)] = value
+			start = pos + 1
+		}
+	}
+}
+
+// topLevel reports, for each byte of s, whether it is outside parentheses, brackets and quotes.
+func topLevel(s string) []bool {
+	top := make([]bool, len(s))
+	depth := 0
+	var quote byte
+	for pos := 0; pos < len(s); pos++ {
+		c := s[pos]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				pos++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			continue
+		case c == '(' || c == '[':
+			depth++
+			continue
+		case c == ')' || c == ']':
+			depth--
+			continue
+		}
+		top[pos] = depth == 0
+	}
+
+	return top
+}
+
+// getTableColumns returns the columns of the table, in order.
+func (i *impl) getTableColumns(ctx context.Context, database string, table string) ([]TableColumn, error) {
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("name"),
+			querybuilder.NewField("type"),
+			querybuilder.NewField("default_kind"),
+			querybuilder.NewField("default_expression"),
+			querybuilder.NewField("compression_codec"),
+			querybuilder.NewField("comment"),
+			querybuilder.NewField("position"),
+		},
+		"system.columns",
+	).Where(
+		querybuilder.WhereEquals("database", database),
+		querybuilder.WhereEquals("table", table),
+	)
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	type positioned struct {
+		column   TableColumn
+		position uint64
+	}
+	rows := make([]positioned, 0)
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		name, err := data.GetString("name")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
+		}
+		columnType, err := data.GetString("type")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'type' field")
+		}
+		defaultKind, err := data.GetString("default_kind")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'default_kind' field")
+		}
+		defaultExpression, err := data.GetString("default_expression")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'default_expression' field")
+		}
+		codec, err := data.GetString("compression_codec")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'compression_codec' field")
+		}
+		comment, err := data.GetString("comment")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'comment' field")
+		}
+		position, err := data.GetUInt64("position")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'position' field")
+		}
+
+		rows = append(rows, positioned{
+			column: TableColumn{
+				Name:        name,
+				Type:        columnType,
+				DefaultKind: nonEmpty(defaultKind),
+				Default:     nonEmpty(defaultExpression),
+				Codec:       nonEmpty(strings.TrimSuffix(strings.TrimPrefix(codec, "CODEC("), ")")),
+				Comment:     nonEmpty(comment),
+			},
+			position: position,
+		})
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	slices.SortFunc(rows, func(a, b positioned) int {
+		return int(a.position) - int(b.position)
+	})
+
+	columns := make([]TableColumn, 0, len(rows))
+	for _, r := range rows {
+		columns = append(columns, r.column)
+	}
+
+	return columns, nil
+}
+
+// UpdateTable alters the table from current to desired. Only the columns, TTL, settings and comment
+// can change: the other attributes of desired are ignored.
+func (i *impl) UpdateTable(ctx context.Context, current Table, desired Table, clusterName *string) (*Table, error) {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each change is its own query: ClickHouse refuses to mix some of them in a single ALTER.
+	for _, builder := range tableAlterations(current, desired, clusterName) {
+		sql, err := builder.Build()
+		if err != nil {
+			return nil, errors.WithMessage(err, "error building query")
+		}
+
+		err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error running query")
+		}
+	}
+
+	return i.GetTable(ctx, desired.Database, desired.Name, clusterName)
+}
+
+func (i *impl) DeleteTable(ctx context.Context, database string, name string, clusterName *string) error {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	builder := querybuilder.NewDropTable(database, name).WithCluster(clusterName)
+	sql, err := builder.Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+// tableAlterations returns the ALTER TABLE queries changing current into desired, one change each.
+// Columns are dropped first, then added or modified in the desired order, each one placed after
+// the previous one.
+func tableAlterations(current Table, desired Table, clusterName *string) []querybuilder.AlterTableQueryBuilder {
+	alterations := make([]querybuilder.AlterTableQueryBuilder, 0)
+	alter := func() querybuilder.AlterTableQueryBuilder {
+		builder := querybuilder.NewAlterTable(desired.Database, desired.Name).WithCluster(clusterName)
+		alterations = append(alterations, builder)
+		return builder
+	}
+
+	existing := make(map[string]TableColumn)
+	for _, c := range current.Columns {
+		existing[c.Name] = c
+	}
+	wanted := make(map[string]bool)
+	for _, c := range desired.Columns {
+		wanted[c.Name] = true
+	}
+
+	// order tracks the position of the columns as the queries are applied.
+	order := make([]string, 0, len(current.Columns))
+	for _, c := range current.Columns {
+		if wanted[c.Name] {
+			order = append(order, c.Name)
+		} else {
+			alter().DropColumn(c.Name)
+		}
+	}
+
+	for idx, c := range desired.Columns {
+		after := ""
+		if idx > 0 {
+			after = desired.Columns[idx-1].Name
+		}
+
+		pos := slices.Index(order, c.Name)
+		old, found := existing[c.Name]
+		switch {
+		case !found:
+			alter().AddColumn(c.definition(), after)
+		case pos != idx || !reflect.DeepEqual(old, c):
+			for _, property := range removedColumnProperties(old, c) {
+				alter().RemoveColumnProperty(c.Name, property)
+			}
+			alter().ModifyColumn(c.definition(), after)
+		default:
+			continue
+		}
+
+		if pos >= 0 {
+			order = slices.Delete(order, pos, pos+1)
+		}
+		order = slices.Insert(order, idx, c.Name)
+	}
+
+	if desired.Comment != current.Comment {
+		alter().ModifyComment(desired.Comment)
+	}
+
+	switch {
+	case desired.TTL == nil && current.TTL != nil:
+		alter().RemoveTTL()
+	case desired.TTL != nil && (current.TTL == nil || *desired.TTL != *current.TTL):
+		alter().ModifyTTL(*desired.TTL)
+	}
+
+	modified := make(map[string]string)
+	for name, value := range desired.Settings {
+		if v, ok := current.Settings[name]; !ok || v != value {
+			modified[name] = value
+		}
+	}
+	if len(modified) > 0 {
+		alter().ModifySettings(modified)
+	}
+
+	reset := make([]string, 0)
+	for _, name := range slices.Sorted(maps.Keys(current.Settings)) {
+		if _, ok := desired.Settings[name]; !ok {
+			reset = append(reset, name)
+		}
+	}
+	if len(reset) > 0 {
+		alter().ResetSettings(reset)
+	}
+
+	return alterations
+}
+
+// removedColumnProperties returns the properties of old that desired removes. Modifying a column
+// without them would keep them.
+func removedColumnProperties(old TableColumn, desired TableColumn) []string {
+	properties := make([]string, 0)
+	if old.Default != nil && desired.Default == nil && desired.DefaultKind == nil {
+		kind := "DEFAULT"
+		if old.DefaultKind != nil {
+			kind = *old.DefaultKind
+		}
+		properties = append(properties, kind)
+	}
+	if old.Codec != nil && desired.Codec == nil {
+		properties = append(properties, "CODEC")
+	}
+	if old.Comment != nil && desired.Comment == nil {
+		properties = append(properties, "COMMENT")
+	}
+	if old.TTL != nil && desired.TTL == nil {
+		properties = append(properties, "TTL")
+	}
+
+	return properties
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,192 @@
+package dbops
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_tableAlterations(t *testing.T) {
+	id := TableColumn{Name: "id", Type: "UInt64"}
+	ts := TableColumn{Name: "ts", Type: "DateTime", Default: new("now()")}
+	payload := TableColumn{Name: "payload", Type: "String", Codec: new("ZSTD(3)")}
+
+	const table = "ALTER TABLE {identifier_0:Identifier}.{identifier_1:Identifier} "
+
+	tests := []struct {
+		name    string
+		current Table
+		desired Table
+		want    []string
+	}{
+		{
+			name:    "No change",
+			current: Table{Columns: []TableColumn{id, ts}, TTL: new("ts + INTERVAL 1 DAY")},
+			desired: Table{Columns: []TableColumn{id, ts}, TTL: new("ts + INTERVAL 1 DAY")},
+		},
+		{
+			name:    "Column added at the end",
+			current: Table{Columns: []TableColumn{id}},
+			desired: Table{Columns: []TableColumn{id, ts}},
+			want:    []string{"ADD COLUMN `ts` DateTime DEFAULT now() AFTER `id`"},
+		},
+		{
+			name:    "Column added first",
+			current: Table{Columns: []TableColumn{ts}},
+			desired: Table{Columns: []TableColumn{id, ts}},
+			want:    []string{"ADD COLUMN `id` UInt64 FIRST"},
+		},
+		{
+			name:    "Column dropped",
+			current: Table{Columns: []TableColumn{id, ts, payload}},
+			desired: Table{Columns: []TableColumn{id, payload}},
+			want:    []string{"DROP COLUMN `ts`"},
+		},
+		{
+			name:    "Column type changed",
+			current: Table{Columns: []TableColumn{id, ts}},
+			desired: Table{Columns: []TableColumn{id, {Name: "ts", Type: "DateTime64(3)", Default: new("now64()")}}},
+			want:    []string{"MODIFY COLUMN `ts` DateTime64(3) DEFAULT now64() AFTER `id`"},
+		},
+		{
+			name:    "Column properties removed",
+			current: Table{Columns: []TableColumn{{Name: "ts", Type: "DateTime", DefaultKind: new("MATERIALIZED"), Default: new("now()"), Comment: new("c")}}},
+			desired: Table{Columns: []TableColumn{{Name: "ts", Type: "DateTime"}}},
+			want: []string{
+				"MODIFY COLUMN `ts` REMOVE MATERIALIZED",
+				"MODIFY COLUMN `ts` REMOVE COMMENT",
+				"MODIFY COLUMN `ts` DateTime FIRST",
+			},
+		},
+		{
+			name:    "Columns reordered",
+			current: Table{Columns: []TableColumn{id, ts, payload}},
+			desired: Table{Columns: []TableColumn{payload, id, ts}},
+			want:    []string{"MODIFY COLUMN `payload` String CODEC(ZSTD(3)) FIRST"},
+		},
+		{
+			name:    "Column replaced in the middle",
+			current: Table{Columns: []TableColumn{id, ts, payload}},
+			desired: Table{Columns: []TableColumn{id, {Name: "day", Type: "Date"}, payload}},
+			want: []string{
+				"DROP COLUMN `ts`",
+				"ADD COLUMN `day` Date AFTER `id`",
+			},
+		},
+		{
+			name:    "Table properties",
+			current: Table{Columns: []TableColumn{id}, Comment: "old", TTL: new("ts + INTERVAL 1 DAY"), Settings: map[string]string{"a": "1", "b": "2"}},
+			desired: Table{Columns: []TableColumn{id}, Comment: "new", Settings: map[string]string{"a": "3", "c": "4"}},
+			want: []string{
+				"MODIFY COMMENT 'new'",
+				"REMOVE TTL",
+				"MODIFY SETTING `a` = '3', `c` = '4'",
+				"RESET SETTING `b`",
+			},
+		},
+		{
+			name:    "TTL changed",
+			current: Table{Columns: []TableColumn{id}, TTL: new("ts + INTERVAL 1 DAY")},
+			desired: Table{Columns: []TableColumn{id}, TTL: new("ts + INTERVAL 2 DAY")},
+			want:    []string{"MODIFY TTL ts + INTERVAL 2 DAY"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.current.Database, tt.current.Name = "db", "t"
+			tt.desired.Database, tt.desired.Name = "db", "t"
+
+			got := make([]string, 0)
+			for _, builder := range tableAlterations(tt.current, tt.desired, nil) {
+				sql, err := builder.Build()
+				if err != nil {
+					t.Fatalf("Build() error = %v", err)
+				}
+				got = append(got, strings.TrimSuffix(strings.TrimPrefix(sql, table), ";"))
+			}
+
+			want := tt.want
+			if want == nil {
+				want = []string{}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("tableAlterations() got = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGetTable(t *testing.T) {
+	fake := &ddlClient{responses: []ddlResponse{
+		{rows: []map[string]any{{"engine": "MergeTree", "engine_full": "MergeTree ORDER BY (id, ts) SETTINGS index_granularity = 8192", "comment": ""}}},
+		{rows: []map[string]any{
+			{"name": "ts", "type": "DateTime", "default_kind": "DEFAULT", "default_expression": "now()", "compression_codec": "CODEC(Delta(4), ZSTD(1))", "comment": "", "position": uint64(2)},
+			{"name": "id", "type": "UInt64", "default_kind": "", "default_expression": "", "compression_codec": "", "comment": "key", "position": uint64(1)},
+		}},
+	}}
+	i := &impl{clickhouseClient: fake}
+
+	got, err := i.GetTable(context.Background(), "db", "t", nil)
+	if err != nil {
+		t.Fatalf("GetTable() error = %v", err)
+	}
+
+	want := &Table{
+		Database: "db",
+		Name:     "t",
+		Engine:   "MergeTree",
+		OrderBy:  new("(id, ts)"),
+		Settings: map[string]string{"index_granularity": "8192"},
+		Columns: []TableColumn{
+			{Name: "id", Type: "UInt64", Comment: new("key")},
+			{Name: "ts", Type: "DateTime", DefaultKind: new("DEFAULT"), Default: new("now()"), Codec: new("Delta(4), ZSTD(1)")},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTable() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestTable_setEngineClauses(t *testing.T) {
+	tests := []struct {
+		name       string
+		engineFull string
+		want       Table
+	}{
+		{
+			name:       "No clauses",
+			engineFull: "Memory",
+		},
+		{
+			name:       "All clauses",
+			engineFull: "ReplicatedMergeTree('/clickhouse/tables/{shard}/db/events', '{replica}') PARTITION BY toYYYYMM(ts) PRIMARY KEY id ORDER BY (id, intHash32(user_id)) SAMPLE BY intHash32(user_id) TTL ts + toIntervalMonth(1) SETTINGS index_granularity = 8192, storage_policy = 'hot and cold'",
+			want: Table{
+				PartitionBy: new("toYYYYMM(ts)"),
+				PrimaryKey:  new("id"),
+				OrderBy:     new("(id, intHash32(user_id))"),
+				SampleBy:    new("intHash32(user_id)"),
+				TTL:         new("ts + toIntervalMonth(1)"),
+				Settings:    map[string]string{"index_granularity": "8192", "storage_policy": "hot and cold"},
+			},
+		},
+		{
+			name:       "Keywords in arguments and strings",
+			engineFull: "MergeTree ORDER BY tuple() TTL ts + toIntervalDay(1) DELETE WHERE kind = ' ORDER BY x' SETTINGS merge_selector_algorithm = 'Simple'",
+			want: Table{
+				OrderBy:  new("tuple()"),
+				TTL:      new("ts + toIntervalDay(1) DELETE WHERE kind = ' ORDER BY x'"),
+				Settings: map[string]string{"merge_selector_algorithm": "Simple"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Table
+			got.setEngineClauses(tt.engineFull)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setEngineClauses() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
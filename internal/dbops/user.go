@@ -3,6 +3,7 @@ package dbops
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/pingcap/errors"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
@@ -15,6 +16,8 @@ type User struct {
 	AuthMethods      []AuthMethod `json:"-"`
 	SettingsProfiles []string     `json:"-"`
 	HostIPs          []string     `json:"-"`
+	// Divergence is set when the user differs between the replicas of its cluster.
+	Divergence *ReplicaDivergence `json:"-"`
 }
 
 // AuthMethod is one resolved authentication method. Type is the querybuilder render-key and Args are
@@ -47,6 +50,11 @@ func (u *User) HasSettingProfile(profileName string) bool {
 }
 
 func (i *impl) CreateUser(ctx context.Context, user User, clusterName *string) (*User, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	builder := querybuilder.NewCreateUser(user.Name).
 		Identified(toQuerybuilderAuthMethods(user.AuthMethods))
 
@@ -60,29 +68,48 @@ func (i *impl) CreateUser(ctx context.Context, user User, clusterName *string) (
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql, builder.Parameters())
+	repair, err := i.existsOnSomeReplicas(ctx, clusterName, "system.users", user.Name)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error running query")
+		return nil, errors.WithMessage(err, "error comparing replicas")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		if !repair || !isAlreadyExistsError(err) {
+			return partiallyCreated(err, func() (*User, error) {
+				return i.FindUserByName(ctx, user.Name, clusterName)
+			})
+		}
+
+		// The replicas that already had the user refused to create it again, the others now have it.
+		tflog.Warn(ctx, "user existed on some replicas only, created it on the others", map[string]any{
+			"name": user.Name,
+		})
 	}
 
-	return retryWithBackoff(ctx, "user", user.Name, func() (*User, error) {
+	return retryWithBackoff(ctx, "user", user.Name, func(ctx context.Context) (*User, error) {
 		return i.FindUserByName(ctx, user.Name, clusterName)
 	}, i.readAfterWriteTimeoutArgs()...)
 }
 
 func (i *impl) GetUser(ctx context.Context, id string, clusterName *string) (*User, error) { // nolint:dupl
-	sql, err := querybuilder.
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.
 		NewSelect([]querybuilder.Field{querybuilder.NewField("name")}, "system.users").
 		WithCluster(clusterName).
-		Where(querybuilder.WhereEquals("id", id)).
-		Build()
+		Where(querybuilder.WhereEquals("id", id))
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var user *User
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotUsers, clusterName, []rowCondition{fieldEquals("id", id)}, func(data clickhouseclient.Row) error {
 		n, err := data.GetString("name")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'name' field")
@@ -104,17 +131,17 @@ func (i *impl) GetUser(ctx context.Context, id string, clusterName *string) (*Us
 
 	// Check if user has settings profile associated.
 	{
-		sql, err = querybuilder.
+		builder := querybuilder.
 			NewSelect([]querybuilder.Field{querybuilder.NewField("inherit_profile")}, "system.settings_profile_elements").
 			WithCluster(clusterName).
-			Where(querybuilder.WhereEquals("user_name", user.Name)).
-			Build()
+			Where(querybuilder.WhereEquals("user_name", user.Name))
+		sql, err := builder.Build()
 		if err != nil {
 			return nil, errors.WithMessage(err, "error building query")
 		}
 
 		profiles := make([]string, 0)
-		err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotSettingsProfileElements, clusterName, []rowCondition{fieldEquals("user_name", user.Name)}, func(data clickhouseclient.Row) error {
 			profile, err := data.GetNullableString("inherit_profile")
 			if err != nil {
 				return errors.WithMessage(err, "error scanning query result, missing 'inherit_profile' field")
@@ -132,10 +159,28 @@ func (i *impl) GetUser(ctx context.Context, id string, clusterName *string) (*Us
 		user.SettingsProfiles = profiles
 	}
 
+	user.Divergence, err = i.checkReplicas(ctx, clusterName, replicaCheck{
+		table: "system.users",
+		name:  user.Name,
+		columns: []string{
+			"auth_type", "host_ip", "host_names", "host_names_regexp", "host_names_like",
+			"default_roles_all", "default_roles_list", "default_roles_except",
+			"grantees_any", "grantees_list", "grantees_except", "default_database",
+		},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error comparing replicas")
+	}
+
 	return user, nil
 }
 
 func (i *impl) DeleteUser(ctx context.Context, id string, clusterName *string) error {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
 	user, err := i.GetUser(ctx, id, clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error getting user")
@@ -146,12 +191,13 @@ func (i *impl) DeleteUser(ctx context.Context, id string, clusterName *string) e
 		return nil
 	}
 
-	sql, err := querybuilder.NewDropUser(user.Name).WithCluster(clusterName).Build()
+	builder := querybuilder.NewDropUser(user.Name).WithCluster(clusterName)
+	sql, err := builder.Build()
 	if err != nil {
 		return errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql)
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return errors.WithMessage(err, "error running query")
 	}
@@ -160,18 +206,23 @@ func (i *impl) DeleteUser(ctx context.Context, id string, clusterName *string) e
 }
 
 func (i *impl) FindUserByName(ctx context.Context, name string, clusterName *string) (*User, error) {
-	sql, err := querybuilder.
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.
 		NewSelect([]querybuilder.Field{querybuilder.NewField("id").ToString()}, "system.users").
 		WithCluster(clusterName).
-		Where(querybuilder.WhereEquals("name", name)).
-		Build()
+		Where(querybuilder.WhereEquals("name", name))
+	sql, err := builder.Build()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
 	var uuid string
 
-	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+	err = i.selectAccess(ctx, sql, builder.Parameters(), snapshotUsers, clusterName, []rowCondition{fieldEquals("name", name)}, func(data clickhouseclient.Row) error {
 		uuid, err = data.GetString("id")
 		if err != nil {
 			return errors.WithMessage(err, "error scanning query result, missing 'id' field")
@@ -191,6 +242,11 @@ func (i *impl) FindUserByName(ctx context.Context, name string, clusterName *str
 }
 
 func (i *impl) UpdateUser(ctx context.Context, user User, clusterName *string) (*User, error) {
+	clusterName, err := i.accessCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Retrieve current user
 	existing, err := i.GetUser(ctx, user.ID, clusterName)
 	if err != nil {
@@ -210,7 +266,7 @@ func (i *impl) UpdateUser(ctx context.Context, user User, clusterName *string) (
 		return nil, errors.WithMessage(err, "error building query")
 	}
 
-	err = i.clickhouseClient.Exec(ctx, sql, builder.Parameters())
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error running query")
 	}
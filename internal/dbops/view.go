@@ -0,0 +1,348 @@
+package dbops
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/querybuilder"
+)
+
+const (
+	engineView             = "View"
+	engineMaterializedView = "MaterializedView"
+)
+
+type View struct {
+	Database string
+	Name     string
+	Query    string
+	// Definer is the name of a user, or CURRENT_USER.
+	Definer *string
+	// SQLSecurity is one of DEFINER, INVOKER or NONE.
+	SQLSecurity *string
+	Comment     string
+}
+
+type MaterializedView struct {
+	Database string
+	Name     string
+	Query    string
+	// Refresh is set for refreshable materialized views. It is not returned by GetMaterializedView.
+	Refresh *RefreshSchedule
+	// ToDatabase and ToTable are the table the view writes to. When they are empty the view stores
+	// its data in an inner table created with Engine, OrderBy and PartitionBy. None of them is
+	// returned by GetMaterializedView.
+	ToDatabase  string
+	ToTable     string
+	Engine      *string
+	OrderBy     *string
+	PartitionBy *string
+	// Populate only applies when the view is created.
+	Populate    bool
+	Definer     *string
+	SQLSecurity *string
+	Comment     string
+}
+
+type RefreshSchedule struct {
+	// Kind is EVERY or AFTER.
+	Kind         string
+	Interval     string
+	Offset       *string
+	RandomizeFor *string
+	DependsOn    []string
+	Append       bool
+}
+
+func (r RefreshSchedule) definition() querybuilder.RefreshSchedule {
+	return querybuilder.RefreshSchedule{
+		Kind:         r.Kind,
+		Interval:     r.Interval,
+		Offset:       r.Offset,
+		RandomizeFor: r.RandomizeFor,
+		DependsOn:    r.DependsOn,
+		Append:       r.Append,
+	}
+}
+
+func (i *impl) CreateView(ctx context.Context, view View, clusterName *string) (*View, error) {
+	return i.createView(ctx, view, false, clusterName)
+}
+
+// UpdateView replaces the view atomically, which keeps the grants on it.
+func (i *impl) UpdateView(ctx context.Context, view View, clusterName *string) (*View, error) {
+	return i.createView(ctx, view, true, clusterName)
+}
+
+func (i *impl) createView(ctx context.Context, view View, orReplace bool, clusterName *string) (*View, error) {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := querybuilder.NewCreateView(view.Database, view.Name).
+		WithCluster(clusterName).
+		WithOrReplace(orReplace).
+		WithSQLSecurity(querybuilder.SQLSecurity{Definer: view.Definer, Type: view.SQLSecurity}).
+		WithQuery(view.Query).
+		WithComment(nonEmpty(view.Comment))
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		if orReplace {
+			return nil, errors.WithMessage(err, "error running query")
+		}
+		return partiallyCreated(err, func() (*View, error) {
+			return i.GetView(ctx, view.Database, view.Name, clusterName)
+		})
+	}
+
+	return i.GetView(ctx, view.Database, view.Name, clusterName)
+}
+
+// GetView returns the view, or nil if it does not exist. Like tables, views are read from the
+// replica hit by the query.
+func (i *impl) GetView(ctx context.Context, database string, name string, _ *string) (*View, error) {
+	definition, err := i.getViewDefinition(ctx, database, name, engineView)
+	if err != nil || definition == nil {
+		return nil, err
+	}
+
+	return &View{
+		Database:    database,
+		Name:        name,
+		Query:       definition.query,
+		Definer:     definition.definer,
+		SQLSecurity: definition.sqlSecurity,
+		Comment:     definition.comment,
+	}, nil
+}
+
+// DeleteView drops a view or a materialized view.
+func (i *impl) DeleteView(ctx context.Context, database string, name string, clusterName *string) error {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	builder := querybuilder.NewDropView(database, name).WithCluster(clusterName)
+	sql, err := builder.Build()
+	if err != nil {
+		return errors.WithMessage(err, "error building query")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		return errors.WithMessage(err, "error running query")
+	}
+
+	return nil
+}
+
+func (i *impl) CreateMaterializedView(ctx context.Context, view MaterializedView, clusterName *string) (*MaterializedView, error) {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var refresh *querybuilder.RefreshSchedule
+	if view.Refresh != nil {
+		refresh = new(view.Refresh.definition())
+	}
+
+	builder := querybuilder.NewCreateMaterializedView(view.Database, view.Name).
+		WithCluster(clusterName).
+		WithRefresh(refresh).
+		WithEngine(view.Engine).
+		WithOrderBy(view.OrderBy).
+		WithPartitionBy(view.PartitionBy).
+		WithPopulate(view.Populate).
+		WithSQLSecurity(querybuilder.SQLSecurity{Definer: view.Definer, Type: view.SQLSecurity}).
+		WithQuery(view.Query).
+		WithComment(nonEmpty(view.Comment))
+	if view.ToTable != "" {
+		builder = builder.WithTo(view.ToDatabase, view.ToTable)
+	}
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+	if err != nil {
+		return partiallyCreated(err, func() (*MaterializedView, error) {
+			return i.GetMaterializedView(ctx, view.Database, view.Name, clusterName)
+		})
+	}
+
+	return i.GetMaterializedView(ctx, view.Database, view.Name, clusterName)
+}
+
+// GetMaterializedView returns the materialized view, or nil if it does not exist.
+func (i *impl) GetMaterializedView(ctx context.Context, database string, name string, _ *string) (*MaterializedView, error) {
+	definition, err := i.getViewDefinition(ctx, database, name, engineMaterializedView)
+	if err != nil || definition == nil {
+		return nil, err
+	}
+
+	return &MaterializedView{
+		Database:    database,
+		Name:        name,
+		Query:       definition.query,
+		Definer:     definition.definer,
+		SQLSecurity: definition.sqlSecurity,
+		Comment:     definition.comment,
+	}, nil
+}
+
+// UpdateMaterializedView alters the materialized view from current to desired. Only the query, the
+// refresh schedule, the SQL security and the comment can change: the other attributes of desired
+// are ignored.
+func (i *impl) UpdateMaterializedView(ctx context.Context, current MaterializedView, desired MaterializedView, clusterName *string) (*MaterializedView, error) {
+	clusterName, err := i.databaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, builder := range materializedViewAlterations(current, desired, clusterName) {
+		sql, err := builder.Build()
+		if err != nil {
+			return nil, errors.WithMessage(err, "error building query")
+		}
+
+		err = i.exec(ctx, sql, builder.Parameters(), clusterName)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error running query")
+		}
+	}
+
+	return i.GetMaterializedView(ctx, desired.Database, desired.Name, clusterName)
+}
+
+// materializedViewAlterations returns the ALTER TABLE queries changing current into desired, one
+// change each. A refresh schedule or SQL security that desired leaves unset is kept as is.
+func materializedViewAlterations(current MaterializedView, desired MaterializedView, clusterName *string) []querybuilder.AlterTableQueryBuilder {
+	alterations := make([]querybuilder.AlterTableQueryBuilder, 0)
+	alter := func() querybuilder.AlterTableQueryBuilder {
+		builder := querybuilder.NewAlterTable(desired.Database, desired.Name).WithCluster(clusterName)
+		alterations = append(alterations, builder)
+		return builder
+	}
+
+	if desired.Refresh != nil && !reflect.DeepEqual(current.Refresh, desired.Refresh) {
+		alter().ModifyRefresh(desired.Refresh.definition())
+	}
+
+	if desired.Query != current.Query {
+		alter().ModifyQuery(desired.Query)
+	}
+
+	if desired.SQLSecurity != nil && (!reflect.DeepEqual(current.SQLSecurity, desired.SQLSecurity) || !reflect.DeepEqual(current.Definer, desired.Definer)) {
+		alter().ModifySQLSecurity(querybuilder.SQLSecurity{Definer: desired.Definer, Type: desired.SQLSecurity})
+	}
+
+	if desired.Comment != current.Comment {
+		alter().ModifyComment(desired.Comment)
+	}
+
+	return alterations
+}
+
+type viewDefinition struct {
+	query       string
+	definer     *string
+	sqlSecurity *string
+	comment     string
+}
+
+// viewSQLSecurityRegexp matches the SQL SECURITY clause, which ClickHouse writes right before the
+// SELECT query of a view.
+var viewSQLSecurityRegexp = regexp.MustCompile("(?:DEFINER = (`(?:[^`\\\\]|\\\\.)*`|\\S+) )?SQL SECURITY (DEFINER|INVOKER|NONE) AS ")
+
+// getViewDefinition returns the definition of the view with the given engine, or nil if it does not exist.
+func (i *impl) getViewDefinition(ctx context.Context, database string, name string, engine string) (*viewDefinition, error) {
+	builder := querybuilder.NewSelect(
+		[]querybuilder.Field{
+			querybuilder.NewField("engine"),
+			querybuilder.NewField("as_select"),
+			querybuilder.NewField("create_table_query"),
+			querybuilder.NewField("comment"),
+		},
+		"system.tables",
+	).Where(
+		querybuilder.WhereEquals("database", database),
+		querybuilder.WhereEquals("name", name),
+	)
+	sql, err := builder.Build()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building query")
+	}
+
+	var definition *viewDefinition
+
+	err = i.clickhouseClient.Select(ctx, sql, func(data clickhouseclient.Row) error {
+		actual, err := data.GetString("engine")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'engine' field")
+		}
+		if actual != engine {
+			return errors.Errorf("%s.%s has engine %s, expected %s", database, name, actual, engine)
+		}
+		query, err := data.GetString("as_select")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'as_select' field")
+		}
+		createQuery, err := data.GetString("create_table_query")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'create_table_query' field")
+		}
+		comment, err := data.GetString("comment")
+		if err != nil {
+			return errors.WithMessage(err, "error scanning query result, missing 'comment' field")
+		}
+
+		definition = &viewDefinition{
+			query:   query,
+			comment: comment,
+		}
+		definition.definer, definition.sqlSecurity = parseSQLSecurity(createQuery, query)
+		return nil
+	}, builder.Parameters())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error running query")
+	}
+
+	return definition, nil
+}
+
+// parseSQLSecurity returns the definer and SQL security of the CREATE query of a view. Only the
+// clause before the SELECT query is looked at.
+func parseSQLSecurity(createQuery string, selectQuery string) (*string, *string) {
+	if idx := strings.Index(createQuery, " AS "+selectQuery); idx >= 0 {
+		createQuery = createQuery[:idx+len(" AS ")]
+	}
+
+	match := viewSQLSecurityRegexp.FindStringSubmatch(createQuery)
+	if match == nil {
+		return nil, nil
+	}
+
+	var definer *string
+	if name := match[1]; name != "" {
+		if strings.HasPrefix(name, "`") {
+			name = strings.ReplaceAll(strings.Trim(name, "`"), "\\`", "`")
+		}
+		definer = &name
+	}
+
+	return definer, &match[2]
+}
@@ -0,0 +1,177 @@
+package dbops
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_materializedViewAlterations(t *testing.T) {
+	hourly := &RefreshSchedule{Kind: "EVERY", Interval: "1 HOUR"}
+
+	const view = "ALTER TABLE {identifier_0:Identifier}.{identifier_1:Identifier} "
+
+	tests := []struct {
+		name    string
+		current MaterializedView
+		desired MaterializedView
+		want    []string
+	}{
+		{
+			name:    "No change",
+			current: MaterializedView{Query: "SELECT 1", Refresh: hourly, SQLSecurity: new("DEFINER")},
+			desired: MaterializedView{Query: "SELECT 1", Refresh: &RefreshSchedule{Kind: "EVERY", Interval: "1 HOUR"}, SQLSecurity: new("DEFINER")},
+		},
+		{
+			name:    "Query changed",
+			current: MaterializedView{Query: "SELECT 1"},
+			desired: MaterializedView{Query: "SELECT 2"},
+			want:    []string{"MODIFY QUERY SELECT 2"},
+		},
+		{
+			name:    "Refresh changed",
+			current: MaterializedView{Query: "SELECT 1", Refresh: hourly},
+			desired: MaterializedView{Query: "SELECT 1", Refresh: &RefreshSchedule{Kind: "EVERY", Interval: "1 HOUR", DependsOn: []string{"db.other"}}},
+			want:    []string{"MODIFY REFRESH EVERY 1 HOUR DEPENDS ON `db`.`other`"},
+		},
+		{
+			name:    "Definer changed",
+			current: MaterializedView{Query: "SELECT 1", Definer: new("alice"), SQLSecurity: new("DEFINER")},
+			desired: MaterializedView{Query: "SELECT 1", Definer: new("bob"), SQLSecurity: new("DEFINER")},
+			want:    []string{"MODIFY DEFINER = `bob` SQL SECURITY DEFINER"},
+		},
+		{
+			name:    "Unset SQL security is kept",
+			current: MaterializedView{Query: "SELECT 1", Definer: new("alice"), SQLSecurity: new("DEFINER")},
+			desired: MaterializedView{Query: "SELECT 1"},
+		},
+		{
+			name:    "Comment changed",
+			current: MaterializedView{Query: "SELECT 1", Comment: "old"},
+			desired: MaterializedView{Query: "SELECT 1"},
+			want:    []string{"MODIFY COMMENT ''"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.current.Database, tt.current.Name = "db", "mv"
+			tt.desired.Database, tt.desired.Name = "db", "mv"
+
+			got := make([]string, 0)
+			for _, builder := range materializedViewAlterations(tt.current, tt.desired, nil) {
+				sql, err := builder.Build()
+				if err != nil {
+					t.Fatalf("Build() error = %v", err)
+				}
+				got = append(got, strings.TrimSuffix(strings.TrimPrefix(sql, view), ";"))
+			}
+
+			want := tt.want
+			if want == nil {
+				want = []string{}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("materializedViewAlterations() got = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func Test_parseSQLSecurity(t *testing.T) {
+	tests := []struct {
+		name            string
+		createQuery     string
+		selectQuery     string
+		wantDefiner     *string
+		wantSQLSecurity *string
+	}{
+		{
+			name:        "No SQL security",
+			createQuery: "CREATE VIEW db.v (`id` UInt64) AS SELECT id FROM db.t",
+			selectQuery: "SELECT id FROM db.t",
+		},
+		{
+			name:            "Definer",
+			createQuery:     "CREATE MATERIALIZED VIEW db.mv TO db.t (`id` UInt64) DEFINER = alice SQL SECURITY DEFINER AS SELECT id FROM db.src",
+			selectQuery:     "SELECT id FROM db.src",
+			wantDefiner:     new("alice"),
+			wantSQLSecurity: new("DEFINER"),
+		},
+		{
+			name:            "Quoted definer",
+			createQuery:     "CREATE VIEW db.v (`id` UInt64) DEFINER = `team\\`lead` SQL SECURITY DEFINER AS SELECT id FROM db.t",
+			selectQuery:     "SELECT id FROM db.t",
+			wantDefiner:     new("team`lead"),
+			wantSQLSecurity: new("DEFINER"),
+		},
+		{
+			name:            "Clauses in the query are ignored",
+			createQuery:     "CREATE VIEW db.v (`s` String) SQL SECURITY INVOKER AS SELECT 'DEFINER = bob SQL SECURITY NONE' AS s",
+			selectQuery:     "SELECT 'DEFINER = bob SQL SECURITY NONE' AS s",
+			wantSQLSecurity: new("INVOKER"),
+		},
+		{
+			name:            "Select query formatted differently",
+			createQuery:     "CREATE VIEW db.v (`s` String COMMENT 'DEFINER = bob SQL SECURITY NONE') DEFINER = alice SQL SECURITY DEFINER AS SELECT 'SQL SECURITY NONE AS ' AS s",
+			selectQuery:     "SELECT 'SQL SECURITY NONE AS ' AS `s`",
+			wantDefiner:     new("alice"),
+			wantSQLSecurity: new("DEFINER"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definer, sqlSecurity := parseSQLSecurity(tt.createQuery, tt.selectQuery)
+			if !reflect.DeepEqual(definer, tt.wantDefiner) {
+				t.Errorf("parseSQLSecurity() definer = %v, want %v", definer, tt.wantDefiner)
+			}
+			if !reflect.DeepEqual(sqlSecurity, tt.wantSQLSecurity) {
+				t.Errorf("parseSQLSecurity() sqlSecurity = %v, want %v", sqlSecurity, tt.wantSQLSecurity)
+			}
+		})
+	}
+}
+
+func TestGetView(t *testing.T) {
+	t.Run("View", func(t *testing.T) {
+		fake := &ddlClient{responses: []ddlResponse{
+			{rows: []map[string]any{{
+				"engine":             "View",
+				"as_select":          "SELECT id FROM db.t",
+				"create_table_query": "CREATE VIEW db.v (`id` UInt64) SQL SECURITY INVOKER AS SELECT id FROM db.t COMMENT 'ids'",
+				"comment":            "ids",
+			}}},
+		}}
+		i := &impl{clickhouseClient: fake}
+
+		got, err := i.GetView(context.Background(), "db", "v", nil)
+		if err != nil {
+			t.Fatalf("GetView() error = %v", err)
+		}
+
+		want := &View{Database: "db", Name: "v", Query: "SELECT id FROM db.t", SQLSecurity: new("INVOKER"), Comment: "ids"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetView() got = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		i := &impl{clickhouseClient: &ddlClient{responses: []ddlResponse{{}}}}
+
+		got, err := i.GetView(context.Background(), "db", "v", nil)
+		if err != nil || got != nil {
+			t.Errorf("GetView() got = %+v, %v, want nil", got, err)
+		}
+	})
+
+	t.Run("Materialized view", func(t *testing.T) {
+		fake := &ddlClient{responses: []ddlResponse{
+			{rows: []map[string]any{{"engine": "MaterializedView", "as_select": "SELECT 1", "create_table_query": "", "comment": ""}}},
+		}}
+		i := &impl{clickhouseClient: fake}
+
+		if _, err := i.GetView(context.Background(), "db", "mv", nil); err == nil {
+			t.Errorf("GetView() expected an error for a materialized view")
+		}
+	})
+}
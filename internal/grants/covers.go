@@ -7,19 +7,20 @@ import (
 
 // Grant is a privilege grant reduced to the fields that determine coverage.
 type Grant struct {
-	AccessType   string
-	Database     *string
-	Table        *string
-	Column       *string
+	AccessType string
+	Database   *string
+	Table      *string
+	Column     *string
+	// AccessObject is the parameter of the privilege: a user, source, named collection or table engine.
 	AccessObject *string
 	GrantOption  bool
 }
 
 // Covers reports whether broader already conveys at least narrower. Both are
 // assumed to target the same grantee.
-func Covers(broader, narrower Grant) bool {
+func (c Catalog) Covers(broader, narrower Grant) bool {
 	// broader must be narrower's privilege, or a group that contains it.
-	if !slices.Contains(AllDescendants(Parsed().Groups, broader.AccessType), narrower.AccessType) {
+	if !slices.Contains(c.Descendants(broader.AccessType), narrower.AccessType) {
 		return false
 	}
 	// A grant that needs grant option is not covered by one lacking it.
@@ -47,7 +47,7 @@ func TestCovers(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := Covers(tt.broader, tt.narrower); got != tt.want {
+			if got := Parsed().Covers(tt.broader, tt.narrower); got != tt.want {
 				t.Errorf("Covers() = %v, want %v", got, tt.want)
 			}
 		})
@@ -1,5 +1,6 @@
-// Package grants exposes the ClickHouse privilege catalog (parsed from the
-// embedded grants.tsv) and the coverage/scope helpers shared by the
+// Package grants exposes the ClickHouse privilege catalog (loaded from the
+// server's system.privileges, or parsed from the embedded grants.tsv when the
+// server cannot be reached) and the coverage/scope helpers shared by the
 // grant_privilege resource and the dbops client.
 package grants
 
@@ -25,47 +26,80 @@ type Catalog struct {
 
 var parsed = sync.OnceValue(func() Catalog { return ParseGrantsTSV(grantsTSV) })
 
-// Parsed returns the catalog parsed from the embedded grants.tsv, cached once.
+// Parsed returns the catalog parsed from the embedded grants.tsv, cached once. It is the fallback
+// for when the catalog of the server is not available.
 func Parsed() Catalog { return parsed() }
 
-// ParseGrantsTSV builds the privilege catalog from ClickHouse's
-// 01271_show_privileges.reference TSV format.
-func ParseGrantsTSV(data string) Catalog {
+// Entry is one privilege of the catalog, as listed in system.privileges.
+type Entry struct {
+	Privilege string
+	Aliases   []string
+	// Level is the scope of the privilege, nil for groups that only bundle other privileges.
+	Level *string
+	// ParentGroup is the group the privilege belongs to, nil for ALL and NONE.
+	ParentGroup *string
+}
+
+// NewCatalog builds the privilege catalog from the rows of system.privileges.
+func NewCatalog(entries []Entry) Catalog {
 	aliases := make(map[string]string)
 	groups := make(map[string][]string)
 	scopes := make(map[string]string)
 
+	for _, e := range entries {
+		for _, a := range e.Aliases {
+			if a != e.Privilege {
+				aliases[a] = e.Privilege
+			}
+		}
+
+		if e.ParentGroup != nil {
+			groups[*e.ParentGroup] = append(groups[*e.ParentGroup], e.Privilege)
+		}
+
+		if e.Level != nil {
+			scopes[e.Privilege] = *e.Level
+		}
+	}
+
+	return Catalog{Aliases: aliases, Groups: groups, Scopes: scopes}
+}
+
+// ParseGrantsTSV builds the privilege catalog from ClickHouse's
+// 01271_show_privileges.reference TSV format.
+func ParseGrantsTSV(data string) Catalog {
+	nullable := func(field string) *string {
+		if field == "\\N" {
+			return nil
+		}
+		return &field
+	}
+
+	entries := make([]Entry, 0)
+
 	scanner := bufio.NewScanner(strings.NewReader(data))
 	for scanner.Scan() {
-		line := scanner.Text()
-		splitted := strings.Split(line, "\t")
+		splitted := strings.Split(scanner.Text(), "\t")
 
-		clean := strings.ReplaceAll(strings.Trim(splitted[1], "[]"), "'", "")
-		if clean != "" {
-			for a := range strings.SplitSeq(clean, ",") {
-				if a != splitted[0] {
-					aliases[a] = splitted[0]
-				}
-			}
+		entry := Entry{
+			Privilege:   splitted[0],
+			Level:       nullable(splitted[2]),
+			ParentGroup: nullable(splitted[3]),
 		}
 
-		if splitted[3] != "\\N" {
-			if groups[splitted[3]] == nil {
-				groups[splitted[3]] = make([]string, 0)
-			}
-			groups[splitted[3]] = append(groups[splitted[3]], splitted[0])
+		clean := strings.ReplaceAll(strings.Trim(splitted[1], "[]"), "'", "")
+		if clean != "" {
+			entry.Aliases = strings.Split(clean, ",")
 		}
 
-		if splitted[2] != "\\N" {
-			scopes[splitted[0]] = splitted[2]
-		}
+		entries = append(entries, entry)
 	}
 
 	if err := scanner.Err(); err != nil {
 		log.Fatal(err)
 	}
 
-	return Catalog{Aliases: aliases, Groups: groups, Scopes: scopes}
+	return NewCatalog(entries)
 }
 
 // AllDescendants returns the privilege plus all its descendants (children,
@@ -77,3 +111,14 @@ func AllDescendants(groups map[string][]string, privilege string) []string {
 	}
 	return result
 }
+
+// Descendants returns the privilege plus all its descendants in the catalog.
+func (c Catalog) Descendants(privilege string) []string {
+	return AllDescendants(c.Groups, privilege)
+}
+
+// Has reports whether privilege is a canonical privilege or group of the catalog.
+func (c Catalog) Has(privilege string) bool {
+	_, ok := c.Scopes[privilege]
+	return ok || len(c.Groups[privilege]) > 0
+}
@@ -4,30 +4,32 @@ import "slices"
 
 // ScopeAttributes describes which target attributes a privilege's scope allows.
 type ScopeAttributes struct {
-	Database     bool
-	Table        bool
-	Column       bool
-	AccessObject bool
+	Database        bool
+	Table           bool
+	Column          bool
+	AccessObject    bool
+	NamedCollection bool
+	TableEngine     bool
 }
 
 var attributesByScope = map[string]ScopeAttributes{
-	"GLOBAL":       {},
-	"DATABASE":     {Database: true},
-	"TABLE":        {Database: true, Table: true},
-	"VIEW":         {Database: true, Table: true},
-	"DICTIONARY":   {Database: true, Table: true},
-	"COLUMN":       {Database: true, Table: true, Column: true},
-	"USER_NAME":    {AccessObject: true},
-	"DEFINER":      {AccessObject: true},
-	"SOURCE":       {AccessObject: true},
-	"TABLE_ENGINE": {},
+	"GLOBAL":           {},
+	"DATABASE":         {Database: true},
+	"TABLE":            {Database: true, Table: true},
+	"VIEW":             {Database: true, Table: true},
+	"DICTIONARY":       {Database: true, Table: true},
+	"COLUMN":           {Database: true, Table: true, Column: true},
+	"USER_NAME":        {AccessObject: true},
+	"DEFINER":          {AccessObject: true},
+	"SOURCE":           {AccessObject: true},
+	"NAMED_COLLECTION": {NamedCollection: true},
+	"TABLE_ENGINE":     {TableEngine: true},
 }
 
 // ScopeAttributesFor returns the attributes supported by the privilege's own
 // scope, the union over all its descendants, and whether the privilege (or any
 // descendant) has a supported scope at all.
-func ScopeAttributesFor(privilege string) (ScopeAttributes, ScopeAttributes, bool) {
-	cat := Parsed()
+func (cat Catalog) ScopeAttributesFor(privilege string) (ScopeAttributes, ScopeAttributes, bool) {
 	attrs := attributesByScope[cat.Scopes[privilege]]
 
 	allAttrs := ScopeAttributes{}
@@ -42,6 +44,8 @@ func ScopeAttributesFor(privilege string) (ScopeAttributes, ScopeAttributes, boo
 		allAttrs.Table = allAttrs.Table || a.Table
 		allAttrs.Column = allAttrs.Column || a.Column
 		allAttrs.AccessObject = allAttrs.AccessObject || a.AccessObject
+		allAttrs.NamedCollection = allAttrs.NamedCollection || a.NamedCollection
+		allAttrs.TableEngine = allAttrs.TableEngine || a.TableEngine
 	}
 
 	return attrs, allAttrs, supported
@@ -52,18 +56,20 @@ func (s ScopeAttributes) SubsetOf(o ScopeAttributes) bool {
 	return (!s.Database || o.Database) &&
 		(!s.Table || o.Table) &&
 		(!s.Column || o.Column) &&
-		(!s.AccessObject || o.AccessObject)
+		(!s.AccessObject || o.AccessObject) &&
+		(!s.NamedCollection || o.NamedCollection) &&
+		(!s.TableEngine || o.TableEngine)
 }
 
 // FoldedMembers returns the members of privilege actually granted at the requested
 // (non-global) scope, and whether the restriction silently drops members of a
-// different scope family (object hierarchy vs access object), as when a group such
-// as ALL or ACCESS MANAGEMENT is restricted to a database or an access object.
-func FoldedMembers(privilege string, requested ScopeAttributes) ([]string, bool) {
+// different scope family (object hierarchy vs access object, named collection or
+// table engine), as when a group such as ALL or ACCESS MANAGEMENT is restricted
+// to a database or an access object.
+func (cat Catalog) FoldedMembers(privilege string, requested ScopeAttributes) ([]string, bool) {
 	if requested == (ScopeAttributes{}) {
 		return nil, false
 	}
-	cat := Parsed()
 	var granted []string
 	var hasObject, hasAccessObject bool
 	for _, p := range AllDescendants(cat.Groups, privilege) {
@@ -74,7 +80,7 @@ func FoldedMembers(privilege string, requested ScopeAttributes) ([]string, bool)
 		if a.Database || a.Table || a.Column {
 			hasObject = true
 		}
-		if a.AccessObject {
+		if a.AccessObject || a.NamedCollection || a.TableEngine {
 			hasAccessObject = true
 		}
 		if requested.SubsetOf(a) {
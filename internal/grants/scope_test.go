@@ -15,14 +15,15 @@ func TestScopeAttributesFor(t *testing.T) {
 		{"CREATE USER", ScopeAttributes{AccessObject: true}, ScopeAttributes{AccessObject: true}, true},
 		{"CREATE", ScopeAttributes{}, ScopeAttributes{Database: true, Table: true}, true},
 		{"ACCESS MANAGEMENT", ScopeAttributes{}, ScopeAttributes{Database: true, Table: true, AccessObject: true}, true},
-		{"ALL", ScopeAttributes{}, ScopeAttributes{Database: true, Table: true, Column: true, AccessObject: true}, true},
-		{"TABLE ENGINE", ScopeAttributes{}, ScopeAttributes{}, true},
+		{"ALL", ScopeAttributes{}, ScopeAttributes{Database: true, Table: true, Column: true, AccessObject: true, NamedCollection: true, TableEngine: true}, true},
+		{"TABLE ENGINE", ScopeAttributes{TableEngine: true}, ScopeAttributes{TableEngine: true}, true},
 		{"READ", ScopeAttributes{AccessObject: true}, ScopeAttributes{AccessObject: true}, true},
-		{"CREATE NAMED COLLECTION", ScopeAttributes{}, ScopeAttributes{}, false},
+		{"CREATE NAMED COLLECTION", ScopeAttributes{NamedCollection: true}, ScopeAttributes{NamedCollection: true}, true},
+		{"NAMED COLLECTION ADMIN", ScopeAttributes{NamedCollection: true}, ScopeAttributes{NamedCollection: true}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.privilege, func(t *testing.T) {
-			got, gotAll, ok := ScopeAttributesFor(tt.privilege)
+			got, gotAll, ok := Parsed().ScopeAttributesFor(tt.privilege)
 			if got != tt.want || gotAll != tt.wantAll || ok != tt.supported {
 				t.Errorf("ScopeAttributesFor(%q) = %+v, %+v, %v; want %+v, %+v, %v", tt.privilege, got, gotAll, ok, tt.want, tt.wantAll, tt.supported)
 			}
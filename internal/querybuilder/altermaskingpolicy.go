@@ -19,6 +19,8 @@ type AlterMaskingPolicyQueryBuilder interface {
 }
 
 type alterMaskingPolicyQueryBuilder struct {
+	noParameters
+
 	name             string
 	database         string
 	table            string
@@ -6,7 +6,7 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// AlterRoleQueryBuilder is an interface to build ALTER ROLE SQL queries (already interpolated).
+// AlterRoleQueryBuilder is an interface to build ALTER ROLE SQL queries.
 type AlterRoleQueryBuilder interface {
 	QueryBuilder
 	RenameTo(newName *string) AlterRoleQueryBuilder
@@ -16,6 +16,8 @@ type AlterRoleQueryBuilder interface {
 }
 
 type alterRoleQueryBuilder struct {
+	noParameters
+
 	resourceName       string
 	oldSettingsProfile *string
 	newSettingsProfile *string
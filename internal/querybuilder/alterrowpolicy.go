@@ -7,6 +7,8 @@ import (
 
 // AlterRowPolicy is a query builder for ALTER ROW POLICY statements.
 type AlterRowPolicy struct {
+	noParameters
+
 	name             string
 	database         string
 	table            string
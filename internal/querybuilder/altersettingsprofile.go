@@ -6,7 +6,7 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// AlterSettingsProfileQueryBuilder is an interface to build ALTER SETTINGS PROFILE SQL queries (already interpolated).
+// AlterSettingsProfileQueryBuilder is an interface to build ALTER SETTINGS PROFILE SQL queries.
 type AlterSettingsProfileQueryBuilder interface {
 	QueryBuilder
 	RenameTo(newName *string) AlterSettingsProfileQueryBuilder
@@ -17,6 +17,8 @@ type AlterSettingsProfileQueryBuilder interface {
 }
 
 type alterSettingsProfileQueryBuilder struct {
+	noParameters
+
 	resourceName   string
 	newName        *string
 	settings       []settingData
@@ -0,0 +1,211 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// AlterTableQueryBuilder is an interface to build ALTER TABLE SQL queries. The commands are applied
+// in the order they are added.
+type AlterTableQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) AlterTableQueryBuilder
+	// AddColumn adds column after the column named after, or first when after is empty.
+	AddColumn(column ColumnDefinition, after string) AlterTableQueryBuilder
+	// ModifyColumn changes the definition of column and moves it after the column named after, or
+	// first when after is empty.
+	ModifyColumn(column ColumnDefinition, after string) AlterTableQueryBuilder
+	// RemoveColumnProperty removes a property of a column: DEFAULT, MATERIALIZED, ALIAS, CODEC,
+	// COMMENT or TTL. Modifying a column without the property keeps it.
+	RemoveColumnProperty(name string, property string) AlterTableQueryBuilder
+	DropColumn(name string) AlterTableQueryBuilder
+	ModifyComment(comment string) AlterTableQueryBuilder
+	ModifyTTL(expression string) AlterTableQueryBuilder
+	RemoveTTL() AlterTableQueryBuilder
+	ModifySettings(settings map[string]string) AlterTableQueryBuilder
+	ResetSettings(names []string) AlterTableQueryBuilder
+	// ModifyQuery replaces the SELECT query of a materialized view.
+	ModifyQuery(query string) AlterTableQueryBuilder
+	// ModifySQLSecurity changes the DEFINER and SQL SECURITY of a view. The type is required.
+	ModifySQLSecurity(security SQLSecurity) AlterTableQueryBuilder
+	// ModifyRefresh changes the schedule of a refreshable materialized view.
+	ModifyRefresh(refresh RefreshSchedule) AlterTableQueryBuilder
+}
+
+type alterTableQueryBuilder struct {
+	boundParameters
+
+	databaseName string
+	tableName    string
+	clusterName  *string
+	commands     []func() (string, error)
+}
+
+func NewAlterTable(databaseName string, tableName string) AlterTableQueryBuilder {
+	return &alterTableQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+	}
+}
+
+func (q *alterTableQueryBuilder) WithCluster(clusterName *string) AlterTableQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *alterTableQueryBuilder) AddColumn(column ColumnDefinition, after string) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		def, err := column.SQLDef()
+		if err != nil {
+			return "", err
+		}
+		return "ADD COLUMN " + def + " " + columnPosition(after), nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) ModifyColumn(column ColumnDefinition, after string) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		def, err := column.SQLDef()
+		if err != nil {
+			return "", err
+		}
+		return "MODIFY COLUMN " + def + " " + columnPosition(after), nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) RemoveColumnProperty(name string, property string) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		if property == "" {
+			return "", errors.New("property cannot be empty for REMOVE")
+		}
+		return "MODIFY COLUMN " + backtick(name) + " REMOVE " + property, nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) DropColumn(name string) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		return "DROP COLUMN " + backtick(name), nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) ModifyComment(comment string) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		return "MODIFY COMMENT " + quote(comment), nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) ModifyTTL(expression string) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		return "MODIFY TTL " + expression, nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) RemoveTTL() AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		return "REMOVE TTL", nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) ModifySettings(settings map[string]string) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		if len(settings) == 0 {
+			return "", errors.New("at least one setting is required for MODIFY SETTING")
+		}
+		return "MODIFY SETTING " + settingAssignments(settings), nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) ResetSettings(names []string) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		if len(names) == 0 {
+			return "", errors.New("at least one setting is required for RESET SETTING")
+		}
+		return "RESET SETTING " + strings.Join(backtickAll(names), ", "), nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) ModifyQuery(query string) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		query := trimQuery(query)
+		if query == "" {
+			return "", errors.New("query cannot be empty for MODIFY QUERY")
+		}
+		return "MODIFY QUERY " + query, nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) ModifySQLSecurity(security SQLSecurity) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		if security.Type == nil {
+			return "", errors.New("SQL SECURITY cannot be empty for MODIFY SQL SECURITY")
+		}
+		return "MODIFY " + security.SQLDef(), nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) ModifyRefresh(refresh RefreshSchedule) AlterTableQueryBuilder {
+	q.commands = append(q.commands, func() (string, error) {
+		def, err := refresh.SQLDef()
+		if err != nil {
+			return "", err
+		}
+		return "MODIFY " + def, nil
+	})
+	return q
+}
+
+func (q *alterTableQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for ALTER TABLE queries")
+	}
+	if q.tableName == "" {
+		return "", errors.New("tableName cannot be empty for ALTER TABLE queries")
+	}
+	if len(q.commands) == 0 {
+		return "", errors.New("at least one command is required for ALTER TABLE queries")
+	}
+
+	params := newParameters()
+
+	tokens := []string{
+		"ALTER",
+		"TABLE",
+		tableIdentifier(params, q.databaseName, q.tableName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+
+	commands := make([]string, 0, len(q.commands))
+	for _, command := range q.commands {
+		c, err := command()
+		if err != nil {
+			return "", errors.WithMessage(err, "invalid command")
+		}
+		commands = append(commands, c)
+	}
+	tokens = append(tokens, strings.Join(commands, ", "))
+
+	q.params = params.values
+
+	return strings.Join(tokens, " ") + ";", nil
+}
+
+func columnPosition(after string) string {
+	if after == "" {
+		return "FIRST"
+	}
+	return "AFTER " + backtick(after)
+}
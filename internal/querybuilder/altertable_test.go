@@ -0,0 +1,104 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func Test_altertable(t *testing.T) {
+	const table = "ALTER TABLE {identifier_0:Identifier}.{identifier_1:Identifier} "
+
+	tests := []struct {
+		name    string
+		builder AlterTableQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Add column first",
+			builder: NewAlterTable("db", "t").AddColumn(ColumnDefinition{Name: "id", Type: "UInt64"}, ""),
+			want:    table + "ADD COLUMN `id` UInt64 FIRST;",
+		},
+		{
+			name:    "Add column after another on cluster",
+			builder: NewAlterTable("db", "t").WithCluster(new("cluster1")).AddColumn(ColumnDefinition{Name: "ts", Type: "DateTime", Default: new("now()")}, "id"),
+			want:    table + "ON CLUSTER 'cluster1' ADD COLUMN `ts` DateTime DEFAULT now() AFTER `id`;",
+		},
+		{
+			name:    "Modify column",
+			builder: NewAlterTable("db", "t").ModifyColumn(ColumnDefinition{Name: "ts", Type: "DateTime64(3)", Comment: new("event time")}, "id"),
+			want:    table + "MODIFY COLUMN `ts` DateTime64(3) COMMENT 'event time' AFTER `id`;",
+		},
+		{
+			name:    "Remove column property",
+			builder: NewAlterTable("db", "t").RemoveColumnProperty("ts", "DEFAULT"),
+			want:    table + "MODIFY COLUMN `ts` REMOVE DEFAULT;",
+		},
+		{
+			name:    "Drop column",
+			builder: NewAlterTable("db", "t").DropColumn("old`col"),
+			want:    table + "DROP COLUMN `old\\`col`;",
+		},
+		{
+			name:    "Table properties",
+			builder: NewAlterTable("db", "t").ModifyComment("it's new").ModifyTTL("ts + INTERVAL 1 DAY"),
+			want:    table + "MODIFY COMMENT 'it\\'s new', MODIFY TTL ts + INTERVAL 1 DAY;",
+		},
+		{
+			name:    "Remove TTL",
+			builder: NewAlterTable("db", "t").RemoveTTL(),
+			want:    table + "REMOVE TTL;",
+		},
+		{
+			name:    "Settings",
+			builder: NewAlterTable("db", "t").ModifySettings(map[string]string{"merge_with_ttl_timeout": "3600"}).ResetSettings([]string{"ttl_only_drop_parts"}),
+			want:    table + "MODIFY SETTING `merge_with_ttl_timeout` = '3600', RESET SETTING `ttl_only_drop_parts`;",
+		},
+		{
+			name:    "Modify query",
+			builder: NewAlterTable("db", "mv").ModifyQuery("SELECT id FROM db.t;\n"),
+			want:    table + "MODIFY QUERY SELECT id FROM db.t;",
+		},
+		{
+			name:    "Modify SQL security",
+			builder: NewAlterTable("db", "v").ModifySQLSecurity(SQLSecurity{Definer: new("alice"), Type: new("DEFINER")}),
+			want:    table + "MODIFY DEFINER = `alice` SQL SECURITY DEFINER;",
+		},
+		{
+			name:    "Modify refresh",
+			builder: NewAlterTable("db", "mv").ModifyRefresh(RefreshSchedule{Kind: "AFTER", Interval: "10 MINUTE", DependsOn: []string{"db.other_mv"}}),
+			want:    table + "MODIFY REFRESH AFTER 10 MINUTE DEPENDS ON `db`.`other_mv`;",
+		},
+		{
+			name:    "Fail to modify SQL security without type",
+			builder: NewAlterTable("db", "v").ModifySQLSecurity(SQLSecurity{Definer: new("alice")}),
+			wantErr: true,
+		},
+		{
+			name:    "Fail without commands",
+			builder: NewAlterTable("db", "t"),
+			wantErr: true,
+		},
+		{
+			name:    "Fail with invalid column",
+			builder: NewAlterTable("db", "t").AddColumn(ColumnDefinition{Name: "id"}, ""),
+			wantErr: true,
+		},
+		{
+			name:    "Fail without settings",
+			builder: NewAlterTable("db", "t").ResetSettings(nil),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
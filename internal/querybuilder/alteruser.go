@@ -6,7 +6,7 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// AlterUserQueryBuilder is an interface to build ALTER USER SQL queries (already interpolated).
+// AlterUserQueryBuilder is an interface to build ALTER USER SQL queries.
 type AlterUserQueryBuilder interface {
 	QueryBuilder
 	RenameTo(newName *string) AlterUserQueryBuilder
@@ -14,13 +14,13 @@ type AlterUserQueryBuilder interface {
 	DropSettingsProfile(profileName *string) AlterUserQueryBuilder
 	AddSettingsProfile(profileName *string) AlterUserQueryBuilder
 	WithCluster(clusterName *string) AlterUserQueryBuilder
-	Parameters() map[string]string
 }
 
 type alterUserQueryBuilder struct {
+	boundParameters
+
 	resourceName       string
-	identified         string
-	params             map[string]string
+	authMethods        []AuthMethod
 	oldSettingsProfile *string
 	newSettingsProfile *string
 	newName            *string
@@ -40,14 +40,10 @@ func (q *alterUserQueryBuilder) RenameTo(newName *string) AlterUserQueryBuilder
 }
 
 func (q *alterUserQueryBuilder) Identified(methods []AuthMethod) AlterUserQueryBuilder {
-	q.identified, q.params = identifiedClause(methods)
+	q.authMethods = methods
 	return q
 }
 
-func (q *alterUserQueryBuilder) Parameters() map[string]string {
-	return q.params
-}
-
 func (q *alterUserQueryBuilder) DropSettingsProfile(profileName *string) AlterUserQueryBuilder {
 	q.oldSettingsProfile = profileName
 	return q
@@ -84,9 +80,10 @@ func (q *alterUserQueryBuilder) Build() (string, error) {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
 	}
 
-	if q.identified != "" {
+	params := newParameters()
+	if identified := identifiedClause(q.authMethods, params); identified != "" {
 		anyChanges = true
-		tokens = append(tokens, q.identified)
+		tokens = append(tokens, identified)
 	}
 
 	if (q.oldSettingsProfile != nil && q.newSettingsProfile != nil && *q.oldSettingsProfile != *q.newSettingsProfile) ||
@@ -107,5 +104,7 @@ func (q *alterUserQueryBuilder) Build() (string, error) {
 		return "", errors.New("no change to be made")
 	}
 
+	q.params = params.values
+
 	return strings.Join(tokens, " ") + ";", nil
 }
@@ -15,11 +15,11 @@ type andWhere struct {
 	clauses []Where
 }
 
-func (s *andWhere) Clause() string {
+func (s *andWhere) clause(params *parameters) string {
 	tokens := make([]string, 0)
 
 	for _, c := range s.clauses {
-		tokens = append(tokens, c.Clause())
+		tokens = append(tokens, c.clause(params))
 	}
 
 	return fmt.Sprintf("(%s)", strings.Join(tokens, " AND "))
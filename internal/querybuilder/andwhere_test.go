@@ -33,7 +33,7 @@ func Test_AndWhere_String(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.where.Clause(); got != tt.want {
+			if got := tt.where.clause(newParameters()); got != tt.want {
 				t.Errorf("String() = %v, want %v", got, tt.want)
 			}
 		})
@@ -50,6 +50,6 @@ func WhereMock(str string) Where {
 	}
 }
 
-func (w whereMock) Clause() string {
+func (w whereMock) clause(_ *parameters) string {
 	return w.Value
 }
@@ -1,7 +1,6 @@
 package querybuilder
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -69,14 +68,13 @@ var methodRenderSpecs = map[Identification]methodRenderSpec{
 	IdentificationKerberos:           {chType: "kerberos", args: []methodArg{{keyword: "REALM", optional: true}}},
 }
 
-// identifiedClause renders "IDENTIFIED WITH m1, m2, ..." for the given methods and the server-side
-// query parameters carrying any secret values
-func identifiedClause(methods []AuthMethod) (string, map[string]string) {
+// identifiedClause renders "IDENTIFIED WITH m1, m2, ..." for the given methods, binding any secret
+// values to params.
+func identifiedClause(methods []AuthMethod, params *parameters) string {
 	if len(methods) == 0 {
-		return "", nil
+		return ""
 	}
 
-	params := map[string]string{}
 	clauses := make([]string, 0, len(methods))
 	for _, m := range methods {
 		spec := methodRenderSpecs[m.Type]
@@ -89,9 +87,7 @@ func identifiedClause(methods []AuthMethod) (string, map[string]string) {
 				continue
 			}
 			if a.secret {
-				name := fmt.Sprintf("secret_%d", len(params))
-				parts = append(parts, a.keyword, fmt.Sprintf("{%s:String}", name))
-				params[name] = m.Args[i]
+				parts = append(parts, a.keyword, params.secret(m.Args[i]))
 			} else {
 				parts = append(parts, a.keyword, quote(m.Args[i]))
 			}
@@ -99,5 +95,5 @@ func identifiedClause(methods []AuthMethod) (string, map[string]string) {
 		clauses = append(clauses, strings.Join(parts, " "))
 	}
 
-	return "IDENTIFIED WITH " + strings.Join(clauses, ", "), params
+	return "IDENTIFIED WITH " + strings.Join(clauses, ", ")
 }
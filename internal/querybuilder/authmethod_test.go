@@ -51,11 +51,12 @@ func Test_identifiedClause(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, params := identifiedClause(tt.methods)
+			params := newParameters()
+			got := identifiedClause(tt.methods, params)
 			if got != tt.want {
 				t.Errorf("identifiedClause() clause = %q, want %q", got, tt.want)
 			}
-			if !maps.Equal(params, tt.wantParams) {
+			if !maps.Equal(params.values, tt.wantParams) {
 				t.Errorf("identifiedClause() params = %v, want %v", params, tt.wantParams)
 			}
 		})
@@ -6,7 +6,7 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// CreateDatabaseQueryBuilder is an interface to build CREATE DATABASE SQL queries (already interpolated).
+// CreateDatabaseQueryBuilder is an interface to build CREATE DATABASE SQL queries.
 type CreateDatabaseQueryBuilder interface {
 	QueryBuilder
 	WithComment(comment string) CreateDatabaseQueryBuilder
@@ -14,6 +14,8 @@ type CreateDatabaseQueryBuilder interface {
 }
 
 type createDatabaseQueryBuilder struct {
+	boundParameters
+
 	databaseName string
 	comment      *string
 	clusterName  *string
@@ -40,10 +42,12 @@ func (q *createDatabaseQueryBuilder) Build() (string, error) {
 		return "", errors.New("databaseName cannot be empty for CREATE DATABASE queries")
 	}
 
+	params := newParameters()
+
 	tokens := []string{
 		"CREATE",
 		"DATABASE",
-		backtick(q.databaseName),
+		params.identifier(q.databaseName),
 	}
 	if q.clusterName != nil {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
@@ -52,5 +56,7 @@ func (q *createDatabaseQueryBuilder) Build() (string, error) {
 		tokens = append(tokens, "COMMENT", quote(*q.comment))
 	}
 
+	q.params = params.values
+
 	return strings.Join(tokens, " ") + ";", nil
 }
@@ -1,6 +1,7 @@
 package querybuilder
 
 import (
+	"maps"
 	"testing"
 )
 
@@ -14,13 +15,15 @@ func Test_createdatabase(t *testing.T) {
 		clusterName  *string
 		identified   string
 		want         string
+		wantParams   map[string]string
 		wantErr      bool
 	}{
 		{
 			name:         "Create database with complex name",
 			resourceType: resourceTypeDatabase,
 			resourceName: "data`base",
-			want:         "CREATE DATABASE `data\\`base`;",
+			want:         "CREATE DATABASE {identifier_0:Identifier};",
+			wantParams:   map[string]string{"identifier_0": "data`base"},
 			wantErr:      false,
 		},
 		{
@@ -28,7 +31,8 @@ func Test_createdatabase(t *testing.T) {
 			resourceType: resourceTypeDatabase,
 			resourceName: "database",
 			comment:      new("this is the comment"),
-			want:         "CREATE DATABASE `database` COMMENT 'this is the comment';",
+			want:         "CREATE DATABASE {identifier_0:Identifier} COMMENT 'this is the comment';",
+			wantParams:   map[string]string{"identifier_0": "database"},
 			wantErr:      false,
 		},
 		{
@@ -36,7 +40,8 @@ func Test_createdatabase(t *testing.T) {
 			resourceType: resourceTypeDatabase,
 			resourceName: "database",
 			clusterName:  new("default"),
-			want:         "CREATE DATABASE `database` ON CLUSTER 'default';",
+			want:         "CREATE DATABASE {identifier_0:Identifier} ON CLUSTER 'default';",
+			wantParams:   map[string]string{"identifier_0": "database"},
 			wantErr:      false,
 		},
 	}
@@ -61,6 +66,9 @@ func Test_createdatabase(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("Build() got = %v, want %v", got, tt.want)
 			}
+			if !maps.Equal(q.Parameters(), tt.wantParams) {
+				t.Errorf("Parameters() got = %v, want %v", q.Parameters(), tt.wantParams)
+			}
 		})
 	}
 }
@@ -0,0 +1,272 @@
+package querybuilder
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+var (
+	dictionaryKeywordRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	numberRegexp            = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// DictionaryAttribute is a key or an attribute of a dictionary, as written in CREATE DICTIONARY queries.
+type DictionaryAttribute struct {
+	Name string
+	Type string
+	// Default is the expression returned for missing keys.
+	Default      *string
+	Expression   *string
+	Hierarchical bool
+	Injective    bool
+	IsObjectID   bool
+}
+
+// SQLDef renders the attribute as it appears in the structure of the dictionary.
+func (a DictionaryAttribute) SQLDef() (string, error) {
+	if a.Name == "" {
+		return "", errors.New("Name can't be empty")
+	}
+	if a.Type == "" {
+		return "", errors.New(fmt.Sprintf("Type of attribute %q can't be empty", a.Name))
+	}
+
+	tokens := []string{backtick(a.Name), a.Type}
+	if a.Default != nil {
+		tokens = append(tokens, "DEFAULT", *a.Default)
+	}
+	if a.Expression != nil {
+		tokens = append(tokens, "EXPRESSION", *a.Expression)
+	}
+	if a.Hierarchical {
+		tokens = append(tokens, "HIERARCHICAL")
+	}
+	if a.Injective {
+		tokens = append(tokens, "INJECTIVE")
+	}
+	if a.IsObjectID {
+		tokens = append(tokens, "IS_OBJECT_ID")
+	}
+
+	return strings.Join(tokens, " "), nil
+}
+
+// DictionaryFunction is the source or the layout of a dictionary, such as `CLICKHOUSE(host 'localhost' port 9000)`.
+type DictionaryFunction struct {
+	Name       string
+	Parameters map[string]string
+}
+
+// SQLDef renders the function with its parameters sorted by name. Numbers are written as is and
+// other values as strings. Passwords are escaped like other values too: ClickHouse hides them in its
+// logs and in SHOW CREATE.
+func (f DictionaryFunction) SQLDef() (string, error) {
+	if !dictionaryKeywordRegexp.MatchString(f.Name) {
+		return "", errors.New(fmt.Sprintf("invalid name %q", f.Name))
+	}
+
+	parameters := make([]string, 0, len(f.Parameters))
+	for _, name := range slices.Sorted(maps.Keys(f.Parameters)) {
+		if !dictionaryKeywordRegexp.MatchString(name) {
+			return "", errors.New(fmt.Sprintf("invalid parameter name %q", name))
+		}
+		parameters = append(parameters, name+" "+numberOrString(f.Parameters[name]))
+	}
+
+	return fmt.Sprintf("%s(%s)", f.Name, strings.Join(parameters, " ")), nil
+}
+
+// CreateDictionaryQueryBuilder is an interface to build CREATE DICTIONARY SQL queries.
+type CreateDictionaryQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) CreateDictionaryQueryBuilder
+	// WithOrReplace replaces the dictionary atomically if it exists.
+	WithOrReplace(orReplace bool) CreateDictionaryQueryBuilder
+	// WithKeys sets the attributes making the primary key of the dictionary.
+	WithKeys(keys []DictionaryAttribute) CreateDictionaryQueryBuilder
+	WithAttributes(attributes []DictionaryAttribute) CreateDictionaryQueryBuilder
+	WithSource(source DictionaryFunction) CreateDictionaryQueryBuilder
+	WithLayout(layout DictionaryFunction) CreateDictionaryQueryBuilder
+	// WithLifetime sets the update interval in seconds. Only min is written when max is nil.
+	WithLifetime(min *int64, max *int64) CreateDictionaryQueryBuilder
+	// WithRange sets the attributes holding the range of validity of range_hashed dictionaries.
+	WithRange(min *string, max *string) CreateDictionaryQueryBuilder
+	WithSettings(settings map[string]string) CreateDictionaryQueryBuilder
+	WithComment(comment *string) CreateDictionaryQueryBuilder
+}
+
+type createDictionaryQueryBuilder struct {
+	boundParameters
+
+	databaseName   string
+	dictionaryName string
+	clusterName    *string
+	orReplace      bool
+	keys           []DictionaryAttribute
+	attributes     []DictionaryAttribute
+	source         DictionaryFunction
+	layout         DictionaryFunction
+	lifetimeMin    *int64
+	lifetimeMax    *int64
+	rangeMin       *string
+	rangeMax       *string
+	settings       map[string]string
+	comment        *string
+}
+
+func NewCreateDictionary(databaseName string, dictionaryName string) CreateDictionaryQueryBuilder {
+	return &createDictionaryQueryBuilder{
+		databaseName:   databaseName,
+		dictionaryName: dictionaryName,
+	}
+}
+
+func (q *createDictionaryQueryBuilder) WithCluster(clusterName *string) CreateDictionaryQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithOrReplace(orReplace bool) CreateDictionaryQueryBuilder {
+	q.orReplace = orReplace
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithKeys(keys []DictionaryAttribute) CreateDictionaryQueryBuilder {
+	q.keys = keys
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithAttributes(attributes []DictionaryAttribute) CreateDictionaryQueryBuilder {
+	q.attributes = attributes
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithSource(source DictionaryFunction) CreateDictionaryQueryBuilder {
+	q.source = source
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithLayout(layout DictionaryFunction) CreateDictionaryQueryBuilder {
+	q.layout = layout
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithLifetime(min *int64, max *int64) CreateDictionaryQueryBuilder {
+	q.lifetimeMin = min
+	q.lifetimeMax = max
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithRange(min *string, max *string) CreateDictionaryQueryBuilder {
+	q.rangeMin = min
+	q.rangeMax = max
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithSettings(settings map[string]string) CreateDictionaryQueryBuilder {
+	q.settings = settings
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) WithComment(comment *string) CreateDictionaryQueryBuilder {
+	q.comment = comment
+	return q
+}
+
+func (q *createDictionaryQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for CREATE DICTIONARY queries")
+	}
+	if q.dictionaryName == "" {
+		return "", errors.New("dictionaryName cannot be empty for CREATE DICTIONARY queries")
+	}
+	if len(q.keys) == 0 {
+		return "", errors.New("at least one key is required for CREATE DICTIONARY queries")
+	}
+	if q.lifetimeMin == nil && q.lifetimeMax != nil {
+		return "", errors.New("the minimum lifetime is required with the maximum one for CREATE DICTIONARY queries")
+	}
+	if (q.rangeMin == nil) != (q.rangeMax == nil) {
+		return "", errors.New("both bounds of RANGE are required for CREATE DICTIONARY queries")
+	}
+
+	params := newParameters()
+
+	structure := make([]string, 0, len(q.keys)+len(q.attributes))
+	keys := make([]string, 0, len(q.keys))
+	for _, a := range q.keys {
+		def, err := a.SQLDef()
+		if err != nil {
+			return "", errors.WithMessage(err, "invalid key")
+		}
+		structure = append(structure, def)
+		keys = append(keys, backtick(a.Name))
+	}
+	for _, a := range q.attributes {
+		def, err := a.SQLDef()
+		if err != nil {
+			return "", errors.WithMessage(err, "invalid attribute")
+		}
+		structure = append(structure, def)
+	}
+
+	source, err := q.source.SQLDef()
+	if err != nil {
+		return "", errors.WithMessage(err, "invalid source")
+	}
+	layout, err := q.layout.SQLDef()
+	if err != nil {
+		return "", errors.WithMessage(err, "invalid layout")
+	}
+
+	tokens := []string{"CREATE"}
+	if q.orReplace {
+		tokens = append(tokens, "OR", "REPLACE")
+	}
+	tokens = append(tokens, "DICTIONARY", tableIdentifier(params, q.databaseName, q.dictionaryName))
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	tokens = append(tokens,
+		"("+strings.Join(structure, ", ")+")",
+		"PRIMARY", "KEY", strings.Join(keys, ", "),
+		"SOURCE("+source+")",
+		"LAYOUT("+layout+")",
+	)
+	switch {
+	case q.lifetimeMax != nil:
+		tokens = append(tokens, fmt.Sprintf("LIFETIME(MIN %d MAX %d)", *q.lifetimeMin, *q.lifetimeMax))
+	case q.lifetimeMin != nil:
+		tokens = append(tokens, fmt.Sprintf("LIFETIME(%d)", *q.lifetimeMin))
+	}
+	if q.rangeMin != nil {
+		tokens = append(tokens, fmt.Sprintf("RANGE(MIN %s MAX %s)", backtick(*q.rangeMin), backtick(*q.rangeMax)))
+	}
+	if len(q.settings) > 0 {
+		settings := make([]string, 0, len(q.settings))
+		for _, name := range slices.Sorted(maps.Keys(q.settings)) {
+			settings = append(settings, fmt.Sprintf("%s = %s", backtick(name), numberOrString(q.settings[name])))
+		}
+		tokens = append(tokens, "SETTINGS("+strings.Join(settings, ", ")+")")
+	}
+	if q.comment != nil {
+		tokens = append(tokens, "COMMENT", quote(*q.comment))
+	}
+
+	q.params = params.values
+
+	return strings.Join(tokens, " ") + ";", nil
+}
+
+// numberOrString renders value as a number when it is one, and as a string otherwise.
+func numberOrString(value string) string {
+	if numberRegexp.MatchString(value) {
+		return value
+	}
+	return quote(value)
+}
@@ -0,0 +1,112 @@
+package querybuilder
+
+import (
+	"maps"
+	"testing"
+)
+
+func Test_createdictionary(t *testing.T) {
+	const dictionary = "DICTIONARY {identifier_0:Identifier}.{identifier_1:Identifier} "
+
+	tests := []struct {
+		name       string
+		builder    CreateDictionaryQueryBuilder
+		want       string
+		wantParams map[string]string
+		wantErr    bool
+	}{
+		{
+			name: "ClickHouse source",
+			builder: NewCreateDictionary("db", "countries").
+				WithKeys([]DictionaryAttribute{{Name: "id", Type: "UInt64"}}).
+				WithAttributes([]DictionaryAttribute{
+					{Name: "name", Type: "String", Default: new("''"), Injective: true},
+					{Name: "parent_id", Type: "UInt64", Default: new("0"), Hierarchical: true},
+					{Name: "code", Type: "String", Expression: new("upper(iso)")},
+				}).
+				WithSource(DictionaryFunction{Name: "CLICKHOUSE", Parameters: map[string]string{"db": "ref", "table": "countries", "port": "9000", "password": "it's"}}).
+				WithLayout(DictionaryFunction{Name: "HASHED"}).
+				WithLifetime(new(int64(300)), new(int64(360))).
+				WithSettings(map[string]string{"format_csv_allow_single_quotes": "0"}).
+				WithComment(new("ISO countries")),
+			want: "CREATE " + dictionary + "(" +
+				"`id` UInt64, " +
+				"`name` String DEFAULT '' INJECTIVE, " +
+				"`parent_id` UInt64 DEFAULT 0 HIERARCHICAL, " +
+				"`code` String EXPRESSION upper(iso)" +
+				") PRIMARY KEY `id` " +
+				"SOURCE(CLICKHOUSE(db 'ref' password 'it\\'s' port 9000 table 'countries')) " +
+				"LAYOUT(HASHED()) LIFETIME(MIN 300 MAX 360) " +
+				"SETTINGS(`format_csv_allow_single_quotes` = 0) COMMENT 'ISO countries';",
+			wantParams: map[string]string{"identifier_0": "db", "identifier_1": "countries"},
+		},
+		{
+			name: "Replace range dictionary with complex key on cluster",
+			builder: NewCreateDictionary("db", "prices").
+				WithCluster(new("cluster1")).
+				WithOrReplace(true).
+				WithKeys([]DictionaryAttribute{{Name: "sku", Type: "String"}, {Name: "region", Type: "String"}}).
+				WithAttributes([]DictionaryAttribute{
+					{Name: "start", Type: "Date"},
+					{Name: "end", Type: "Date"},
+					{Name: "price", Type: "Float64"},
+				}).
+				WithSource(DictionaryFunction{Name: "POSTGRESQL", Parameters: map[string]string{"host": "pg", "table": "prices"}}).
+				WithLayout(DictionaryFunction{Name: "COMPLEX_KEY_RANGE_HASHED", Parameters: map[string]string{"range_lookup_strategy": "max"}}).
+				WithLifetime(new(int64(0)), nil).
+				WithRange(new("start"), new("end")),
+			want: "CREATE OR REPLACE " + dictionary + "ON CLUSTER 'cluster1' (" +
+				"`sku` String, `region` String, `start` Date, `end` Date, `price` Float64" +
+				") PRIMARY KEY `sku`, `region` " +
+				"SOURCE(POSTGRESQL(host 'pg' table 'prices')) " +
+				"LAYOUT(COMPLEX_KEY_RANGE_HASHED(range_lookup_strategy 'max')) LIFETIME(0) RANGE(MIN `start` MAX `end`);",
+			wantParams: map[string]string{"identifier_0": "db", "identifier_1": "prices"},
+		},
+		{
+			name: "Fail without keys",
+			builder: NewCreateDictionary("db", "d").
+				WithSource(DictionaryFunction{Name: "NULL"}).
+				WithLayout(DictionaryFunction{Name: "FLAT"}),
+			wantErr: true,
+		},
+		{
+			name: "Fail with invalid parameter name",
+			builder: NewCreateDictionary("db", "d").
+				WithKeys([]DictionaryAttribute{{Name: "id", Type: "UInt64"}}).
+				WithSource(DictionaryFunction{Name: "FILE", Parameters: map[string]string{"path) LAYOUT(": "x"}}).
+				WithLayout(DictionaryFunction{Name: "FLAT"}),
+			wantErr: true,
+		},
+		{
+			name: "Fail without layout",
+			builder: NewCreateDictionary("db", "d").
+				WithKeys([]DictionaryAttribute{{Name: "id", Type: "UInt64"}}).
+				WithSource(DictionaryFunction{Name: "NULL"}),
+			wantErr: true,
+		},
+		{
+			name: "Fail with half a range",
+			builder: NewCreateDictionary("db", "d").
+				WithKeys([]DictionaryAttribute{{Name: "id", Type: "UInt64"}}).
+				WithSource(DictionaryFunction{Name: "NULL"}).
+				WithLayout(DictionaryFunction{Name: "RANGE_HASHED"}).
+				WithRange(new("start"), nil),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+			if !maps.Equal(tt.builder.Parameters(), tt.wantParams) {
+				t.Errorf("Parameters() got = %v, want %v", tt.builder.Parameters(), tt.wantParams)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// CreateMaterializedViewQueryBuilder is an interface to build CREATE MATERIALIZED VIEW SQL queries.
+// The view either writes to an existing table, set with WithTo, or stores its data in an inner
+// table, set with WithEngine.
+type CreateMaterializedViewQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) CreateMaterializedViewQueryBuilder
+	WithRefresh(refresh *RefreshSchedule) CreateMaterializedViewQueryBuilder
+	WithTo(databaseName string, tableName string) CreateMaterializedViewQueryBuilder
+	WithEngine(engine *string) CreateMaterializedViewQueryBuilder
+	WithOrderBy(expression *string) CreateMaterializedViewQueryBuilder
+	WithPartitionBy(expression *string) CreateMaterializedViewQueryBuilder
+	// WithPopulate fills the inner table with the existing data of the source table at creation.
+	WithPopulate(populate bool) CreateMaterializedViewQueryBuilder
+	WithSQLSecurity(security SQLSecurity) CreateMaterializedViewQueryBuilder
+	WithQuery(query string) CreateMaterializedViewQueryBuilder
+	WithComment(comment *string) CreateMaterializedViewQueryBuilder
+}
+
+type createMaterializedViewQueryBuilder struct {
+	boundParameters
+
+	databaseName   string
+	viewName       string
+	clusterName    *string
+	refresh        *RefreshSchedule
+	toDatabaseName string
+	toTableName    string
+	engine         *string
+	orderBy        *string
+	partitionBy    *string
+	populate       bool
+	security       SQLSecurity
+	query          string
+	comment        *string
+}
+
+func NewCreateMaterializedView(databaseName string, viewName string) CreateMaterializedViewQueryBuilder {
+	return &createMaterializedViewQueryBuilder{
+		databaseName: databaseName,
+		viewName:     viewName,
+	}
+}
+
+func (q *createMaterializedViewQueryBuilder) WithCluster(clusterName *string) CreateMaterializedViewQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithRefresh(refresh *RefreshSchedule) CreateMaterializedViewQueryBuilder {
+	q.refresh = refresh
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithTo(databaseName string, tableName string) CreateMaterializedViewQueryBuilder {
+	q.toDatabaseName = databaseName
+	q.toTableName = tableName
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithEngine(engine *string) CreateMaterializedViewQueryBuilder {
+	q.engine = engine
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithOrderBy(expression *string) CreateMaterializedViewQueryBuilder {
+	q.orderBy = expression
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithPartitionBy(expression *string) CreateMaterializedViewQueryBuilder {
+	q.partitionBy = expression
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithPopulate(populate bool) CreateMaterializedViewQueryBuilder {
+	q.populate = populate
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithSQLSecurity(security SQLSecurity) CreateMaterializedViewQueryBuilder {
+	q.security = security
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithQuery(query string) CreateMaterializedViewQueryBuilder {
+	q.query = query
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) WithComment(comment *string) CreateMaterializedViewQueryBuilder {
+	q.comment = comment
+	return q
+}
+
+func (q *createMaterializedViewQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for CREATE MATERIALIZED VIEW queries")
+	}
+	if q.viewName == "" {
+		return "", errors.New("viewName cannot be empty for CREATE MATERIALIZED VIEW queries")
+	}
+	query := trimQuery(q.query)
+	if query == "" {
+		return "", errors.New("query cannot be empty for CREATE MATERIALIZED VIEW queries")
+	}
+	if (q.toTableName == "") == (q.engine == nil) {
+		return "", errors.New("exactly one of the TO table and the engine must be set for CREATE MATERIALIZED VIEW queries")
+	}
+	if q.toTableName != "" && q.toDatabaseName == "" {
+		return "", errors.New("the database of the TO table cannot be empty for CREATE MATERIALIZED VIEW queries")
+	}
+	if q.engine == nil && (q.orderBy != nil || q.partitionBy != nil) {
+		return "", errors.New("ORDER BY and PARTITION BY require an engine for CREATE MATERIALIZED VIEW queries")
+	}
+	if q.populate && (q.engine == nil || q.refresh != nil) {
+		return "", errors.New("POPULATE requires an engine and cannot be used with REFRESH for CREATE MATERIALIZED VIEW queries")
+	}
+
+	params := newParameters()
+
+	tokens := []string{
+		"CREATE",
+		"MATERIALIZED",
+		"VIEW",
+		tableIdentifier(params, q.databaseName, q.viewName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	if q.refresh != nil {
+		refresh, err := q.refresh.SQLDef()
+		if err != nil {
+			return "", errors.WithMessage(err, "invalid refresh")
+		}
+		tokens = append(tokens, refresh)
+	}
+	if q.toTableName != "" {
+		tokens = append(tokens, "TO", backtick(q.toDatabaseName)+"."+backtick(q.toTableName))
+	}
+	if q.engine != nil {
+		tokens = append(tokens, "ENGINE", "=", *q.engine)
+		if q.orderBy != nil {
+			tokens = append(tokens, "ORDER BY", *q.orderBy)
+		}
+		if q.partitionBy != nil {
+			tokens = append(tokens, "PARTITION BY", *q.partitionBy)
+		}
+	}
+	if q.populate {
+		tokens = append(tokens, "POPULATE")
+	}
+	if security := q.security.SQLDef(); security != "" {
+		tokens = append(tokens, security)
+	}
+	tokens = append(tokens, "AS", query)
+	if q.comment != nil {
+		tokens = append(tokens, "COMMENT", quote(*q.comment))
+	}
+
+	q.params = params.values
+
+	return strings.Join(tokens, " ") + ";", nil
+}
@@ -0,0 +1,84 @@
+package querybuilder
+
+import (
+	"testing"
+)
+
+func Test_creatematerializedview(t *testing.T) {
+	const view = "CREATE MATERIALIZED VIEW {identifier_0:Identifier}.{identifier_1:Identifier} "
+
+	tests := []struct {
+		name    string
+		builder CreateMaterializedViewQueryBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "View writing to a table",
+			builder: NewCreateMaterializedView("db", "mv").
+				WithTo("db", "totals").
+				WithSQLSecurity(SQLSecurity{Definer: new("CURRENT_USER"), Type: new("DEFINER")}).
+				WithQuery("SELECT id, count() AS c FROM db.events GROUP BY id"),
+			want: view + "TO `db`.`totals` DEFINER = CURRENT_USER SQL SECURITY DEFINER AS SELECT id, count() AS c FROM db.events GROUP BY id;",
+		},
+		{
+			name: "View with an inner table populated on cluster",
+			builder: NewCreateMaterializedView("db", "mv").
+				WithCluster(new("cluster1")).
+				WithEngine(new("SummingMergeTree")).
+				WithOrderBy(new("id")).
+				WithPartitionBy(new("toYYYYMM(day)")).
+				WithPopulate(true).
+				WithQuery("SELECT id, day, count() AS c FROM db.events GROUP BY id, day").
+				WithComment(new("daily counts")),
+			want: view + "ON CLUSTER 'cluster1' ENGINE = SummingMergeTree ORDER BY id PARTITION BY toYYYYMM(day) POPULATE AS SELECT id, day, count() AS c FROM db.events GROUP BY id, day COMMENT 'daily counts';",
+		},
+		{
+			name: "Refreshable view",
+			builder: NewCreateMaterializedView("db", "mv").
+				WithRefresh(&RefreshSchedule{
+					Kind:         "EVERY",
+					Interval:     "1 HOUR",
+					Offset:       new("5 MINUTE"),
+					RandomizeFor: new("1 MINUTE"),
+					DependsOn:    []string{"db.first", "second"},
+					Append:       true,
+				}).
+				WithTo("reports", "hourly").
+				WithQuery("SELECT now() AS ts, count() AS c FROM db.events"),
+			want: view + "REFRESH EVERY 1 HOUR OFFSET 5 MINUTE RANDOMIZE FOR 1 MINUTE DEPENDS ON `db`.`first`, `second` APPEND TO `reports`.`hourly` AS SELECT now() AS ts, count() AS c FROM db.events;",
+		},
+		{
+			name:    "Fail without target",
+			builder: NewCreateMaterializedView("db", "mv").WithQuery("SELECT 1"),
+			wantErr: true,
+		},
+		{
+			name:    "Fail with both target and engine",
+			builder: NewCreateMaterializedView("db", "mv").WithTo("db", "t").WithEngine(new("Memory")).WithQuery("SELECT 1"),
+			wantErr: true,
+		},
+		{
+			name:    "Fail to populate a table",
+			builder: NewCreateMaterializedView("db", "mv").WithTo("db", "t").WithPopulate(true).WithQuery("SELECT 1"),
+			wantErr: true,
+		},
+		{
+			name:    "Fail with invalid refresh",
+			builder: NewCreateMaterializedView("db", "mv").WithRefresh(&RefreshSchedule{Kind: "SOMETIMES", Interval: "1 HOUR"}).WithTo("db", "t").WithQuery("SELECT 1"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
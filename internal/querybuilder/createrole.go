@@ -6,13 +6,15 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// CreateRoleQueryBuilder is an interface to build CREATE ROLE SQL queries (already interpolated).
+// CreateRoleQueryBuilder is an interface to build CREATE ROLE SQL queries.
 type CreateRoleQueryBuilder interface {
 	QueryBuilder
 	WithCluster(clusterName *string) CreateRoleQueryBuilder
 }
 
 type createRoleQueryBuilder struct {
+	noParameters
+
 	resourceName string
 	clusterName  *string
 }
@@ -6,7 +6,7 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// CreateSettingsProfileQueryBuilder is an interface to build CREATE SETTINGS PROFILE SQL queries (already interpolated).
+// CreateSettingsProfileQueryBuilder is an interface to build CREATE SETTINGS PROFILE SQL queries.
 type CreateSettingsProfileQueryBuilder interface {
 	QueryBuilder
 	WithCluster(clusterName *string) CreateSettingsProfileQueryBuilder
@@ -14,6 +14,8 @@ type CreateSettingsProfileQueryBuilder interface {
 }
 
 type createSettingsProfileQueryBuilder struct {
+	noParameters
+
 	profileName string
 	clusterName *string
 	inheritFrom []string
@@ -0,0 +1,220 @@
+package querybuilder
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// ColumnDefinition is a column of a table, as written in CREATE TABLE and ALTER TABLE queries.
+type ColumnDefinition struct {
+	Name string
+	Type string
+	// DefaultKind is one of DEFAULT, MATERIALIZED, ALIAS or EPHEMERAL. DEFAULT applies when it is nil.
+	DefaultKind *string
+	Default     *string
+	// Codec is the list of codecs, without the CODEC(...) wrapper, such as `Delta, ZSTD(3)`.
+	Codec   *string
+	Comment *string
+	TTL     *string
+}
+
+// SQLDef renders the column as it appears in a column list.
+func (c ColumnDefinition) SQLDef() (string, error) {
+	if c.Name == "" {
+		return "", errors.New("Name can't be empty")
+	}
+	if c.Type == "" {
+		return "", errors.New(fmt.Sprintf("Type of column %q can't be empty", c.Name))
+	}
+
+	tokens := []string{backtick(c.Name), c.Type}
+	if c.DefaultKind != nil || c.Default != nil {
+		kind := "DEFAULT"
+		if c.DefaultKind != nil {
+			kind = *c.DefaultKind
+		}
+		tokens = append(tokens, kind)
+		if c.Default != nil {
+			tokens = append(tokens, *c.Default)
+		}
+	}
+	if c.Comment != nil {
+		tokens = append(tokens, "COMMENT", quote(*c.Comment))
+	}
+	if c.Codec != nil {
+		tokens = append(tokens, fmt.Sprintf("CODEC(%s)", *c.Codec))
+	}
+	if c.TTL != nil {
+		tokens = append(tokens, "TTL", *c.TTL)
+	}
+
+	return strings.Join(tokens, " "), nil
+}
+
+// CreateTableQueryBuilder is an interface to build CREATE TABLE SQL queries.
+type CreateTableQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) CreateTableQueryBuilder
+	WithColumns(columns []ColumnDefinition) CreateTableQueryBuilder
+	WithEngine(engine string) CreateTableQueryBuilder
+	WithOrderBy(expression *string) CreateTableQueryBuilder
+	WithPartitionBy(expression *string) CreateTableQueryBuilder
+	WithPrimaryKey(expression *string) CreateTableQueryBuilder
+	WithSampleBy(expression *string) CreateTableQueryBuilder
+	WithTTL(expression *string) CreateTableQueryBuilder
+	WithSettings(settings map[string]string) CreateTableQueryBuilder
+	WithComment(comment *string) CreateTableQueryBuilder
+}
+
+type createTableQueryBuilder struct {
+	boundParameters
+
+	databaseName string
+	tableName    string
+	clusterName  *string
+	columns      []ColumnDefinition
+	engine       string
+	orderBy      *string
+	partitionBy  *string
+	primaryKey   *string
+	sampleBy     *string
+	ttl          *string
+	settings     map[string]string
+	comment      *string
+}
+
+func NewCreateTable(databaseName string, tableName string) CreateTableQueryBuilder {
+	return &createTableQueryBuilder{
+		databaseName: databaseName,
+		tableName:    tableName,
+	}
+}
+
+func (q *createTableQueryBuilder) WithCluster(clusterName *string) CreateTableQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *createTableQueryBuilder) WithColumns(columns []ColumnDefinition) CreateTableQueryBuilder {
+	q.columns = columns
+	return q
+}
+
+func (q *createTableQueryBuilder) WithEngine(engine string) CreateTableQueryBuilder {
+	q.engine = engine
+	return q
+}
+
+func (q *createTableQueryBuilder) WithOrderBy(expression *string) CreateTableQueryBuilder {
+	q.orderBy = expression
+	return q
+}
+
+func (q *createTableQueryBuilder) WithPartitionBy(expression *string) CreateTableQueryBuilder {
+	q.partitionBy = expression
+	return q
+}
+
+func (q *createTableQueryBuilder) WithPrimaryKey(expression *string) CreateTableQueryBuilder {
+	q.primaryKey = expression
+	return q
+}
+
+func (q *createTableQueryBuilder) WithSampleBy(expression *string) CreateTableQueryBuilder {
+	q.sampleBy = expression
+	return q
+}
+
+func (q *createTableQueryBuilder) WithTTL(expression *string) CreateTableQueryBuilder {
+	q.ttl = expression
+	return q
+}
+
+func (q *createTableQueryBuilder) WithSettings(settings map[string]string) CreateTableQueryBuilder {
+	q.settings = settings
+	return q
+}
+
+func (q *createTableQueryBuilder) WithComment(comment *string) CreateTableQueryBuilder {
+	q.comment = comment
+	return q
+}
+
+func (q *createTableQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for CREATE TABLE queries")
+	}
+	if q.tableName == "" {
+		return "", errors.New("tableName cannot be empty for CREATE TABLE queries")
+	}
+	if len(q.columns) == 0 {
+		return "", errors.New("at least one column is required for CREATE TABLE queries")
+	}
+	if q.engine == "" {
+		return "", errors.New("engine cannot be empty for CREATE TABLE queries")
+	}
+
+	params := newParameters()
+
+	columns := make([]string, 0, len(q.columns))
+	for _, c := range q.columns {
+		def, err := c.SQLDef()
+		if err != nil {
+			return "", errors.WithMessage(err, "invalid column")
+		}
+		columns = append(columns, def)
+	}
+
+	tokens := []string{
+		"CREATE",
+		"TABLE",
+		tableIdentifier(params, q.databaseName, q.tableName),
+	}
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	tokens = append(tokens, "("+strings.Join(columns, ", ")+")", "ENGINE", "=", q.engine)
+	for _, clause := range []struct {
+		keyword    string
+		expression *string
+	}{
+		{"ORDER BY", q.orderBy},
+		{"PARTITION BY", q.partitionBy},
+		{"PRIMARY KEY", q.primaryKey},
+		{"SAMPLE BY", q.sampleBy},
+		{"TTL", q.ttl},
+	} {
+		if clause.expression != nil {
+			tokens = append(tokens, clause.keyword, *clause.expression)
+		}
+	}
+	if len(q.settings) > 0 {
+		tokens = append(tokens, "SETTINGS", settingAssignments(q.settings))
+	}
+	if q.comment != nil {
+		tokens = append(tokens, "COMMENT", quote(*q.comment))
+	}
+
+	q.params = params.values
+
+	return strings.Join(tokens, " ") + ";", nil
+}
+
+// tableIdentifier binds the database and name of a table.
+func tableIdentifier(params *parameters, databaseName string, tableName string) string {
+	return params.identifier(databaseName) + "." + params.identifier(tableName)
+}
+
+// settingAssignments renders settings as `name = 'value'` pairs, sorted by name.
+func settingAssignments(settings map[string]string) string {
+	assignments := make([]string, 0, len(settings))
+	for _, name := range slices.Sorted(maps.Keys(settings)) {
+		assignments = append(assignments, fmt.Sprintf("%s = %s", backtick(name), quote(settings[name])))
+	}
+
+	return strings.Join(assignments, ", ")
+}
@@ -0,0 +1,91 @@
+package querybuilder
+
+import (
+	"maps"
+	"testing"
+)
+
+func Test_createtable(t *testing.T) {
+	tests := []struct {
+		name       string
+		builder    CreateTableQueryBuilder
+		want       string
+		wantParams map[string]string
+		wantErr    bool
+	}{
+		{
+			name: "MergeTree table",
+			builder: NewCreateTable("db", "events").
+				WithColumns([]ColumnDefinition{
+					{Name: "id", Type: "UInt64"},
+					{Name: "ts", Type: "DateTime", Default: new("now()"), Codec: new("Delta, ZSTD(3)")},
+					{Name: "day", Type: "Date", DefaultKind: new("MATERIALIZED"), Default: new("toDate(ts)"), Comment: new("day's partition")},
+					{Name: "payload", Type: "String", TTL: new("ts + INTERVAL 1 DAY")},
+				}).
+				WithEngine("MergeTree").
+				WithOrderBy(new("(id, ts)")).
+				WithPartitionBy(new("toYYYYMM(ts)")).
+				WithPrimaryKey(new("id")).
+				WithSampleBy(new("id")).
+				WithTTL(new("ts + INTERVAL 1 MONTH")).
+				WithSettings(map[string]string{"ttl_only_drop_parts": "1", "index_granularity": "8192"}).
+				WithComment(new("raw events")),
+			want: "CREATE TABLE {identifier_0:Identifier}.{identifier_1:Identifier} (" +
+				"`id` UInt64, " +
+				"`ts` DateTime DEFAULT now() CODEC(Delta, ZSTD(3)), " +
+				"`day` Date MATERIALIZED toDate(ts) COMMENT 'day\\'s partition', " +
+				"`payload` String TTL ts + INTERVAL 1 DAY" +
+				") ENGINE = MergeTree ORDER BY (id, ts) PARTITION BY toYYYYMM(ts) PRIMARY KEY id SAMPLE BY id TTL ts + INTERVAL 1 MONTH " +
+				"SETTINGS `index_granularity` = '8192', `ttl_only_drop_parts` = '1' COMMENT 'raw events';",
+			wantParams: map[string]string{"identifier_0": "db", "identifier_1": "events"},
+		},
+		{
+			name: "Table on cluster",
+			builder: NewCreateTable("db", "events").
+				WithCluster(new("cluster1")).
+				WithColumns([]ColumnDefinition{{Name: "id", Type: "UInt64"}}).
+				WithEngine("ReplicatedMergeTree('/clickhouse/tables/{shard}/db/events', '{replica}')").
+				WithOrderBy(new("id")),
+			want:       "CREATE TABLE {identifier_0:Identifier}.{identifier_1:Identifier} ON CLUSTER 'cluster1' (`id` UInt64) ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/db/events', '{replica}') ORDER BY id;",
+			wantParams: map[string]string{"identifier_0": "db", "identifier_1": "events"},
+		},
+		{
+			name: "Ephemeral column without expression",
+			builder: NewCreateTable("db", "t").
+				WithColumns([]ColumnDefinition{{Name: "raw", Type: "String", DefaultKind: new("EPHEMERAL")}}).
+				WithEngine("Null"),
+			want:       "CREATE TABLE {identifier_0:Identifier}.{identifier_1:Identifier} (`raw` String EPHEMERAL) ENGINE = Null;",
+			wantParams: map[string]string{"identifier_0": "db", "identifier_1": "t"},
+		},
+		{
+			name:    "Fail without columns",
+			builder: NewCreateTable("db", "t").WithEngine("Null"),
+			wantErr: true,
+		},
+		{
+			name:    "Fail without engine",
+			builder: NewCreateTable("db", "t").WithColumns([]ColumnDefinition{{Name: "id", Type: "UInt64"}}),
+			wantErr: true,
+		},
+		{
+			name:    "Fail with untyped column",
+			builder: NewCreateTable("db", "t").WithColumns([]ColumnDefinition{{Name: "id"}}).WithEngine("Null"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+			if !maps.Equal(tt.builder.Parameters(), tt.wantParams) {
+				t.Errorf("Parameters() got = %v, want %v", tt.builder.Parameters(), tt.wantParams)
+			}
+		})
+	}
+}
@@ -6,20 +6,20 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// CreateUserQueryBuilder is an interface to build CREATE USER SQL queries (already interpolated).
+// CreateUserQueryBuilder is an interface to build CREATE USER SQL queries.
 type CreateUserQueryBuilder interface {
 	QueryBuilder
 	Identified(methods []AuthMethod) CreateUserQueryBuilder
 	WithSettingsProfile(profileName *string) CreateUserQueryBuilder
 	WithCluster(clusterName *string) CreateUserQueryBuilder
 	HostIPs(ips []string) CreateUserQueryBuilder
-	Parameters() map[string]string
 }
 
 type createUserQueryBuilder struct {
+	boundParameters
+
 	resourceName    string
-	identified      string
-	params          map[string]string
+	authMethods     []AuthMethod
 	hostIPs         []string
 	settingsProfile *string
 	clusterName     *string
@@ -32,14 +32,10 @@ func NewCreateUser(resourceName string) CreateUserQueryBuilder {
 }
 
 func (q *createUserQueryBuilder) Identified(methods []AuthMethod) CreateUserQueryBuilder {
-	q.identified, q.params = identifiedClause(methods)
+	q.authMethods = methods
 	return q
 }
 
-func (q *createUserQueryBuilder) Parameters() map[string]string {
-	return q.params
-}
-
 func (q *createUserQueryBuilder) HostIPs(ips []string) CreateUserQueryBuilder {
 	q.hostIPs = ips
 	return q
@@ -73,12 +69,15 @@ func (q *createUserQueryBuilder) Build() (string, error) {
 			tokens = append(tokens, "HOST", "IP", quote(ip))
 		}
 	}
-	if q.identified != "" {
-		tokens = append(tokens, q.identified)
+	params := newParameters()
+	if identified := identifiedClause(q.authMethods, params); identified != "" {
+		tokens = append(tokens, identified)
 	}
 	if q.settingsProfile != nil {
 		tokens = append(tokens, "SETTINGS", "PROFILE", quote(*q.settingsProfile))
 	}
 
+	q.params = params.values
+
 	return strings.Join(tokens, " ") + ";", nil
 }
@@ -0,0 +1,179 @@
+package querybuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// SQLSecurity holds the DEFINER and SQL SECURITY clauses of a view.
+type SQLSecurity struct {
+	// Definer is the name of a user, or CURRENT_USER.
+	Definer *string
+	// Type is one of DEFINER, INVOKER or NONE.
+	Type *string
+}
+
+// SQLDef renders the clauses, or an empty string when none is set.
+func (s SQLSecurity) SQLDef() string {
+	tokens := make([]string, 0)
+	if s.Definer != nil {
+		definer := *s.Definer
+		if definer != "CURRENT_USER" {
+			definer = backtick(definer)
+		}
+		tokens = append(tokens, "DEFINER", "=", definer)
+	}
+	if s.Type != nil {
+		tokens = append(tokens, "SQL", "SECURITY", *s.Type)
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// RefreshSchedule is the REFRESH clause of a refreshable materialized view.
+type RefreshSchedule struct {
+	// Kind is EVERY or AFTER.
+	Kind string
+	// Interval is the refresh period, such as `1 HOUR`.
+	Interval     string
+	Offset       *string
+	RandomizeFor *string
+	// DependsOn is the list of refreshable materialized views, as `name` or `database.name`, to refresh after.
+	DependsOn []string
+	Append    bool
+}
+
+// SQLDef renders the REFRESH clause.
+func (r RefreshSchedule) SQLDef() (string, error) {
+	if r.Kind != "EVERY" && r.Kind != "AFTER" {
+		return "", errors.New(fmt.Sprintf("refresh kind must be EVERY or AFTER, got %q", r.Kind))
+	}
+	if r.Interval == "" {
+		return "", errors.New("refresh interval can't be empty")
+	}
+
+	tokens := []string{"REFRESH", r.Kind, r.Interval}
+	if r.Offset != nil {
+		tokens = append(tokens, "OFFSET", *r.Offset)
+	}
+	if r.RandomizeFor != nil {
+		tokens = append(tokens, "RANDOMIZE", "FOR", *r.RandomizeFor)
+	}
+	if len(r.DependsOn) > 0 {
+		dependencies := make([]string, 0, len(r.DependsOn))
+		for _, d := range r.DependsOn {
+			dependencies = append(dependencies, qualifiedName(d))
+		}
+		tokens = append(tokens, "DEPENDS", "ON", strings.Join(dependencies, ", "))
+	}
+	if r.Append {
+		tokens = append(tokens, "APPEND")
+	}
+
+	return strings.Join(tokens, " "), nil
+}
+
+// CreateViewQueryBuilder is an interface to build CREATE VIEW SQL queries.
+type CreateViewQueryBuilder interface {
+	QueryBuilder
+	WithCluster(clusterName *string) CreateViewQueryBuilder
+	// WithOrReplace replaces the view atomically if it exists.
+	WithOrReplace(orReplace bool) CreateViewQueryBuilder
+	WithSQLSecurity(security SQLSecurity) CreateViewQueryBuilder
+	WithQuery(query string) CreateViewQueryBuilder
+	WithComment(comment *string) CreateViewQueryBuilder
+}
+
+type createViewQueryBuilder struct {
+	boundParameters
+
+	databaseName string
+	viewName     string
+	clusterName  *string
+	orReplace    bool
+	security     SQLSecurity
+	query        string
+	comment      *string
+}
+
+func NewCreateView(databaseName string, viewName string) CreateViewQueryBuilder {
+	return &createViewQueryBuilder{
+		databaseName: databaseName,
+		viewName:     viewName,
+	}
+}
+
+func (q *createViewQueryBuilder) WithCluster(clusterName *string) CreateViewQueryBuilder {
+	q.clusterName = clusterName
+	return q
+}
+
+func (q *createViewQueryBuilder) WithOrReplace(orReplace bool) CreateViewQueryBuilder {
+	q.orReplace = orReplace
+	return q
+}
+
+func (q *createViewQueryBuilder) WithSQLSecurity(security SQLSecurity) CreateViewQueryBuilder {
+	q.security = security
+	return q
+}
+
+func (q *createViewQueryBuilder) WithQuery(query string) CreateViewQueryBuilder {
+	q.query = query
+	return q
+}
+
+func (q *createViewQueryBuilder) WithComment(comment *string) CreateViewQueryBuilder {
+	q.comment = comment
+	return q
+}
+
+func (q *createViewQueryBuilder) Build() (string, error) {
+	if q.databaseName == "" {
+		return "", errors.New("databaseName cannot be empty for CREATE VIEW queries")
+	}
+	if q.viewName == "" {
+		return "", errors.New("viewName cannot be empty for CREATE VIEW queries")
+	}
+	query := trimQuery(q.query)
+	if query == "" {
+		return "", errors.New("query cannot be empty for CREATE VIEW queries")
+	}
+
+	params := newParameters()
+
+	tokens := []string{"CREATE"}
+	if q.orReplace {
+		tokens = append(tokens, "OR", "REPLACE")
+	}
+	tokens = append(tokens, "VIEW", tableIdentifier(params, q.databaseName, q.viewName))
+	if q.clusterName != nil {
+		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
+	}
+	if security := q.security.SQLDef(); security != "" {
+		tokens = append(tokens, security)
+	}
+	tokens = append(tokens, "AS", query)
+	if q.comment != nil {
+		tokens = append(tokens, "COMMENT", quote(*q.comment))
+	}
+
+	q.params = params.values
+
+	return strings.Join(tokens, " ") + ";", nil
+}
+
+// qualifiedName escapes a `name` or `database.name` table reference.
+func qualifiedName(name string) string {
+	if database, table, ok := strings.Cut(name, "."); ok {
+		return backtick(database) + "." + backtick(table)
+	}
+	return backtick(name)
+}
+
+// trimQuery removes the spaces and semicolons around a query embedded in another one.
+func trimQuery(query string) string {
+	return strings.Trim(query, " \t\r\n;")
+}
@@ -0,0 +1,65 @@
+package querybuilder
+
+import (
+	"maps"
+	"testing"
+)
+
+func Test_createview(t *testing.T) {
+	tests := []struct {
+		name       string
+		builder    CreateViewQueryBuilder
+		want       string
+		wantParams map[string]string
+		wantErr    bool
+	}{
+		{
+			name:       "Simple view",
+			builder:    NewCreateView("db", "v").WithQuery("SELECT * FROM db.t;\n"),
+			want:       "CREATE VIEW {identifier_0:Identifier}.{identifier_1:Identifier} AS SELECT * FROM db.t;",
+			wantParams: map[string]string{"identifier_0": "db", "identifier_1": "v"},
+		},
+		{
+			name: "Replace view with definer on cluster",
+			builder: NewCreateView("db", "v").
+				WithCluster(new("cluster1")).
+				WithOrReplace(true).
+				WithSQLSecurity(SQLSecurity{Definer: new("report`er"), Type: new("DEFINER")}).
+				WithQuery("SELECT id FROM db.t").
+				WithComment(new("tenant's view")),
+			want:       "CREATE OR REPLACE VIEW {identifier_0:Identifier}.{identifier_1:Identifier} ON CLUSTER 'cluster1' DEFINER = `report\\`er` SQL SECURITY DEFINER AS SELECT id FROM db.t COMMENT 'tenant\\'s view';",
+			wantParams: map[string]string{"identifier_0": "db", "identifier_1": "v"},
+		},
+		{
+			name:       "Invoker view",
+			builder:    NewCreateView("db", "v").WithSQLSecurity(SQLSecurity{Type: new("INVOKER")}).WithQuery("SELECT 1"),
+			want:       "CREATE VIEW {identifier_0:Identifier}.{identifier_1:Identifier} SQL SECURITY INVOKER AS SELECT 1;",
+			wantParams: map[string]string{"identifier_0": "db", "identifier_1": "v"},
+		},
+		{
+			name:    "Fail without query",
+			builder: NewCreateView("db", "v").WithQuery(" ; "),
+			wantErr: true,
+		},
+		{
+			name:    "Fail without database",
+			builder: NewCreateView("", "v").WithQuery("SELECT 1"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+			if !maps.Equal(tt.builder.Parameters(), tt.wantParams) {
+				t.Errorf("Parameters() got = %v, want %v", tt.builder.Parameters(), tt.wantParams)
+			}
+		})
+	}
+}
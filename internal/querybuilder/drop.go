@@ -11,6 +11,9 @@ const (
 	resourceTypeRole            = "ROLE"
 	resourceTypeUser            = "USER"
 	resourceTypeSettingsProfile = "SETTINGS PROFILE"
+	resourceTypeTable           = "TABLE"
+	resourceTypeView            = "VIEW"
+	resourceTypeDictionary      = "DICTIONARY"
 )
 
 type DropQueryBuilder interface {
@@ -19,9 +22,13 @@ type DropQueryBuilder interface {
 }
 
 type dropQueryBuilder struct {
+	boundParameters
+
 	resourceTypeName string
-	resourceName     string
-	clusterName      *string
+	// databaseName is the database of a table, view or dictionary.
+	databaseName string
+	resourceName string
+	clusterName  *string
 }
 
 func NewDropRole(resourceName string) DropQueryBuilder {
@@ -32,6 +39,33 @@ func NewDropDatabase(resourceName string) DropQueryBuilder {
 	return newDrop(resourceTypeDatabase, resourceName)
 }
 
+// NewDropTable drops a table synchronously, so that a table replacing it can reuse its replication path.
+func NewDropTable(databaseName string, tableName string) DropQueryBuilder {
+	return &dropQueryBuilder{
+		resourceTypeName: resourceTypeTable,
+		databaseName:     databaseName,
+		resourceName:     tableName,
+	}
+}
+
+// NewDropView drops a view or a materialized view synchronously.
+func NewDropView(databaseName string, viewName string) DropQueryBuilder {
+	return &dropQueryBuilder{
+		resourceTypeName: resourceTypeView,
+		databaseName:     databaseName,
+		resourceName:     viewName,
+	}
+}
+
+// NewDropDictionary drops a dictionary synchronously.
+func NewDropDictionary(databaseName string, dictionaryName string) DropQueryBuilder {
+	return &dropQueryBuilder{
+		resourceTypeName: resourceTypeDictionary,
+		databaseName:     databaseName,
+		resourceName:     dictionaryName,
+	}
+}
+
 func NewDropUser(resourceName string) DropQueryBuilder {
 	return newDrop(resourceTypeUser, resourceName)
 }
@@ -57,15 +91,35 @@ func (q *dropQueryBuilder) Build() (string, error) {
 		return "", errors.New("resourceName cannot be empty for CREATE and DROP queries")
 	}
 
+	params := newParameters()
+
+	// Only database names can be bound, access entity names are plain tokens in the grammar.
+	name := backtick(q.resourceName)
+	switch q.resourceTypeName {
+	case resourceTypeDatabase:
+		name = params.identifier(q.resourceName)
+	case resourceTypeTable, resourceTypeView, resourceTypeDictionary:
+		if q.databaseName == "" {
+			return "", errors.New("databaseName cannot be empty for DROP " + q.resourceTypeName + " queries")
+		}
+		name = tableIdentifier(params, q.databaseName, q.resourceName)
+	}
+
 	tokens := []string{
 		"DROP",
 		q.resourceTypeName,
-		backtick(q.resourceName),
+		name,
 	}
 
 	if q.clusterName != nil {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
 	}
+	switch q.resourceTypeName {
+	case resourceTypeTable, resourceTypeView, resourceTypeDictionary:
+		tokens = append(tokens, "SYNC")
+	}
+
+	q.params = params.values
 
 	return strings.Join(tokens, " ") + ";", nil
 }
@@ -1,6 +1,7 @@
 package querybuilder
 
 import (
+	"maps"
 	"testing"
 )
 
@@ -9,18 +10,21 @@ func Test_drop(t *testing.T) {
 		name         string
 		action       string
 		resourceType string
+		databaseName string
 		resourceName string
 		comment      string
 		identified   string
 		clusterName  *string
 		want         string
+		wantParams   map[string]string
 		wantErr      bool
 	}{
 		{
 			name:         "Drop database",
 			resourceType: resourceTypeDatabase,
 			resourceName: "db1",
-			want:         "DROP DATABASE `db1`;",
+			want:         "DROP DATABASE {identifier_0:Identifier};",
+			wantParams:   map[string]string{"identifier_0": "db1"},
 			wantErr:      false,
 		},
 		{
@@ -28,14 +32,16 @@ func Test_drop(t *testing.T) {
 			resourceType: resourceTypeDatabase,
 			resourceName: "db1",
 			clusterName:  new("cluster1"),
-			want:         "DROP DATABASE `db1` ON CLUSTER 'cluster1';",
+			want:         "DROP DATABASE {identifier_0:Identifier} ON CLUSTER 'cluster1';",
+			wantParams:   map[string]string{"identifier_0": "db1"},
 			wantErr:      false,
 		},
 		{
 			name:         "Drop database with complex name",
 			resourceType: resourceTypeDatabase,
 			resourceName: "data`base",
-			want:         "DROP DATABASE `data\\`base`;",
+			want:         "DROP DATABASE {identifier_0:Identifier};",
+			wantParams:   map[string]string{"identifier_0": "data`base"},
 			wantErr:      false,
 		},
 		{
@@ -87,11 +93,48 @@ func Test_drop(t *testing.T) {
 			want:         "",
 			wantErr:      true,
 		},
+		{
+			name:         "Drop table on cluster",
+			resourceType: resourceTypeTable,
+			databaseName: "db1",
+			resourceName: "events",
+			clusterName:  new("cluster1"),
+			want:         "DROP TABLE {identifier_0:Identifier}.{identifier_1:Identifier} ON CLUSTER 'cluster1' SYNC;",
+			wantParams:   map[string]string{"identifier_0": "db1", "identifier_1": "events"},
+			wantErr:      false,
+		},
+		{
+			name:         "Drop view",
+			resourceType: resourceTypeView,
+			databaseName: "db1",
+			resourceName: "events_mv",
+			want:         "DROP VIEW {identifier_0:Identifier}.{identifier_1:Identifier} SYNC;",
+			wantParams:   map[string]string{"identifier_0": "db1", "identifier_1": "events_mv"},
+			wantErr:      false,
+		},
+		{
+			name:         "Drop dictionary on cluster",
+			resourceType: resourceTypeDictionary,
+			databaseName: "db1",
+			resourceName: "countries",
+			clusterName:  new("cluster1"),
+			want:         "DROP DICTIONARY {identifier_0:Identifier}.{identifier_1:Identifier} ON CLUSTER 'cluster1' SYNC;",
+			wantParams:   map[string]string{"identifier_0": "db1", "identifier_1": "countries"},
+			wantErr:      false,
+		},
+		{
+			name:         "Fail to drop table without database",
+			resourceType: resourceTypeTable,
+			resourceName: "events",
+			want:         "",
+			wantErr:      true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			q := dropQueryBuilder{
 				resourceTypeName: tt.resourceType,
+				databaseName:     tt.databaseName,
 				resourceName:     tt.resourceName,
 				clusterName:      tt.clusterName,
 			}
@@ -104,6 +147,9 @@ func Test_drop(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("Build() got = %v, want %v", got, tt.want)
 			}
+			if !maps.Equal(q.Parameters(), tt.wantParams) {
+				t.Errorf("Parameters() got = %v, want %v", q.Parameters(), tt.wantParams)
+			}
 		})
 	}
 }
@@ -7,28 +7,35 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// GrantPrivilegeQueryBuilder is an interface to build GRANT SQL queries (already interpolated).
+// GrantPrivilegeQueryBuilder is an interface to build GRANT SQL queries.
 type GrantPrivilegeQueryBuilder interface {
 	QueryBuilder
 	WithDatabase(*string) GrantPrivilegeQueryBuilder
 	WithTable(*string) GrantPrivilegeQueryBuilder
 	WithColumn(*string) GrantPrivilegeQueryBuilder
 	WithAccessObject(*string) GrantPrivilegeQueryBuilder
+	WithNamedCollection(*string) GrantPrivilegeQueryBuilder
+	WithTableEngine(*string) GrantPrivilegeQueryBuilder
 	WithGrantOption(bool) GrantPrivilegeQueryBuilder
 	WithCluster(*string) GrantPrivilegeQueryBuilder
 	WithCurrentGrants(bool) GrantPrivilegeQueryBuilder
 }
 
 type grantPrivilegeQueryBuilder struct {
-	accessType    string
-	to            string
-	database      *string
-	table         *string
-	column        *string
-	accessObject  *string
-	grantOption   bool
-	clusterName   *string
-	currentGrants bool
+	noParameters
+
+	accessType   string
+	to           string
+	database     *string
+	table        *string
+	column       *string
+	accessObject *string
+	// namedCollection and tableEngine are the targets of NAMED_COLLECTION and TABLE_ENGINE scoped privileges.
+	namedCollection *string
+	tableEngine     *string
+	grantOption     bool
+	clusterName     *string
+	currentGrants   bool
 }
 
 func GrantPrivilege(accessType string, to string) GrantPrivilegeQueryBuilder {
@@ -58,6 +65,16 @@ func (q *grantPrivilegeQueryBuilder) WithAccessObject(accessObject *string) Gran
 	return q
 }
 
+func (q *grantPrivilegeQueryBuilder) WithNamedCollection(namedCollection *string) GrantPrivilegeQueryBuilder {
+	q.namedCollection = namedCollection
+	return q
+}
+
+func (q *grantPrivilegeQueryBuilder) WithTableEngine(tableEngine *string) GrantPrivilegeQueryBuilder {
+	q.tableEngine = tableEngine
+	return q
+}
+
 func (q *grantPrivilegeQueryBuilder) WithCluster(clusterName *string) GrantPrivilegeQueryBuilder {
 	q.clusterName = clusterName
 	return q
@@ -102,6 +119,10 @@ func (q *grantPrivilegeQueryBuilder) Build() (string, error) {
 	switch {
 	case q.accessObject != nil:
 		target = identifierOrPattern(*q.accessObject)
+	case q.namedCollection != nil:
+		target = identifierOrPattern(*q.namedCollection)
+	case q.tableEngine != nil:
+		target = identifierOrPattern(*q.tableEngine)
 	case q.database != nil && q.table != nil:
 		target = fmt.Sprintf("%s.%s", identifierOrPattern(*q.database), identifierOrPattern(*q.table))
 	case q.database != nil:
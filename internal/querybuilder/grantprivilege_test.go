@@ -107,6 +107,18 @@ func Test_grantPrivilegeQueryBuilder(t *testing.T) {
 			want:    "GRANT CREATE USER ON team_* TO `admin`;",
 			wantErr: false,
 		},
+		{
+			name:    "Named collection",
+			builder: GrantPrivilege("NAMED COLLECTION", "r").WithNamedCollection(new("my_s3")),
+			want:    "GRANT NAMED COLLECTION ON `my_s3` TO `r`;",
+			wantErr: false,
+		},
+		{
+			name:    "Table engine",
+			builder: GrantPrivilege("TABLE ENGINE", "r").WithTableEngine(new("S3")),
+			want:    "GRANT TABLE ENGINE ON `S3` TO `r`;",
+			wantErr: false,
+		},
 		{
 			name:    "Missing access type",
 			builder: GrantPrivilege("", "user1"),
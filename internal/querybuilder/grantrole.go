@@ -6,7 +6,7 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// GrantRoleQueryBuilder is an interface to build GRANT SQL queries (already interpolated).
+// GrantRoleQueryBuilder is an interface to build GRANT SQL queries.
 type GrantRoleQueryBuilder interface {
 	QueryBuilder
 	WithAdminOption(bool) GrantRoleQueryBuilder
@@ -14,6 +14,8 @@ type GrantRoleQueryBuilder interface {
 }
 
 type grantQueryBuilder struct {
+	noParameters
+
 	roleName    string
 	to          string
 	adminOption bool
@@ -1,6 +1,9 @@
 package querybuilder
 
-// QueryBuilder is an interface meant to build SQL queries (already interpolated) with pluggable options.
+// QueryBuilder is an interface meant to build SQL queries with pluggable options.
 type QueryBuilder interface {
 	Build() (string, error)
+	// Parameters returns the values bound by Build as server-side query parameters. They must be
+	// passed to the server along with the query.
+	Parameters() map[string]string
 }
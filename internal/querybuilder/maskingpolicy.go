@@ -28,6 +28,8 @@ type CreateMaskingPolicyQueryBuilder interface {
 }
 
 type createMaskingPolicyQueryBuilder struct {
+	noParameters
+
 	name             string
 	database         string
 	table            string
@@ -119,6 +121,8 @@ type DropMaskingPolicyQueryBuilder interface {
 }
 
 type dropMaskingPolicyQueryBuilder struct {
+	noParameters
+
 	name     string
 	database string
 	table    string
@@ -0,0 +1,68 @@
+package querybuilder
+
+import (
+	"fmt"
+)
+
+// parameters collects the values bound as server-side query parameters while a query is built.
+// Bound values reach ClickHouse separately from the query text, so they never need escaping.
+//
+// ClickHouse only accepts parameters where its grammar expects an expression or a database/table
+// name. Access entity names (users, roles, profiles, policies), GRANT targets, setting values,
+// dictionary source and layout parameters and ON CLUSTER clauses are plain tokens in the grammar
+// and are still escaped with backtick and quote.
+type parameters struct {
+	values map[string]string
+	counts map[string]int
+}
+
+func newParameters() *parameters {
+	return &parameters{
+		counts: make(map[string]int),
+	}
+}
+
+// bind stores value and returns the placeholder referencing it in the query.
+func (p *parameters) bind(prefix string, chType string, value string) string {
+	if p.values == nil {
+		p.values = make(map[string]string)
+	}
+
+	name := fmt.Sprintf("%s_%d", prefix, p.counts[prefix])
+	p.counts[prefix]++
+	p.values[name] = value
+
+	return fmt.Sprintf("{%s:%s}", name, chType)
+}
+
+// identifier binds the name of a database or table.
+func (p *parameters) identifier(s string) string {
+	return p.bind("identifier", "Identifier", s)
+}
+
+// literal binds a String value.
+func (p *parameters) literal(s string) string {
+	return p.bind("value", "String", s)
+}
+
+// secret binds a String value that must not appear in the query text, such as a password.
+func (p *parameters) secret(s string) string {
+	return p.bind("secret", "String", s)
+}
+
+// boundParameters implements QueryBuilder.Parameters for builders binding parameters in Build.
+type boundParameters struct {
+	params map[string]string
+}
+
+func (b *boundParameters) Parameters() map[string]string {
+	return b.params
+}
+
+// noParameters implements QueryBuilder.Parameters for builders escaping every value, as the grammar
+// of their statements does not accept parameters.
+type noParameters struct{}
+
+func (noParameters) Parameters() map[string]string {
+	return nil
+}
@@ -7,24 +7,34 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// RevokePrivilegeQueryBuilder is an interface to build REVOKE SQL queries (already interpolated).
+// RevokePrivilegeQueryBuilder is an interface to build REVOKE SQL queries.
 type RevokePrivilegeQueryBuilder interface {
 	QueryBuilder
 	WithDatabase(*string) RevokePrivilegeQueryBuilder
 	WithTable(*string) RevokePrivilegeQueryBuilder
 	WithColumn(*string) RevokePrivilegeQueryBuilder
 	WithAccessObject(*string) RevokePrivilegeQueryBuilder
+	WithNamedCollection(*string) RevokePrivilegeQueryBuilder
+	WithTableEngine(*string) RevokePrivilegeQueryBuilder
 	WithCluster(*string) RevokePrivilegeQueryBuilder
+	GrantOptionOnly() RevokePrivilegeQueryBuilder
 }
 
 type revokePrivilegeQueryBuilder struct {
+	noParameters
+
 	accessType   string
 	from         string
 	database     *string
 	table        *string
 	column       *string
 	accessObject *string
-	clusterName  *string
+	// namedCollection and tableEngine are the targets of NAMED_COLLECTION and TABLE_ENGINE scoped privileges.
+	namedCollection *string
+	tableEngine     *string
+	clusterName     *string
+	// grantOptionOnly revokes the grant option, leaving the privilege itself granted.
+	grantOptionOnly bool
 }
 
 func RevokePrivilege(accessType string, from string) RevokePrivilegeQueryBuilder {
@@ -54,11 +64,26 @@ func (q *revokePrivilegeQueryBuilder) WithAccessObject(accessObject *string) Rev
 	return q
 }
 
+func (q *revokePrivilegeQueryBuilder) WithNamedCollection(namedCollection *string) RevokePrivilegeQueryBuilder {
+	q.namedCollection = namedCollection
+	return q
+}
+
+func (q *revokePrivilegeQueryBuilder) WithTableEngine(tableEngine *string) RevokePrivilegeQueryBuilder {
+	q.tableEngine = tableEngine
+	return q
+}
+
 func (q *revokePrivilegeQueryBuilder) WithCluster(clusterName *string) RevokePrivilegeQueryBuilder {
 	q.clusterName = clusterName
 	return q
 }
 
+func (q *revokePrivilegeQueryBuilder) GrantOptionOnly() RevokePrivilegeQueryBuilder {
+	q.grantOptionOnly = true
+	return q
+}
+
 func (q *revokePrivilegeQueryBuilder) Build() (string, error) {
 	if q.accessType == "" {
 		return "", errors.New("AccessType cannot be empty")
@@ -75,6 +100,10 @@ func (q *revokePrivilegeQueryBuilder) Build() (string, error) {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
 	}
 
+	if q.grantOptionOnly {
+		tokens = append(tokens, "GRANT", "OPTION", "FOR")
+	}
+
 	// Privilege
 	if q.column != nil && *q.column != "" {
 		tokens = append(tokens, fmt.Sprintf("%s(%s)", q.accessType, backtick(*q.column)))
@@ -89,6 +118,10 @@ func (q *revokePrivilegeQueryBuilder) Build() (string, error) {
 		switch {
 		case q.accessObject != nil:
 			tokens = append(tokens, identifierOrPattern(*q.accessObject))
+		case q.namedCollection != nil:
+			tokens = append(tokens, identifierOrPattern(*q.namedCollection))
+		case q.tableEngine != nil:
+			tokens = append(tokens, identifierOrPattern(*q.tableEngine))
 		case q.database != nil && q.table != nil:
 			tokens = append(tokens, fmt.Sprintf("%s.%s", identifierOrPattern(*q.database), identifierOrPattern(*q.table)))
 		case q.database != nil:
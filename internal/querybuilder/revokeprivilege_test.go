@@ -53,6 +53,24 @@ func Test_revokePrivilegeQueryBuilder(t *testing.T) {
 			want:    "REVOKE CREATE USER ON `bob` FROM `admin`;",
 			wantErr: false,
 		},
+		{
+			name:    "Grant option on table",
+			builder: RevokePrivilege("SELECT", "user1").WithDatabase(new("db1")).WithTable(new("tbl1")).GrantOptionOnly(),
+			want:    "REVOKE GRANT OPTION FOR SELECT ON `db1`.`tbl1` FROM `user1`;",
+			wantErr: false,
+		},
+		{
+			name:    "Grant option on cluster",
+			builder: RevokePrivilege("SELECT", "user1").WithCluster(new("cluster1")).GrantOptionOnly(),
+			want:    "REVOKE ON CLUSTER 'cluster1' GRANT OPTION FOR SELECT ON *.* FROM `user1`;",
+			wantErr: false,
+		},
+		{
+			name:    "Named collection",
+			builder: RevokePrivilege("NAMED COLLECTION", "r").WithNamedCollection(new("my_s3")),
+			want:    "REVOKE NAMED COLLECTION ON `my_s3` FROM `r`;",
+			wantErr: false,
+		},
 		{
 			name:    "Missing access type",
 			builder: RevokePrivilege("", "user1"),
@@ -6,16 +6,21 @@ import (
 	"github.com/pingcap/errors"
 )
 
-// RevokeRoleQueryBuilder is an interface to build REVOKE SQL queries (already interpolated).
+// RevokeRoleQueryBuilder is an interface to build REVOKE SQL queries.
 type RevokeRoleQueryBuilder interface {
 	QueryBuilder
 	WithCluster(clusterName *string) RevokeRoleQueryBuilder
+	AdminOptionOnly() RevokeRoleQueryBuilder
 }
 
 type revokeRoleQueryBuilder struct {
+	noParameters
+
 	roleName    string
 	from        string
 	clusterName *string
+	// adminOptionOnly revokes the admin option, leaving the role itself granted.
+	adminOptionOnly bool
 }
 
 func RevokeRole(roleName string, from string) RevokeRoleQueryBuilder {
@@ -30,6 +35,11 @@ func (q *revokeRoleQueryBuilder) WithCluster(clusterName *string) RevokeRoleQuer
 	return q
 }
 
+func (q *revokeRoleQueryBuilder) AdminOptionOnly() RevokeRoleQueryBuilder {
+	q.adminOptionOnly = true
+	return q
+}
+
 func (q *revokeRoleQueryBuilder) Build() (string, error) {
 	if q.roleName == "" {
 		return "", errors.New("RoleName cannot be empty")
@@ -45,6 +55,10 @@ func (q *revokeRoleQueryBuilder) Build() (string, error) {
 		tokens = append(tokens, "ON", "CLUSTER", quote(*q.clusterName))
 	}
 
+	if q.adminOptionOnly {
+		tokens = append(tokens, "ADMIN", "OPTION", "FOR")
+	}
+
 	tokens = append(tokens, backtick(q.roleName), "FROM", backtick(q.from))
 
 	return strings.Join(tokens, " ") + ";", nil
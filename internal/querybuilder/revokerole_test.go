@@ -6,11 +6,12 @@ import (
 
 func Test_revokeRoleQueryBuilder_Build(t *testing.T) {
 	tests := []struct {
-		name     string
-		roleName string
-		from     string
-		want     string
-		wantErr  bool
+		name            string
+		roleName        string
+		from            string
+		adminOptionOnly bool
+		want            string
+		wantErr         bool
 	}{
 		{
 			name:     "Simple revoke role",
@@ -19,6 +20,14 @@ func Test_revokeRoleQueryBuilder_Build(t *testing.T) {
 			want:     "REVOKE `test` FROM `user`;",
 			wantErr:  false,
 		},
+		{
+			name:            "Revoke admin option",
+			roleName:        "test",
+			from:            "user",
+			adminOptionOnly: true,
+			want:            "REVOKE ADMIN OPTION FOR `test` FROM `user`;",
+			wantErr:         false,
+		},
 		{
 			name:     "REVOKE role with funky name",
 			roleName: "te`st",
@@ -44,8 +53,9 @@ func Test_revokeRoleQueryBuilder_Build(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			q := &revokeRoleQueryBuilder{
-				roleName: tt.roleName,
-				from:     tt.from,
+				roleName:        tt.roleName,
+				from:            tt.from,
+				adminOptionOnly: tt.adminOptionOnly,
 			}
 			got, err := q.Build()
 			if (err != nil) != tt.wantErr {
@@ -8,6 +8,8 @@ import (
 // CreateRowPolicy builds CREATE ROW POLICY statements. Identifiers go through backtick() so they
 // are escaped consistently with the ALTER ROW POLICY builder.
 type CreateRowPolicy struct {
+	noParameters
+
 	name             string
 	database         string
 	table            string
@@ -106,6 +108,8 @@ func (c *CreateRowPolicy) Build() (string, error) {
 
 // DropRowPolicy builds DROP ROW POLICY statements.
 type DropRowPolicy struct {
+	noParameters
+
 	name        string
 	database    string
 	table       string
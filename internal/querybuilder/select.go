@@ -14,19 +14,24 @@ const (
 	DESC OrderDirection = "DESC"
 )
 
-// SelectQueryBuilder is an interface to build SELECT SQL queries (already interpolated).
+// SelectQueryBuilder is an interface to build SELECT SQL queries.
 type SelectQueryBuilder interface {
 	QueryBuilder
 	Where(...Where) SelectQueryBuilder
 	WithCluster(clusterName *string) SelectQueryBuilder
+	// AllReplicas reads from every replica of the cluster instead of one replica per shard.
+	AllReplicas() SelectQueryBuilder
 	OrderBy(column Field, order OrderDirection) SelectQueryBuilder
 }
 
 type selectQueryBuilder struct {
+	boundParameters
+
 	tableName      string
 	fields         []Field
 	where          Where
 	clusterName    *string
+	allReplicas    bool
 	orderBy        Field
 	orderDirection *OrderDirection
 }
@@ -48,6 +53,11 @@ func (q *selectQueryBuilder) WithCluster(clusterName *string) SelectQueryBuilder
 	return q
 }
 
+func (q *selectQueryBuilder) AllReplicas() SelectQueryBuilder {
+	q.allReplicas = true
+	return q
+}
+
 func (q *selectQueryBuilder) OrderBy(column Field, order OrderDirection) SelectQueryBuilder {
 	q.orderBy = column
 	q.orderDirection = &order
@@ -62,6 +72,8 @@ func (q *selectQueryBuilder) Build() (string, error) {
 		return "", errors.New("at least one with is required for SELECT queries")
 	}
 
+	params := newParameters()
+
 	fields := make([]string, 0)
 	for _, f := range q.fields {
 		fields = append(fields, f.SQLDef())
@@ -76,7 +88,11 @@ func (q *selectQueryBuilder) Build() (string, error) {
 		tableName := strings.Join(tokens, ".")
 
 		if q.clusterName != nil {
-			from = fmt.Sprintf("cluster(%s, %s)", quote(*q.clusterName), tableName)
+			function := "cluster"
+			if q.allReplicas {
+				function = "clusterAllReplicas"
+			}
+			from = fmt.Sprintf("%s(%s, %s)", function, params.literal(*q.clusterName), tableName)
 		} else {
 			from = tableName
 		}
@@ -91,7 +107,7 @@ func (q *selectQueryBuilder) Build() (string, error) {
 
 	// Handle WHERE
 	if q.where != nil {
-		tokens = append(tokens, "WHERE", q.where.Clause())
+		tokens = append(tokens, "WHERE", q.where.clause(params))
 	}
 
 	// ORDER BY
@@ -99,5 +115,7 @@ func (q *selectQueryBuilder) Build() (string, error) {
 		tokens = append(tokens, "ORDER BY", q.orderBy.SQLDef(), string(*q.orderDirection))
 	}
 
+	q.params = params.values
+
 	return strings.Join(tokens, " ") + ";", nil
 }
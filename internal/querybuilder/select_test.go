@@ -1,20 +1,23 @@
 package querybuilder
 
 import (
+	"maps"
 	"testing"
 )
 
 func Test_selectQueryBuilder_Build(t *testing.T) {
 	tests := []struct {
-		name     string
-		fields   []Field
-		where    []Where
-		from     string
-		cluster  string
-		orderCol *Field
-		orderDir *OrderDirection
-		want     string
-		wantErr  bool
+		name       string
+		fields     []Field
+		where      []Where
+		from       string
+		cluster    string
+		all        bool
+		orderCol   *Field
+		orderDir   *OrderDirection
+		want       string
+		wantParams map[string]string
+		wantErr    bool
 	}{
 		{
 			name:    "Select one with",
@@ -24,12 +27,23 @@ func Test_selectQueryBuilder_Build(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "Select With Cluster",
-			fields:  []Field{NewField("name")},
-			from:    "users",
-			cluster: "cluster1",
-			want:    "SELECT `name` FROM cluster('cluster1', `users`);",
-			wantErr: false,
+			name:       "Select With Cluster",
+			fields:     []Field{NewField("name")},
+			from:       "users",
+			cluster:    "cluster1",
+			want:       "SELECT `name` FROM cluster({value_0:String}, `users`);",
+			wantParams: map[string]string{"value_0": "cluster1"},
+			wantErr:    false,
+		},
+		{
+			name:       "Select With Cluster All Replicas",
+			fields:     []Field{NewField("name")},
+			from:       "users",
+			cluster:    "cluster1",
+			all:        true,
+			want:       "SELECT `name` FROM clusterAllReplicas({value_0:String}, `users`);",
+			wantParams: map[string]string{"value_0": "cluster1"},
+			wantErr:    false,
 		},
 		{
 			name:    "Select two fields",
@@ -61,6 +75,15 @@ func Test_selectQueryBuilder_Build(t *testing.T) {
 			want:    "SELECT `name` FROM `users` WHERE (mock_where_clause AND mock_where_clause_2);",
 			wantErr: false,
 		},
+		{
+			name:       "Select with bound values",
+			fields:     []Field{NewField("name")},
+			where:      []Where{WhereEquals("name", "o'brien"), WhereIn("id", []string{"1", "2"})},
+			from:       "users",
+			cluster:    "cluster1",
+			want:       "SELECT `name` FROM cluster({value_0:String}, `users`) WHERE (`name` = {value_1:String} AND `id` IN ({value_2:String}, {value_3:String}));",
+			wantParams: map[string]string{"value_0": "cluster1", "value_1": "o'brien", "value_2": "1", "value_3": "2"},
+		},
 		{
 			name:     "Select with order by",
 			fields:   []Field{NewField("name")},
@@ -81,6 +104,9 @@ func Test_selectQueryBuilder_Build(t *testing.T) {
 			if tt.cluster != "" {
 				q = q.WithCluster(&tt.cluster)
 			}
+			if tt.all {
+				q = q.AllReplicas()
+			}
 			if tt.orderCol != nil && tt.orderDir != nil {
 				q = q.OrderBy(*tt.orderCol, *tt.orderDir)
 			}
@@ -92,6 +118,9 @@ func Test_selectQueryBuilder_Build(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("Build() got = %q, want %q", got, tt.want)
 			}
+			if !maps.Equal(q.Parameters(), tt.wantParams) {
+				t.Errorf("Parameters() got = %v, want %v", q.Parameters(), tt.wantParams)
+			}
 		})
 	}
 }
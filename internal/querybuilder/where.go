@@ -6,8 +6,11 @@ import (
 	"strings"
 )
 
+// Where is a condition of a WHERE clause. It is only implemented in this package, by the values
+// WhereEquals, WhereIn and the other constructors return.
 type Where interface {
-	Clause() string
+	// clause renders the condition, binding the values it compares against to params.
+	clause(params *parameters) string
 }
 
 type simpleWhere struct {
@@ -47,13 +50,13 @@ func IsNull(fieldName string) Where {
 	}
 }
 
-func (s *simpleWhere) Clause() string {
+func (s *simpleWhere) clause(params *parameters) string {
 	if s.value == nil {
 		return fmt.Sprintf("%s IS NULL", backtick(s.field))
 	}
 
 	if reflect.TypeOf(s.value).String() == "string" {
-		return fmt.Sprintf("%s %s %s", backtick(s.field), s.operator, quote(s.value.(string)))
+		return fmt.Sprintf("%s %s %s", backtick(s.field), s.operator, params.literal(s.value.(string)))
 	}
 
 	if reflect.TypeOf(s.value).Kind() == reflect.Slice {
@@ -66,7 +69,7 @@ func (s *simpleWhere) Clause() string {
 		for i := 0; i < sliceValue.Len(); i++ {
 			elem := sliceValue.Index(i).Interface()
 			if isStringSlice {
-				values[i] = quote(elem.(string))
+				values[i] = params.literal(elem.(string))
 			} else {
 				values[i] = fmt.Sprintf("%v", elem)
 			}
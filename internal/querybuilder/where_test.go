@@ -1,19 +1,22 @@
 package querybuilder
 
 import (
+	"maps"
 	"testing"
 )
 
-func Test_SimpleWhere_Clause(t *testing.T) {
+func Test_SimpleWhere_clause(t *testing.T) {
 	tests := []struct {
-		name  string
-		where Where
-		want  string
+		name       string
+		where      Where
+		want       string
+		wantParams map[string]string
 	}{
 		{
-			name:  "String",
-			where: WhereEquals("name", "mark"),
-			want:  "`name` = 'mark'",
+			name:       "String",
+			where:      WhereEquals("name", "mark"),
+			want:       "`name` = {value_0:String}",
+			wantParams: map[string]string{"value_0": "mark"},
 		},
 		{
 			name:  "Numeric",
@@ -21,14 +24,16 @@ func Test_SimpleWhere_Clause(t *testing.T) {
 			want:  "`age` = 3",
 		},
 		{
-			name:  "String with backtick in name",
-			where: WhereEquals("te`st", "value"),
-			want:  "`te\\`st` = 'value'",
+			name:       "String with backtick in name",
+			where:      WhereEquals("te`st", "value"),
+			want:       "`te\\`st` = {value_0:String}",
+			wantParams: map[string]string{"value_0": "value"},
 		},
 		{
-			name:  "String Differs",
-			where: WhereDiffers("name", "mark"),
-			want:  "`name` <> 'mark'",
+			name:       "String Differs",
+			where:      WhereDiffers("name", "mark"),
+			want:       "`name` <> {value_0:String}",
+			wantParams: map[string]string{"value_0": "mark"},
 		},
 		{
 			name:  "Numeric Differs",
@@ -36,20 +41,42 @@ func Test_SimpleWhere_Clause(t *testing.T) {
 			want:  "`age` <> 3",
 		},
 		{
-			name:  "String with backtick in name Differs",
-			where: WhereDiffers("te`st", "value"),
-			want:  "`te\\`st` <> 'value'",
+			name:       "String with backtick in name Differs",
+			where:      WhereDiffers("te`st", "value"),
+			want:       "`te\\`st` <> {value_0:String}",
+			wantParams: map[string]string{"value_0": "value"},
 		},
 		{
 			name:  "Null",
 			where: IsNull("age"),
 			want:  "`age` IS NULL",
 		},
+		{
+			name:       "String with quote is not interpolated",
+			where:      WhereEquals("name", "o'brien\\"),
+			want:       "`name` = {value_0:String}",
+			wantParams: map[string]string{"value_0": "o'brien\\"},
+		},
+		{
+			name:       "String In",
+			where:      WhereIn("name", []string{"a", "b"}),
+			want:       "`name` IN ({value_0:String}, {value_1:String})",
+			wantParams: map[string]string{"value_0": "a", "value_1": "b"},
+		},
+		{
+			name:  "Numeric In",
+			where: WhereIn("age", []int{1, 2}),
+			want:  "`age` IN (1, 2)",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.where.Clause(); got != tt.want {
-				t.Errorf("Clause() = %v, want %v", got, tt.want)
+			params := newParameters()
+			if got := tt.where.clause(params); got != tt.want {
+				t.Errorf("clause() = %v, want %v", got, tt.want)
+			}
+			if !maps.Equal(params.values, tt.wantParams) {
+				t.Errorf("clause() params = %v, want %v", params.values, tt.wantParams)
 			}
 		})
 	}
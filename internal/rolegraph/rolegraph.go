@@ -0,0 +1,192 @@
+// Package rolegraph models the hierarchy of ClickHouse roles: which roles are granted to which users
+// and roles. It is shared by the role grant resources, to reject cycles at plan time, and by the
+// role_graph data source.
+package rolegraph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Node kinds.
+const (
+	KindUser = "user"
+	KindRole = "role"
+)
+
+// Node is a user or a role.
+type Node struct {
+	Name string
+	Kind string
+}
+
+// Edge is a role granted to a user or another role.
+type Edge struct {
+	// Role is the name of the granted role.
+	Role string
+	// Grantee is the user or role Role is granted to.
+	Grantee     Node
+	AdminOption bool
+}
+
+// Graph is a set of users and roles and the grants between them.
+type Graph struct {
+	nodes map[Node]bool
+	edges map[edgeKey]Edge
+}
+
+type edgeKey struct {
+	role    string
+	grantee Node
+}
+
+func New() *Graph {
+	return &Graph{
+		nodes: make(map[Node]bool),
+		edges: make(map[edgeKey]Edge),
+	}
+}
+
+// AddNode adds a user or role, even one with no grants.
+func (g *Graph) AddNode(n Node) {
+	g.nodes[n] = true
+}
+
+// AddEdge grants e.Role to e.Grantee, replacing an existing grant between the two.
+func (g *Graph) AddEdge(e Edge) {
+	g.AddNode(Node{Name: e.Role, Kind: KindRole})
+	g.AddNode(e.Grantee)
+	g.edges[edgeKey{role: e.Role, grantee: e.Grantee}] = e
+}
+
+// RemoveEdge removes the grant of e.Role to e.Grantee, if any.
+func (g *Graph) RemoveEdge(e Edge) {
+	delete(g.edges, edgeKey{role: e.Role, grantee: e.Grantee})
+}
+
+// RemoveEdges removes every grant of role.
+func (g *Graph) RemoveEdges(role string) {
+	for k := range g.edges {
+		if k.role == role {
+			delete(g.edges, k)
+		}
+	}
+}
+
+// Nodes returns the users and roles sorted by kind and name.
+func (g *Graph) Nodes() []Node {
+	nodes := make([]Node, 0, len(g.nodes))
+	for n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+	slices.SortFunc(nodes, compareNodes)
+
+	return nodes
+}
+
+// Edges returns the grants sorted by role and grantee.
+func (g *Graph) Edges() []Edge {
+	edges := make([]Edge, 0, len(g.edges))
+	for _, e := range g.edges {
+		edges = append(edges, e)
+	}
+	slices.SortFunc(edges, func(a, b Edge) int {
+		if c := strings.Compare(a.Role, b.Role); c != 0 {
+			return c
+		}
+		return compareNodes(a.Grantee, b.Grantee)
+	})
+
+	return edges
+}
+
+// Cycle returns a path of roles that ends where it starts, each granted to the next one, or nil if
+// the role hierarchy has no cycle. Users cannot be granted to anything and are never part of one.
+func (g *Graph) Cycle() []string {
+	granted := make(map[string][]string)
+	for _, e := range g.Edges() {
+		if e.Grantee.Kind == KindRole {
+			granted[e.Role] = append(granted[e.Role], e.Grantee.Name)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	path := make([]string, 0)
+
+	var visit func(role string) []string
+	visit = func(role string) []string {
+		state[role] = visiting
+		path = append(path, role)
+
+		for _, grantee := range granted[role] {
+			switch state[grantee] {
+			case visiting:
+				start := slices.Index(path, grantee)
+				return append(slices.Clone(path[start:]), grantee)
+			case unvisited:
+				if cycle := visit(grantee); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[role] = done
+		return nil
+	}
+
+	for _, n := range g.Nodes() {
+		if n.Kind == KindRole && state[n.Name] == unvisited {
+			if cycle := visit(n.Name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// DOT renders the graph in the Graphviz DOT language, with an edge from each role to its grantees.
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph roles {\n")
+	for _, n := range g.Nodes() {
+		shape := "ellipse"
+		if n.Kind == KindUser {
+			shape = "box"
+		}
+		sb.WriteString(fmt.Sprintf("  %s [label=%s, shape=%s];\n", n.id(), quote(n.Name), shape))
+	}
+	for _, e := range g.Edges() {
+		attrs := ""
+		if e.AdminOption {
+			attrs = ` [label="admin option"]`
+		}
+		sb.WriteString(fmt.Sprintf("  %s -> %s%s;\n", Node{Name: e.Role, Kind: KindRole}.id(), e.Grantee.id(), attrs))
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// id is the DOT identifier of the node: users and roles may share a name.
+func (n Node) id() string {
+	return quote(n.Kind + ":" + n.Name)
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func compareNodes(a, b Node) int {
+	if c := strings.Compare(a.Kind, b.Kind); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Name, b.Name)
+}
@@ -0,0 +1,94 @@
+package rolegraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func toUser(role string, user string) Edge {
+	return Edge{Role: role, Grantee: Node{Name: user, Kind: KindUser}}
+}
+
+func toRole(role string, grantee string) Edge {
+	return Edge{Role: role, Grantee: Node{Name: grantee, Kind: KindRole}}
+}
+
+func TestGraph_Cycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges []Edge
+		want  []string
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name:  "Hierarchy",
+			edges: []Edge{toRole("reader", "writer"), toRole("writer", "admin"), toRole("reader", "admin"), toUser("admin", "alice")},
+		},
+		{
+			name:  "Self grant",
+			edges: []Edge{toRole("a", "a")},
+			want:  []string{"a", "a"},
+		},
+		{
+			name:  "Two roles",
+			edges: []Edge{toRole("a", "b"), toRole("b", "a")},
+			want:  []string{"a", "b", "a"},
+		},
+		{
+			name:  "Cycle below an acyclic prefix",
+			edges: []Edge{toRole("a", "b"), toRole("b", "c"), toRole("c", "d"), toRole("d", "b")},
+			want:  []string{"b", "c", "d", "b"},
+		},
+		{
+			name:  "Users and roles sharing a name",
+			edges: []Edge{toRole("a", "b"), toUser("b", "a")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New()
+			for _, e := range tt.edges {
+				g.AddEdge(e)
+			}
+			if got := g.Cycle(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cycle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_RemoveEdges(t *testing.T) {
+	g := New()
+	g.AddEdge(toRole("a", "b"))
+	g.AddEdge(toRole("b", "a"))
+	g.RemoveEdges("b")
+
+	if got := g.Cycle(); got != nil {
+		t.Errorf("Cycle() = %v, want nil", got)
+	}
+	if got, want := g.Edges(), []Edge{toRole("a", "b")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Edges() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_DOT(t *testing.T) {
+	g := New()
+	g.AddNode(Node{Name: "idle", Kind: KindRole})
+	g.AddEdge(Edge{Role: "admin", Grantee: Node{Name: `al"ice`, Kind: KindUser}, AdminOption: true})
+	g.AddEdge(toRole("reader", "admin"))
+
+	want := `digraph roles {
+  "role:admin" [label="admin", shape=ellipse];
+  "role:idle" [label="idle", shape=ellipse];
+  "role:reader" [label="reader", shape=ellipse];
+  "user:al\"ice" [label="al\"ice", shape=box];
+  "role:admin" -> "user:al\"ice" [label="admin option"];
+  "role:reader" -> "role:admin";
+}
+`
+	if got := g.DOT(); got != want {
+		t.Errorf("DOT() = %s, want %s", got, want)
+	}
+}
@@ -111,6 +111,22 @@ func (r *ResourceBuilder) WithListResourceFieldReference(attrName string, resour
 	return r
 }
 
+// WithDependsOn sets the depends_on meta-argument to the given resource addresses, such as
+// `clickhousedbops_table.events`, for references terraform cannot infer.
+func (r *ResourceBuilder) WithDependsOn(addresses ...string) *ResourceBuilder {
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")}}
+	for i, address := range addresses {
+		if i != 0 {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		}
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenIdent, Bytes: []byte(address)})
+	}
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+	r.getRootResourceBody().SetAttributeRaw("depends_on", tokens)
+
+	return r
+}
+
 func (r *ResourceBuilder) AddDependency(resource string) *ResourceBuilder {
 	r.dependencies = append(r.dependencies, resource)
 	return r
@@ -151,6 +167,27 @@ func (b *BlockBuilder) WithBoolAttribute(attrName string, attrVal bool) *BlockBu
 	return b
 }
 
+// WithMapAttribute sets a map of strings, such as the parameters of a dictionary source.
+func (b *BlockBuilder) WithMapAttribute(attrName string, attrVal map[string]string) *BlockBuilder {
+	values := make(map[string]cty.Value, len(attrVal))
+	for k, v := range attrVal {
+		values[k] = cty.StringVal(v)
+	}
+	b.body.SetAttributeValue(attrName, cty.MapVal(values))
+
+	return b
+}
+
+func (b *BlockBuilder) WithResourceFieldReference(attrName string, resourceType string, resourceName string, fieldName string) *BlockBuilder {
+	b.body.SetAttributeTraversal(attrName, hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: resourceName},
+		hcl.TraverseAttr{Name: fieldName},
+	})
+
+	return b
+}
+
 func (b *BlockBuilder) WithFunction(attrName string, function string, args ...string) *BlockBuilder {
 	b.body.SetAttributeRaw(attrName, functionTokens(function, args))
 
@@ -20,7 +20,8 @@ func TestResourcebuilder_Build(t *testing.T) {
 			function string
 			arg      string
 		}
-		want string
+		dependsOn []string
+		want      string
 	}{
 		{
 			name:         "Empty resource",
@@ -74,6 +75,15 @@ func TestResourcebuilder_Build(t *testing.T) {
 			},
 			want: `resource "test" "foo" {
   hash = sha256("test")
+}`,
+		},
+		{
+			name:         "Resource with explicit dependencies",
+			resourceType: "test",
+			resourceName: "foo",
+			dependsOn:    []string{"test.bar", "test.baz"},
+			want: `resource "test" "foo" {
+  depends_on = [test.bar, test.baz]
 }`,
 		},
 	}
@@ -93,9 +103,31 @@ func TestResourcebuilder_Build(t *testing.T) {
 				r.WithFunction(n, v.function, v.arg)
 			}
 
+			if len(tt.dependsOn) > 0 {
+				r.WithDependsOn(tt.dependsOn...)
+			}
+
 			if got := strings.TrimRight(r.Build(), "\n"); got != tt.want {
 				t.Errorf("Build() = %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestBlockBuilder_WithMapAttribute(t *testing.T) {
+	r := New("test", "foo").WithBlock("source", func(b *BlockBuilder) {
+		b.WithMapAttribute("parameters", map[string]string{"table": "events", "db": "default"})
+	})
+
+	want := `resource "test" "foo" {
+  source {
+    parameters = {
+      db    = "default"
+      table = "events"
+    }
+  }
+}`
+	if got := strings.TrimRight(r.Build(), "\n"); got != want {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,292 @@
+package tfutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+// knownException describes a ClickHouse error code users commonly hit and how to fix it.
+type knownException struct {
+	name string
+	hint func(ex *clickhouse.Exception) string
+}
+
+func staticHint(hint string) func(*clickhouse.Exception) string {
+	return func(*clickhouse.Exception) string {
+		return hint
+	}
+}
+
+const keeperHint = "ClickHouse could not complete the operation in Keeper, which stores replicated access entities and coordinates ON CLUSTER queries. Check the health of the Keeper cluster and of the connection to it, then apply again."
+
+// missingGrantRegex extracts the missing privilege from an ACCESS_DENIED message.
+var missingGrantRegex = regexp.MustCompile(`(?s)necessary to have the grant (.+?)\.?\s*$`)
+
+var exceptionCatalog = map[int32]knownException{
+	60: {
+		name: "UNKNOWN_TABLE",
+		hint: staticHint("The table does not exist. Create it before this resource, or check `database_name` and `table_name` for typos."),
+	},
+	81: {
+		name: "UNKNOWN_DATABASE",
+		hint: staticHint("The database does not exist. Create it before this resource, for example by referencing a `clickhousedbops_database` resource so Terraform orders the operations, or check `database_name` for typos."),
+	},
+	159: {
+		name: "TIMEOUT_EXCEEDED",
+		hint: staticHint("The query took longer than allowed. For ON CLUSTER queries check that every replica is online, or raise `distributed_ddl_task_timeout` with `query_settings`."),
+	},
+	192: {
+		name: "UNKNOWN_USER",
+		hint: staticHint("The user does not exist. Create it before this resource, for example by referencing a `clickhousedbops_user` resource so Terraform orders the operations, or check the user name for typos."),
+	},
+	225: {
+		name: "NO_ZOOKEEPER",
+		hint: staticHint(keeperHint),
+	},
+	493: {
+		name: "ACCESS_ENTITY_ALREADY_EXISTS",
+		hint: staticHint("An entity with the same name already exists, possibly created outside Terraform. Import it with `terraform import` or choose another name."),
+	},
+	497: {
+		name: "ACCESS_DENIED",
+		hint: func(ex *clickhouse.Exception) string {
+			if match := missingGrantRegex.FindStringSubmatch(ex.Message); match != nil {
+				return fmt.Sprintf("The user the provider connects as is missing the `%s` privilege. Grant it to that user, for example with `GRANT %s TO <user>`, or configure the provider with a more privileged user.", match[1], match[1])
+			}
+			return "The user the provider connects as is missing a privilege required by this operation. Grant it to that user or configure the provider with a more privileged user."
+		},
+	},
+	511: {
+		name: "UNKNOWN_ROLE",
+		hint: staticHint("The role does not exist. Create it before this resource, for example by referencing a `clickhousedbops_role` resource so Terraform orders the operations, or check the role name for typos."),
+	},
+	516: {
+		name: "AUTHENTICATION_FAILED",
+		hint: staticHint("ClickHouse rejected the credentials. Check `auth_config` in the provider configuration."),
+	},
+	701: {
+		name: "CLUSTER_DOESNT_EXIST",
+		hint: staticHint("The cluster set in `cluster_name` is not defined on the server. Check `system.clusters` for the available clusters."),
+	},
+	999: {
+		name: "KEEPER_EXCEPTION",
+		hint: staticHint(keeperHint),
+	},
+}
+
+// ErrorDetail formats err as the detail of a diagnostic, prefixed with a hint on how to fix it
+// when it is a known ClickHouse error.
+func ErrorDetail(err error) string {
+	return WithErrorHint(fmt.Sprintf("%+v\n", err), err)
+}
+
+// WithErrorHint prefixes detail with a hint on how to fix err when it is a known ClickHouse error.
+func WithErrorHint(detail string, err error) string {
+	ex, ok := clickhouseclient.AsException(err)
+	if !ok {
+		return detail
+	}
+
+	known, ok := exceptionCatalog[ex.Code]
+	if !ok {
+		return detail
+	}
+
+	name := ex.CodeName
+	if name == "" {
+		name = known.name
+	}
+
+	return fmt.Sprintf("%s (code %d): %s\n\n%s", name, ex.Code, known.hint(ex), detail)
+}
+
+// replicaDriftKey names the private-state entry listing the replicas where the entity differs, as found
+// by the last refresh.
+const replicaDriftKey = "replica_drift"
+
+// PrivateState is the private state of a resource, as held by the framework requests and responses.
+type PrivateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// ReplicaDrift reports an entity that differs between the replicas of its cluster as drift. It returns
+// true when replicas miss the entity: removing the resource from the state then lets the next apply
+// create it on them again. Replicas where the entity is different are recorded in private, for
+// ReplaceDriftedReplicas to plan the replacement of the resource.
+func ReplicaDrift(ctx context.Context, diags *diag.Diagnostics, private PrivateState, divergence *dbops.ReplicaDivergence, kind string, name string) bool {
+	if divergence == nil {
+		diags.Append(private.SetKey(ctx, replicaDriftKey, nil)...)
+		return false
+	}
+
+	diags.AddWarning(
+		fmt.Sprintf("ClickHouse %s differs between replicas", kind),
+		divergence.Detail(kind, name),
+	)
+
+	if len(divergence.Missing) > 0 {
+		return true
+	}
+
+	hosts, err := json.Marshal(divergence.Different)
+	if err != nil {
+		diags.AddError("Error recording replica drift", err.Error())
+		return false
+	}
+	diags.Append(private.SetKey(ctx, replicaDriftKey, hosts)...)
+
+	return false
+}
+
+// ReplaceDriftedReplicas plans the replacement of a resource whose entity ReplicaDrift found different
+// between replicas, so that the apply creates it again identically on all of them. attribute names a
+// computed string attribute of the resource, planned unknown to carry the replacement.
+func ReplaceDriftedReplicas(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, kind string, attribute string) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	value, diags := req.Private.GetKey(ctx, replicaDriftKey)
+	resp.Diagnostics.Append(diags...)
+	if len(value) == 0 {
+		return
+	}
+
+	var hosts []string
+	if err := json.Unmarshal(value, &hosts); err != nil {
+		resp.Diagnostics.AddError("Error reading replica drift", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attribute), types.StringUnknown())...)
+	resp.RequiresReplace.Append(path.Root(attribute))
+	resp.Diagnostics.AddWarning(
+		fmt.Sprintf("ClickHouse %s differs between replicas", kind),
+		fmt.Sprintf("The %s is replaced to create it again identically on all replicas. It is different on %s.", kind, strings.Join(hosts, ", ")),
+	)
+}
+
+// RequireCapability fails the plan when the server lacks a feature the resource needs, instead of
+// letting the apply fail later on a confusing SQL error. supported picks the feature in the flags. It
+// only warns when the flags were derived from the version, which cannot tell a feature is missing.
+func RequireCapability(ctx context.Context, diags *diag.Diagnostics, client dbops.Client, feature string, supported func(dbops.CapabilityFlags) bool) {
+	flags, err := client.GetCapabilityFlags(ctx)
+	if err != nil {
+		diags.AddWarning(
+			"Could not check server capabilities",
+			fmt.Sprintf("Skipping validation that the server supports %s. Error: %+v", feature, err),
+		)
+		return
+	}
+
+	if !supported(flags) && flags.FromVersion {
+		diags.AddWarning(
+			"Could not check server capabilities",
+			fmt.Sprintf("Skipping validation that the server supports %s: the server could not be probed, check that the user can read the system tables.", feature),
+		)
+		return
+	}
+
+	if !supported(flags) {
+		diags.AddError(
+			"Not supported by this server",
+			fmt.Sprintf("The connected ClickHouse server does not support %s.", feature),
+		)
+	}
+}
+
+// RejectRoleCycle errors when the role grants planned by resource, applied to those of the server
+// along with the ones planned so far by the other resources of the plan, form a cycle. The plan is
+// recorded on client unless it is rejected. The check is skipped with a warning when the grants
+// cannot be read.
+func RejectRoleCycle(ctx context.Context, diags *diag.Diagnostics, client dbops.Client, clusterName *string, resource string, plan dbops.RoleGrantPlan) {
+	graph, err := client.GetRoleGraph(ctx, clusterName)
+	if err != nil {
+		diags.AddWarning(
+			"Could not check the role hierarchy",
+			fmt.Sprintf("Skipping validation that the role grants do not form a cycle. Error: %+v", err),
+		)
+		client.PlanRoleGrants(clusterName, resource, plan, nil)
+		return
+	}
+
+	client.PlanRoleGrants(clusterName, resource, plan, func(others []dbops.RoleGrantPlan) bool {
+		plans := append(others, plan)
+
+		// Revocations first, so that a grant reversed by the plan does not conflict with itself.
+		for _, p := range plans {
+			for _, role := range p.ReplacedRoles {
+				graph.RemoveEdges(role)
+			}
+			for _, e := range p.Removed {
+				graph.RemoveEdge(e)
+			}
+		}
+		for _, p := range plans {
+			for _, e := range p.Added {
+				graph.AddEdge(e)
+			}
+		}
+
+		cycle := graph.Cycle()
+		if cycle != nil {
+			diags.AddError(
+				"Role Cycle",
+				fmt.Sprintf("This grant would make the role hierarchy circular: %s, where each role is granted to the next one. ClickHouse rejects role grants that form a cycle.", strings.Join(cycle, " -> ")),
+			)
+		}
+		return cycle == nil
+	})
+}
+
+// SameQuery reports whether two queries of a view of database are equal once formatted by the
+// server. When the server cannot format them, a warning is added and ok is false.
+func SameQuery(ctx context.Context, diags *diag.Diagnostics, client dbops.Client, database string, a string, b string) (same bool, ok bool) {
+	same, err := client.SameQuery(ctx, database, a, b)
+	if err != nil {
+		diags.AddWarning(
+			"Could not compare queries",
+			fmt.Sprintf("Comparing the query of the view as written, which might hide a change made outside of terraform or show a difference where ClickHouse only formats the query differently. Error: %+v", err),
+		)
+		return false, false
+	}
+
+	return same, true
+}
+
+// SaveCreated reports the error of a CREATE statement and saves the state toState returns for the
+// created entity. When the statement was applied on some hosts of the cluster only, created is
+// returned along with err: it is saved anyway, so that terraform taints it and the next apply
+// replaces it instead of leaving it behind.
+func SaveCreated[T any, S any](ctx context.Context, resp *resource.CreateResponse, summary string, created *T, err error, toState func(created *T) (S, diag.Diagnostics)) {
+	if err != nil {
+		resp.Diagnostics.AddError(summary, ErrorDetail(err))
+	}
+	if created == nil {
+		if err == nil {
+			resp.Diagnostics.AddError(summary, "The entity was created but could not be read back from the server.")
+		}
+		return
+	}
+
+	state, diags := toState(created)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
@@ -0,0 +1,285 @@
+package tfutils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/rolegraph"
+)
+
+func TestWithErrorHint(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantPrefix string
+	}{
+		{
+			name:       "Access denied names the missing privilege",
+			err:        errors.WithMessage(&clickhouse.Exception{Code: 497, Message: "alice: Not enough privileges. To execute this query, it's necessary to have the grant CREATE ROLE ON *.*."}, "error running query"),
+			wantPrefix: "ACCESS_DENIED (code 497): The user the provider connects as is missing the `CREATE ROLE ON *.*` privilege.",
+		},
+		{
+			name:       "Code name returned by the server wins",
+			err:        &clickhouse.Exception{Code: 511, CodeName: "UNKNOWN_ROLE"},
+			wantPrefix: "UNKNOWN_ROLE (code 511): The role does not exist.",
+		},
+		{
+			name:       "Keeper",
+			err:        &clickhouse.Exception{Code: 999},
+			wantPrefix: "KEEPER_EXCEPTION (code 999): ClickHouse could not complete the operation in Keeper",
+		},
+		{
+			name:       "Unknown code",
+			err:        &clickhouse.Exception{Code: 62},
+			wantPrefix: "detail",
+		},
+		{
+			name:       "Not an exception",
+			err:        errors.New("connection refused"),
+			wantPrefix: "detail",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WithErrorHint("detail", tt.err)
+			if !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("WithErrorHint() = %q, want prefix %q", got, tt.wantPrefix)
+			}
+			if !strings.HasSuffix(got, "detail") {
+				t.Errorf("WithErrorHint() = %q, want the original detail at the end", got)
+			}
+		})
+	}
+}
+
+// privateState is an in-memory PrivateState.
+type privateState map[string][]byte
+
+func (p privateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p privateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	if len(value) == 0 {
+		delete(p, key)
+	} else {
+		p[key] = value
+	}
+	return nil
+}
+
+func TestReplicaDrift(t *testing.T) {
+	tests := []struct {
+		name        string
+		divergence  *dbops.ReplicaDivergence
+		wantRemove  bool
+		wantPrivate string
+		wantWarning string
+	}{
+		{
+			name: "Same on all replicas",
+		},
+		{
+			name:        "Missing on a replica removes the resource",
+			divergence:  &dbops.ReplicaDivergence{Missing: []string{"ch-2"}, Different: []string{"ch-3"}},
+			wantRemove:  true,
+			wantWarning: "ch-2",
+		},
+		{
+			name:        "Different on a replica is recorded for the plan",
+			divergence:  &dbops.ReplicaDivergence{Different: []string{"ch-2", "ch-3"}},
+			wantPrivate: `["ch-2","ch-3"]`,
+			wantWarning: "ch-2, ch-3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A drift recorded by an earlier refresh must not outlive the replicas agreeing again.
+			private := privateState{replicaDriftKey: []byte(`["ch-9"]`)}
+			var diags diag.Diagnostics
+
+			remove := ReplicaDrift(context.Background(), &diags, private, tt.divergence, "user", "alice")
+			if remove != tt.wantRemove {
+				t.Errorf("ReplicaDrift() = %v, want %v", remove, tt.wantRemove)
+			}
+			if diags.HasError() {
+				t.Fatalf("ReplicaDrift() unexpected error: %v", diags)
+			}
+			if got := string(private[replicaDriftKey]); !tt.wantRemove && got != tt.wantPrivate {
+				t.Errorf("ReplicaDrift() private = %q, want %q", got, tt.wantPrivate)
+			}
+			switch {
+			case tt.wantWarning == "" && diags.WarningsCount() > 0:
+				t.Errorf("ReplicaDrift() unexpected warning: %v", diags)
+			case tt.wantWarning != "" && (diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), tt.wantWarning)):
+				t.Errorf("ReplicaDrift() want a warning naming %s, got %v", tt.wantWarning, diags)
+			}
+		})
+	}
+}
+
+// capabilityClient is a dbops.Client that only answers GetCapabilityFlags.
+type capabilityClient struct {
+	dbops.Client
+	flags dbops.CapabilityFlags
+}
+
+func (c capabilityClient) GetCapabilityFlags(context.Context) (dbops.CapabilityFlags, error) {
+	return c.flags, nil
+}
+
+func TestRequireCapability(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       dbops.CapabilityFlags
+		wantError   bool
+		wantWarning bool
+	}{
+		{
+			name:  "Supported",
+			flags: dbops.CapabilityFlags{MaskingPolicies: true},
+		},
+		{
+			name:      "Probed, not supported",
+			flags:     dbops.CapabilityFlags{},
+			wantError: true,
+		},
+		{
+			name:        "Derived from the version, unknown",
+			flags:       dbops.CapabilityFlags{FromVersion: true},
+			wantWarning: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			RequireCapability(context.Background(), &diags, capabilityClient{flags: tt.flags}, "masking policies", func(f dbops.CapabilityFlags) bool {
+				return f.MaskingPolicies
+			})
+
+			if diags.HasError() != tt.wantError {
+				t.Errorf("RequireCapability() error = %v, want %v: %v", diags.HasError(), tt.wantError, diags)
+			}
+			if (diags.WarningsCount() > 0) != tt.wantWarning {
+				t.Errorf("RequireCapability() warning = %v, want %v: %v", diags.WarningsCount() > 0, tt.wantWarning, diags)
+			}
+		})
+	}
+}
+
+// roleGraphClient is a dbops.Client that only answers GetRoleGraph, with the role grants of edges,
+// and records planned role grants.
+type roleGraphClient struct {
+	dbops.Client
+	edges   []rolegraph.Edge
+	planned dbops.PlannedRoleGrants
+}
+
+func (c *roleGraphClient) GetRoleGraph(context.Context, *string) (*rolegraph.Graph, error) {
+	graph := rolegraph.New()
+	for _, e := range c.edges {
+		graph.AddEdge(e)
+	}
+	return graph, nil
+}
+
+func (c *roleGraphClient) PlanRoleGrants(clusterName *string, resource string, plan dbops.RoleGrantPlan, accept func([]dbops.RoleGrantPlan) bool) {
+	c.planned.PlanRoleGrants(clusterName, resource, plan, accept)
+}
+
+func TestRejectRoleCycle(t *testing.T) {
+	grant := func(role string, grantee string) rolegraph.Edge {
+		return rolegraph.Edge{Role: role, Grantee: rolegraph.Node{Name: grantee, Kind: rolegraph.KindRole}}
+	}
+
+	type step struct {
+		clusterName *string
+		resource    string
+		plan        dbops.RoleGrantPlan
+		wantErr     bool
+	}
+
+	tests := []struct {
+		name   string
+		server []rolegraph.Edge
+		steps  []step
+	}{
+		{
+			name: "Cycle across resources",
+			steps: []step{
+				{resource: "ab", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("a", "b")}}},
+				{resource: "ba", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("b", "a")}}, wantErr: true},
+			},
+		},
+		{
+			name: "Resource planned again",
+			steps: []step{
+				{resource: "ab", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("a", "b")}}},
+				{resource: "ab", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("a", "b")}}},
+			},
+		},
+		{
+			name: "Grant of a resource planned again is dropped",
+			steps: []step{
+				{resource: "x", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("a", "b")}}},
+				{resource: "x", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("a", "c")}}},
+				{resource: "ba", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("b", "a")}}},
+			},
+		},
+		{
+			name: "Other clusters are separate hierarchies",
+			steps: []step{
+				{resource: "ab", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("a", "b")}}},
+				{clusterName: new("other"), resource: "ba", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("b", "a")}}},
+			},
+		},
+		{
+			name:   "Grant reversed by a replaced resource",
+			server: []rolegraph.Edge{grant("a", "b")},
+			steps: []step{
+				{resource: "ba", plan: dbops.RoleGrantPlan{Removed: []rolegraph.Edge{grant("a", "b")}, Added: []rolegraph.Edge{grant("b", "a")}}},
+			},
+		},
+		{
+			name:   "Grant of a destroyed resource",
+			server: []rolegraph.Edge{grant("a", "b")},
+			steps: []step{
+				{resource: "revoke ab", plan: dbops.RoleGrantPlan{Removed: []rolegraph.Edge{grant("a", "b")}}},
+				{resource: "ba", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("b", "a")}}},
+			},
+		},
+		{
+			name:   "Members of a role replaced",
+			server: []rolegraph.Edge{grant("a", "b")},
+			steps: []step{
+				{resource: "members of a", plan: dbops.RoleGrantPlan{ReplacedRoles: []string{"a"}, Added: []rolegraph.Edge{grant("a", "c")}}},
+				{resource: "ba", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("b", "a")}}},
+			},
+		},
+		{
+			name:   "Cycle with the server",
+			server: []rolegraph.Edge{grant("a", "b")},
+			steps: []step{
+				{resource: "ba", plan: dbops.RoleGrantPlan{Added: []rolegraph.Edge{grant("b", "a")}}, wantErr: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &roleGraphClient{edges: tt.server}
+			for i, s := range tt.steps {
+				var diags diag.Diagnostics
+				RejectRoleCycle(context.Background(), &diags, client, s.clusterName, s.resource, s.plan)
+				if diags.HasError() != s.wantErr {
+					t.Errorf("RejectRoleCycle() step %d error = %v, wantErr %v", i, diags, s.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tfutils
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
+)
+
+// PrivilegeCatalog returns the privileges of the server, or the catalog embedded in the provider while
+// client is not configured, as during `terraform validate`.
+func PrivilegeCatalog(ctx context.Context, client dbops.Client) grants.Catalog {
+	if client == nil {
+		return grants.Parsed()
+	}
+
+	return client.PrivilegeCatalog(ctx)
+}
+
+// SetChanges sorts the elements of a set managed authoritatively by what has to happen to them.
+type SetChanges[T any] struct {
+	// Add are desired elements missing from the server.
+	Add []T
+	// Keep are desired elements the server has already.
+	Keep []T
+	// Remove are elements the server has that are not desired anymore.
+	Remove []T
+}
+
+// DiffSets compares the elements of a set on the server with the desired ones. key identifies an
+// element, ignoring the attributes that can change in place.
+func DiffSets[T any](current []T, desired []T, key func(T) string) SetChanges[T] {
+	var changes SetChanges[T]
+
+	existing := make(map[string]bool, len(current))
+	for _, e := range current {
+		existing[key(e)] = true
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, e := range desired {
+		wanted[key(e)] = true
+		if existing[key(e)] {
+			changes.Keep = append(changes.Keep, e)
+		} else {
+			changes.Add = append(changes.Add, e)
+		}
+	}
+
+	for _, e := range current {
+		if !wanted[key(e)] {
+			changes.Remove = append(changes.Remove, e)
+		}
+	}
+
+	return changes
+}
+
+// Reconcile runs apply, which changes a set managed authoritatively until the server matches the plan.
+// When apply fails, readBack replaces the planned set with the one on the server, so that the state
+// records the elements changed before the failure. kind names the set in diagnostics. Returns false
+// when the set could not be read back either.
+func Reconcile(diags *diag.Diagnostics, kind string, apply func() error, readBack func() error) bool {
+	err := apply()
+	if err == nil {
+		return true
+	}
+
+	diags.AddError(
+		"Error Updating "+kind,
+		ErrorDetail(err),
+	)
+
+	if err := readBack(); err != nil {
+		diags.AddError(
+			"Error Reading "+kind,
+			ErrorDetail(err),
+		)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,44 @@
+package tfutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSets(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []string
+		desired []string
+		want    SetChanges[string]
+	}{
+		{
+			name: "Nothing current nor desired",
+		},
+		{
+			name:    "First apply",
+			desired: []string{"events_1", "events_2"},
+			want:    SetChanges[string]{Add: []string{"events_1", "events_2"}},
+		},
+		{
+			name:    "New element",
+			current: []string{"events_1"},
+			desired: []string{"events_1", "events_2"},
+			want:    SetChanges[string]{Add: []string{"events_2"}, Keep: []string{"events_1"}},
+		},
+		{
+			name:    "Element no longer desired",
+			current: []string{"events_1", "events_2"},
+			desired: []string{"events_2"},
+			want:    SetChanges[string]{Keep: []string{"events_2"}, Remove: []string{"events_1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffSets(tt.current, tt.desired, func(s string) string { return s })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffSets() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,9 +3,15 @@ package tfutils
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
 )
 
 // SetToStringSlice converts a Terraform string set to a Go slice.
@@ -36,3 +42,29 @@ func StringSliceToSet(values []string) (types.Set, diag.Diagnostics) {
 	}
 	return set, diags
 }
+
+// QuerySettingsAttribute returns the schema of the `query_settings` attribute shared by all resources.
+// The settings only affect how the provider talks to ClickHouse, so changing them never replaces a resource.
+func QuerySettingsAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType: types.StringType,
+		Optional:    true,
+		Description: "ClickHouse settings applied to the queries run for this resource. They override the provider level `query_settings`.",
+		Validators: []validator.Map{
+			mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+		},
+	}
+}
+
+// WithQuerySettings returns a copy of ctx carrying the resource level `query_settings`.
+func WithQuerySettings(ctx context.Context, settings types.Map) (context.Context, diag.Diagnostics) {
+	if settings.IsNull() || settings.IsUnknown() {
+		return ctx, nil
+	}
+	var values map[string]string
+	diags := settings.ElementsAs(ctx, &values, false)
+	if diags.HasError() {
+		return ctx, diags
+	}
+	return clickhouseclient.WithSettings(ctx, values), diags
+}
@@ -0,0 +1,24 @@
+package rolegraph
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RoleGraph struct {
+	ClusterName types.String `tfsdk:"cluster_name"`
+	Nodes       []Node       `tfsdk:"nodes"`
+	Edges       []Edge       `tfsdk:"edges"`
+	DOT         types.String `tfsdk:"dot"`
+}
+
+type Node struct {
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+type Edge struct {
+	RoleName        types.String `tfsdk:"role_name"`
+	GranteeUserName types.String `tfsdk:"grantee_user_name"`
+	GranteeRoleName types.String `tfsdk:"grantee_role_name"`
+	AdminOption     types.Bool   `tfsdk:"admin_option"`
+}
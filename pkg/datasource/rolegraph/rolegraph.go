@@ -0,0 +1,144 @@
+package rolegraph
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/rolegraph"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
+)
+
+//go:embed rolegraph.md
+var roleGraphDataSourceDescription string
+
+var (
+	_ datasource.DataSource              = &DataSource{}
+	_ datasource.DataSourceWithConfigure = &DataSource{}
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client dbops.Client
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role_graph"
+}
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to read the role hierarchy from. If omitted, the provider `cluster_name` applies when set, otherwise the replica hit by the query is read.",
+			},
+			"nodes": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Every user and role of the server, sorted by type and name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the user or role.",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Either `user` or `role`.",
+						},
+					},
+				},
+			},
+			"edges": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Every role granted to a user or role, sorted by role and grantee.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"role_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the granted role.",
+						},
+						"grantee_user_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the `user` the role is granted to.",
+						},
+						"grantee_role_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the `role` the role is granted to.",
+						},
+						"admin_option": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the grantee can grant the role to others.",
+						},
+					},
+				},
+			},
+			"dot": schema.StringAttribute{
+				Computed:    true,
+				Description: "The graph in the Graphviz DOT language, with an edge from each role to its grantees. Users are drawn as boxes and roles as ellipses.",
+			},
+		},
+		MarkdownDescription: roleGraphDataSourceDescription,
+	}
+}
+
+func (d *DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(dbops.Client)
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config RoleGraph
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	graph, err := d.client.GetRoleGraph(ctx, config.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Role Graph",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	state := RoleGraph{
+		ClusterName: config.ClusterName,
+		Nodes:       make([]Node, 0),
+		Edges:       make([]Edge, 0),
+		DOT:         types.StringValue(graph.DOT()),
+	}
+	for _, n := range graph.Nodes() {
+		state.Nodes = append(state.Nodes, Node{
+			Name: types.StringValue(n.Name),
+			Type: types.StringValue(n.Kind),
+		})
+	}
+	for _, e := range graph.Edges() {
+		edge := Edge{
+			RoleName:        types.StringValue(e.Role),
+			GranteeUserName: types.StringNull(),
+			GranteeRoleName: types.StringNull(),
+			AdminOption:     types.BoolValue(e.AdminOption),
+		}
+		if e.Grantee.Kind == rolegraph.KindUser {
+			edge.GranteeUserName = types.StringValue(e.Grantee.Name)
+		} else {
+			edge.GranteeRoleName = types.StringValue(e.Grantee.Name)
+		}
+		state.Edges = append(state.Edges, edge)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
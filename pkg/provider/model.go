@@ -9,10 +9,21 @@ type Model struct {
 	Protocol              types.String `tfsdk:"protocol"`
 	Host                  types.String `tfsdk:"host"`
 	Port                  types.Int32  `tfsdk:"port"`
+	AdditionalHosts       types.List   `tfsdk:"additional_hosts"`
+	ConnOpenStrategy      types.String `tfsdk:"conn_open_strategy"`
 	AuthConfig            AuthConfig   `tfsdk:"auth_config"`
 	TLSConfig             *TLSConfig   `tfsdk:"tls_config"`
+	HTTPConfig            *HTTPConfig  `tfsdk:"http_config"`
 	ReadAfterWriteTimeout types.Int64  `tfsdk:"read_after_write_timeout"`
 	DialTimeout           types.Int64  `tfsdk:"dial_timeout"`
+	QueryTimeout          types.Int64  `tfsdk:"query_timeout"`
+	MaxIdleConns          types.Int64  `tfsdk:"max_idle_conns"`
+	MaxOpenConns          types.Int64  `tfsdk:"max_open_conns"`
+	QuerySettings         types.Map    `tfsdk:"query_settings"`
+	RetryMaxDuration      types.Int64  `tfsdk:"retry_max_duration"`
+	SnapshotCache         types.Bool   `tfsdk:"snapshot_cache"`
+	ClusterName           types.String `tfsdk:"cluster_name"`
+	ClusterMode           types.String `tfsdk:"cluster_mode"`
 }
 
 type AuthConfig struct {
@@ -25,3 +36,10 @@ type TLSConfig struct {
 	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
 	CACert             types.String `tfsdk:"ca_cert"`
 }
+
+type HTTPConfig struct {
+	Path                 types.String `tfsdk:"path"`
+	Headers              types.Map    `tfsdk:"headers"`
+	ProxyURL             types.String `tfsdk:"proxy_url"`
+	CredentialsInHeaders types.Bool   `tfsdk:"credentials_in_headers"`
+}
@@ -5,30 +5,43 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/clickhouseclient"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/datasource/rolegraph"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/project"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/database"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/dictionary"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/grantprivilege"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/grantprivilegetables"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/grantrole"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/maskingpolicy"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/materializedview"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/role"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/rolemembers"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/rowpolicy"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/setting"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/settingsprofile"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/settingsprofileassociation"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/table"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/user"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/pkg/resource/view"
 )
 
 const (
@@ -39,11 +52,14 @@ const (
 
 	authStrategyPassword  = "password"
 	authStrategyBasicAuth = "basicauth"
+
+	defaultRetryMaxDuration = 60 * time.Second
 )
 
 var (
 	availableProtocols      = []string{protocolNative, protocolNativeSecure, protocolHTTP, protocolHTTPS}
 	availableAuthStrategies = []string{authStrategyPassword, authStrategyBasicAuth}
+	availableProxySchemes   = []string{"http", "https", "socks5", "socks5h"}
 )
 
 // Ensure Provider satisfies various provider interfaces.
@@ -75,6 +91,21 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 				Required:    true,
 				Description: "The port to use to connect to the clickhouse instance",
 			},
+			"additional_hosts": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Additional replicas of the same clickhouse instance to connect to when `host` is unavailable. Each entry is either a hostname, which uses `port`, or a `host:port` pair.",
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"conn_open_strategy": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The order in which `host` and `additional_hosts` are tried when connecting. The next host is only tried when a connection to the current one cannot be established. Valid options are: %s. Defaults to %s.", strings.Join(clickhouseclient.AvailableConnOpenStrategies, ", "), clickhouseclient.ConnOpenInOrder),
+				Validators: []validator.String{
+					stringvalidator.OneOf(clickhouseclient.AvailableConnOpenStrategies...),
+				},
+			},
 			"auth_config": schema.SingleNestedAttribute{
 				Attributes: map[string]schema.Attribute{
 					"strategy": schema.StringAttribute{
@@ -117,6 +148,34 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 				Optional:    true,
 				Description: "TLS configuration options",
 			},
+			"http_config": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"path": schema.StringAttribute{
+						Optional:    true,
+						Description: "URL path ClickHouse is served on, for example when it is reached through a gateway. Defaults to `/`.",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"headers": schema.MapAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Sensitive:   true,
+						Description: "Additional HTTP headers to send with every request.",
+					},
+					"proxy_url": schema.StringAttribute{
+						Optional:    true,
+						Sensitive:   true,
+						Description: fmt.Sprintf("URL of the proxy to send requests through. Valid schemes are: %s.", strings.Join(availableProxySchemes, ", ")),
+					},
+					"credentials_in_headers": schema.BoolAttribute{
+						Optional:    true,
+						Description: "If true, send the credentials in the `X-ClickHouse-User` and `X-ClickHouse-Key` headers instead of the URL userinfo.",
+					},
+				},
+				Optional:    true,
+				Description: "HTTP configuration options. Only applies to the http and https protocols.",
+			},
 			"read_after_write_timeout": schema.Int64Attribute{
 				Optional:    true,
 				Description: "Timeout in seconds for read-after-write verification of created resources. ClickHouse Cloud services with multiple replicas may need higher values due to replication lag. Defaults to 30.",
@@ -124,6 +183,32 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 					int64validator.AtLeast(1),
 				},
 			},
+			"retry_max_duration": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum time in seconds spent retrying a query that failed with a transient error, such as a Keeper exception, a timeout or a network error. Statements that are not safe to run twice are only retried when they never reached the server. Set to 0 to disable retries. Defaults to 60.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster used by resources that do not set their own `cluster_name`. Statements are run `ON CLUSTER` and reads go through the `cluster()` table function, as selected by `cluster_mode`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"cluster_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("When `cluster_name` applies to resources that do not set their own. With `always`, it applies to all of them. With `auto`, users, roles, grants, row policies and settings profiles only use it when the server does not keep them in replicated storage, and databases only when the cluster has more than one host. Valid values are: %s. Defaults to `always`.", strings.Join(dbops.AvailableClusterModes, ", ")),
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbops.AvailableClusterModes...),
+					stringvalidator.AlsoRequires(path.MatchRoot("cluster_name")),
+				},
+			},
+			"snapshot_cache": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If true, load system.grants, system.role_grants, system.users, system.roles and system.settings_profile_elements once and serve the reads of individual resources from that copy, instead of running one query per resource. The copy is dropped after every change the provider makes. Speeds up plans of configurations with many grants. Defaults to false.",
+			},
 			"dial_timeout": schema.Int64Attribute{
 				Optional:    true,
 				Description: "Timeout in seconds for establishing connections to ClickHouse. Only applies to the native and nativesecure protocols. Useful when the ClickHouse instance takes time to start up from an idle state.",
@@ -131,6 +216,35 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 					int64validator.AtLeast(1),
 				},
 			},
+			"query_timeout": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Timeout in seconds for a single query. When exceeded, or when Terraform cancels the operation, the query is killed on the server. Defaults to no timeout.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_idle_conns": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of idle connections kept open to ClickHouse. With the http and https protocols the limit applies per host.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_open_conns": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of connections open to ClickHouse at the same time. With the http and https protocols the limit applies per host.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"query_settings": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "ClickHouse settings applied to every query run by the provider, for example `distributed_ddl_task_timeout` or `insert_quorum`. Resources can override them with their own `query_settings` attribute.",
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
 		},
 	}
 }
@@ -145,11 +259,29 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		return
 	}
 
-	if data.Host.IsUnknown() || data.Protocol.IsUnknown() || data.Port.IsUnknown() || data.AuthConfig.Strategy.IsUnknown() || data.AuthConfig.Username.IsUnknown() {
+	if data.Host.IsUnknown() || data.Protocol.IsUnknown() || data.Port.IsUnknown() || data.AuthConfig.Strategy.IsUnknown() || data.AuthConfig.Username.IsUnknown() || data.AdditionalHosts.IsUnknown() || data.QuerySettings.IsUnknown() || data.ClusterName.IsUnknown() || data.ClusterMode.IsUnknown() {
 		// We don't know the service data yet.
 		return
 	}
 
+	var additionalHosts []string
+	if !data.AdditionalHosts.IsNull() {
+		resp.Diagnostics.Append(data.AdditionalHosts.ElementsAs(ctx, &additionalHosts, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var querySettings map[string]string
+	if !data.QuerySettings.IsNull() {
+		resp.Diagnostics.Append(data.QuerySettings.ElementsAs(ctx, &querySettings, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	connOpenStrategy := clickhouseclient.ConnOpenStrategy(data.ConnOpenStrategy.ValueString())
+
 	var clickhouseClient clickhouseclient.ClickhouseClient
 	{
 		switch data.Protocol.ValueString() {
@@ -176,6 +308,11 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 				return
 			}
 
+			if data.HTTPConfig != nil {
+				resp.Diagnostics.AddError("invalid configuration", fmt.Sprintf("http_config is not supported with the %s protocol", data.Protocol.ValueString()))
+				return
+			}
+
 			var port uint16
 			{
 				if !data.Port.IsUnknown() {
@@ -210,12 +347,20 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 			nativeConfig := clickhouseclient.NativeClientConfig{
 				Host:             data.Host.ValueString(),
 				Port:             port,
+				AdditionalHosts:  additionalHosts,
+				ConnOpenStrategy: connOpenStrategy,
 				UserPasswordAuth: auth,
 				TLSConfig:        nativeTLSConfig,
+				Settings:         querySettings,
 			}
 			if !data.DialTimeout.IsNull() {
 				nativeConfig.DialTimeout = time.Duration(data.DialTimeout.ValueInt64()) * time.Second
 			}
+			if !data.QueryTimeout.IsNull() {
+				nativeConfig.QueryTimeout = time.Duration(data.QueryTimeout.ValueInt64()) * time.Second
+			}
+			nativeConfig.MaxIdleConns = int(data.MaxIdleConns.ValueInt64())
+			nativeConfig.MaxOpenConns = int(data.MaxOpenConns.ValueInt64())
 			clickhouseClient, err = clickhouseclient.NewNativeClient(nativeConfig)
 		case protocolHTTP:
 			fallthrough
@@ -273,13 +418,42 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 				}
 			}
 
-			clickhouseClient, err = clickhouseclient.NewHTTPClient(clickhouseclient.HTTPClientConfig{
-				Protocol:  protocol,
-				Host:      data.Host.ValueString(),
-				Port:      port,
-				BasicAuth: auth,
-				TLSConfig: tlsConfig,
-			})
+			httpConfig := clickhouseclient.HTTPClientConfig{
+				Protocol:         protocol,
+				Host:             data.Host.ValueString(),
+				Port:             port,
+				AdditionalHosts:  additionalHosts,
+				ConnOpenStrategy: connOpenStrategy,
+				BasicAuth:        auth,
+				TLSConfig:        tlsConfig,
+				MaxIdleConns:     int(data.MaxIdleConns.ValueInt64()),
+				MaxOpenConns:     int(data.MaxOpenConns.ValueInt64()),
+				Settings:         querySettings,
+			}
+			if !data.QueryTimeout.IsNull() {
+				httpConfig.QueryTimeout = time.Duration(data.QueryTimeout.ValueInt64()) * time.Second
+			}
+			if data.HTTPConfig != nil {
+				httpConfig.Path = data.HTTPConfig.Path.ValueString()
+				httpConfig.CredentialsInHeaders = data.HTTPConfig.CredentialsInHeaders.ValueBool()
+
+				if !data.HTTPConfig.Headers.IsNull() && !data.HTTPConfig.Headers.IsUnknown() {
+					resp.Diagnostics.Append(data.HTTPConfig.Headers.ElementsAs(ctx, &httpConfig.Headers, false)...)
+					if resp.Diagnostics.HasError() {
+						return
+					}
+				}
+
+				if !data.HTTPConfig.ProxyURL.IsNull() && data.HTTPConfig.ProxyURL.ValueString() != "" {
+					proxyURL, err := url.Parse(data.HTTPConfig.ProxyURL.ValueString())
+					if err != nil || !slices.Contains(availableProxySchemes, proxyURL.Scheme) || proxyURL.Host == "" {
+						resp.Diagnostics.AddError("invalid configuration", fmt.Sprintf("invalid proxy_url. It must be an URL with one of the following schemes: %s", strings.Join(availableProxySchemes, ", ")))
+						return
+					}
+					httpConfig.ProxyURL = proxyURL
+				}
+			}
+			clickhouseClient, err = clickhouseclient.NewHTTPClient(httpConfig)
 		}
 	}
 
@@ -288,10 +462,28 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		return
 	}
 
+	retryMaxDuration := defaultRetryMaxDuration
+	if !data.RetryMaxDuration.IsNull() {
+		retryMaxDuration = time.Duration(data.RetryMaxDuration.ValueInt64()) * time.Second
+	}
+	if retryMaxDuration > 0 {
+		clickhouseClient = clickhouseclient.NewRetryingClient(clickhouseClient, clickhouseclient.RetryConfig{MaxDuration: retryMaxDuration})
+	}
+
 	var dbopsOpts []dbops.ClientOption
 	if !data.ReadAfterWriteTimeout.IsNull() {
 		dbopsOpts = append(dbopsOpts, dbops.WithReadAfterWriteTimeout(time.Duration(data.ReadAfterWriteTimeout.ValueInt64())*time.Second))
 	}
+	if !data.ClusterName.IsNull() {
+		mode := dbops.ClusterModeAlways
+		if !data.ClusterMode.IsNull() {
+			mode = dbops.ClusterMode(data.ClusterMode.ValueString())
+		}
+		dbopsOpts = append(dbopsOpts, dbops.WithDefaultCluster(data.ClusterName.ValueString(), mode))
+	}
+	if data.SnapshotCache.ValueBool() {
+		dbopsOpts = append(dbopsOpts, dbops.WithSnapshotCache())
+	}
 
 	dbopsClient, err := dbops.NewClient(clickhouseClient, dbopsOpts...)
 	if err != nil {
@@ -306,10 +498,16 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 func (p *Provider) Resources(ctx context.Context) []func() tfresource.Resource {
 	return []func() tfresource.Resource{
 		database.NewResource,
+		table.NewResource,
+		view.NewResource,
+		materializedview.NewResource,
+		dictionary.NewResource,
 		role.NewResource,
 		user.NewResource,
 		grantrole.NewResource,
+		rolemembers.NewResource,
 		grantprivilege.NewResource,
+		grantprivilegetables.NewResource,
 		maskingpolicy.NewResource,
 		settingsprofile.NewResource,
 		setting.NewResource,
@@ -319,7 +517,9 @@ func (p *Provider) Resources(ctx context.Context) []func() tfresource.Resource {
 }
 
 func (p *Provider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		rolegraph.NewDataSource,
+	}
 }
 
 func New() func() provider.Provider {
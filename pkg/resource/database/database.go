@@ -3,11 +3,11 @@ package database
 import (
 	"context"
 	_ "embed"
-	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -18,6 +18,7 @@ import (
 	"github.com/pingcap/errors"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
 )
 
 //go:embed database.md
@@ -28,6 +29,7 @@ var (
 	_ resource.Resource                = &Resource{}
 	_ resource.ResourceWithConfigure   = &Resource{}
 	_ resource.ResourceWithImportState = &Resource{}
+	_ resource.ResourceWithModifyPlan  = &Resource{}
 )
 
 // NewResource is a helper function to simplify the provider implementation.
@@ -51,7 +53,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "Name of the cluster to create the database into. If omitted, the database will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				Description: "Name of the cluster to create the database into. If omitted, the provider `cluster_name` applies when set, otherwise the database will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -81,11 +83,17 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
 		},
 		MarkdownDescription: databaseResourceDescription,
 	}
 }
 
+// ModifyPlan replaces the database when its replicas differ.
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	tfutils.ReplaceDriftedReplicas(ctx, req, resp, "database", "uuid")
+}
+
 func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -102,37 +110,36 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
-	db, err := r.client.CreateDatabase(ctx, dbops.Database{Name: plan.Name.ValueString(), Comment: plan.Comment.ValueString()}, plan.ClusterName.ValueStringPointer())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating database",
-			fmt.Sprintf("%+v\n", err),
-		)
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	state, err := r.syncDatabaseState(ctx, db.UUID, plan.ClusterName.ValueStringPointer())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error syncing database",
-			fmt.Sprintf("%+v\n", err),
-		)
-		return
-	}
+	db, err := r.client.CreateDatabase(ctx, dbops.Database{Name: plan.Name.ValueString(), Comment: plan.Comment.ValueString()}, plan.ClusterName.ValueStringPointer())
+	tfutils.SaveCreated(ctx, resp, "Error creating database", db, err, func(db *dbops.Database) (*Database, diag.Diagnostics) {
+		var diags diag.Diagnostics
 
-	if state == nil {
-		resp.Diagnostics.AddError(
-			"Error syncing database",
-			"failed retrieving database after creation",
-		)
-		return
-	}
+		state, _, err := r.syncDatabaseState(ctx, db.UUID, plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			diags.AddError(
+				"Error syncing database",
+				tfutils.ErrorDetail(err),
+			)
+			return nil, diags
+		}
 
-	diags = resp.State.Set(ctx, state)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
+		if state == nil {
+			diags.AddError(
+				"Error syncing database",
+				"failed retrieving database after creation",
+			)
+			return nil, diags
+		}
+
+		state.QuerySettings = plan.QuerySettings
+		return state, diags
+	})
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -143,18 +150,25 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
-	state, err := r.syncDatabaseState(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer())
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, divergence, err := r.syncDatabaseState(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error syncing database",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
 
-	if state == nil {
+	if state == nil || tfutils.ReplicaDrift(ctx, &resp.Diagnostics, resp.Private, divergence, "database", state.Name.ValueString()) {
 		resp.State.RemoveResource(ctx)
 	} else {
+		state.QuerySettings = plan.QuerySettings
 		diags = resp.State.Set(ctx, state)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -164,7 +178,23 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	panic("unsupported")
+	// All other attributes require replacement, so only query_settings can change here.
+	var plan, state Database
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.QuerySettings = plan.QuerySettings
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -175,11 +205,17 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.DeleteDatabase(ctx, plan.UUID.ValueString(), plan.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting database",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -205,7 +241,7 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Cannot find database",
-				fmt.Sprintf("%+v\n", err),
+				tfutils.ErrorDetail(err),
 			)
 			return
 		}
@@ -221,16 +257,17 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 	}
 }
 
-// syncDatabaseState reads database settings from clickhouse and returns a DatabaseResourceModel
-func (r *Resource) syncDatabaseState(ctx context.Context, uuid string, clusterName *string) (*Database, error) {
+// syncDatabaseState reads database settings from clickhouse and returns a DatabaseResourceModel,
+// along with how the database differs between the replicas of its cluster, if it does.
+func (r *Resource) syncDatabaseState(ctx context.Context, uuid string, clusterName *string) (*Database, *dbops.ReplicaDivergence, error) {
 	db, err := r.client.GetDatabase(ctx, uuid, clusterName)
 	if err != nil {
-		return nil, errors.WithMessage(err, "cannot get database")
+		return nil, nil, errors.WithMessage(err, "cannot get database")
 	}
 
 	if db == nil {
 		// Database not found.
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	comment := types.StringNull()
@@ -245,5 +282,5 @@ func (r *Resource) syncDatabaseState(ctx context.Context, uuid string, clusterNa
 		Comment:     comment,
 	}
 
-	return state, nil
+	return state, db.Divergence, nil
 }
@@ -5,8 +5,9 @@ import (
 )
 
 type Database struct {
-	ClusterName types.String `tfsdk:"cluster_name"`
-	UUID        types.String `tfsdk:"uuid"`
-	Name        types.String `tfsdk:"name"`
-	Comment     types.String `tfsdk:"comment"`
+	ClusterName   types.String `tfsdk:"cluster_name"`
+	UUID          types.String `tfsdk:"uuid"`
+	Name          types.String `tfsdk:"name"`
+	Comment       types.String `tfsdk:"comment"`
+	QuerySettings types.Map    `tfsdk:"query_settings"`
 }
@@ -0,0 +1,555 @@
+package dictionary
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
+)
+
+//go:embed dictionary.md
+var dictionaryResourceDescription string
+
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+)
+
+// keywordRegexp matches the names of sources, layouts and their parameters.
+var keywordRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dictionary"
+}
+
+// requiredString is a required, non empty string attribute.
+func requiredString(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Required:    true,
+		Description: description,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+	}
+}
+
+// optionalString is an optional, non empty string attribute.
+func optionalString(description string, validators ...validator.String) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: description,
+		Validators:  append([]validator.String{stringvalidator.LengthAtLeast(1)}, validators...),
+	}
+}
+
+// keyword is the required name of a source or a layout.
+func keyword(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Required:    true,
+		Description: description,
+		Validators: []validator.String{
+			stringvalidator.RegexMatches(keywordRegexp, "must be a keyword made of letters, digits and underscores"),
+		},
+	}
+}
+
+// parameters is the optional map of parameters of a source or a layout.
+func parameters(description string) schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType: types.StringType,
+		Optional:    true,
+		Description: description,
+		Validators: []validator.Map{
+			mapvalidator.KeysAre(stringvalidator.RegexMatches(keywordRegexp, "must be a keyword made of letters, digits and underscores")),
+		},
+	}
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the dictionary into. If omitted, the provider `cluster_name` applies when set, otherwise the dictionary will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database of the dictionary",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the dictionary",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"lifetime_min": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum number of seconds between two updates of the dictionary. When `lifetime_max` is not set, the dictionary is created with `LIFETIME(lifetime_min)`. `0` disables the updates.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"lifetime_max": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of seconds between two updates of the dictionary. ClickHouse picks a random time between `lifetime_min` and `lifetime_max` for each update.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AlsoRequires(path.MatchRoot("lifetime_min")),
+				},
+			},
+			"range_min": optionalString(
+				"Attribute holding the start of the validity range of the values, for the `RANGE_HASHED` and `COMPLEX_KEY_RANGE_HASHED` layouts.",
+				stringvalidator.AlsoRequires(path.MatchRoot("range_max")),
+			),
+			"range_max": optionalString(
+				"Attribute holding the end of the validity range of the values, for the `RANGE_HASHED` and `COMPLEX_KEY_RANGE_HASHED` layouts.",
+				stringvalidator.AlsoRequires(path.MatchRoot("range_min")),
+			),
+			"settings": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Settings of the queries loading the dictionary, such as `format_csv_allow_single_quotes`.",
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"comment":        optionalString("Comment associated with the dictionary."),
+			"query_settings": tfutils.QuerySettingsAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"key": schema.ListNestedBlock{
+				Description: "An attribute of the primary key of the dictionary, in order. A single `UInt64` key suits the `FLAT`, `HASHED` and `RANGE_HASHED` layouts, other keys need a `COMPLEX_KEY_` layout.",
+				Validators: []validator.List{
+					listvalidator.IsRequired(),
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": requiredString("Name of the key attribute."),
+						"type": requiredString("Data type of the key attribute, such as `UInt64` or `String`."),
+					},
+				},
+			},
+			"attribute": schema.ListNestedBlock{
+				Description: "An attribute of the dictionary, in order.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name":       requiredString("Name of the attribute."),
+						"type":       requiredString("Data type of the attribute, such as `String` or `Nullable(Float64)`."),
+						"default":    optionalString("Value returned for keys missing from the dictionary, such as `''` or `0`. If omitted, the default value of the type applies."),
+						"expression": optionalString("Expression computing the attribute from the columns of the source, such as `concat(first_name, ' ', last_name)`."),
+						"hierarchical": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Whether the attribute holds the key of the parent, for hierarchical dictionaries.",
+						},
+						"injective": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Whether distinct keys always map to distinct values of the attribute, which lets ClickHouse optimize GROUP BY queries.",
+						},
+						"is_object_id": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Whether the attribute is the ObjectId of MongoDB documents.",
+						},
+					},
+				},
+			},
+			"source": schema.SingleNestedBlock{
+				Description: "Source of the data of the dictionary. Changing it replaces the dictionary atomically.",
+				Validators: []validator.Object{
+					objectvalidator.IsRequired(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"type":       keyword("Type of the source, such as `CLICKHOUSE`, `FILE`, `HTTP`, `MYSQL`, `POSTGRESQL`, `MONGODB`, `REDIS` or `EXECUTABLE`."),
+					"parameters": parameters("Parameters of the source, such as `host`, `port`, `db`, `table`, `query`, `url` or `format`. Numbers are written as is and other values as strings."),
+					"secret_parameters_wo": schema.MapAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Sensitive:   true,
+						WriteOnly:   true,
+						Description: "Secret parameters of the source, such as `user` and `password`. They are never stored in the state. Use this for Terraform/OpenTofu >= 1.11.",
+						Validators: []validator.Map{
+							mapvalidator.KeysAre(stringvalidator.RegexMatches(keywordRegexp, "must be a keyword made of letters, digits and underscores")),
+							mapvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("secret_parameters_wo_version")),
+						},
+					},
+					"secret_parameters_wo_version": schema.Int32Attribute{
+						Optional:    true,
+						Description: "Version of the secret_parameters_wo field. Bump this value to update the secret parameters of the dictionary.",
+						Validators: []validator.Int32{
+							int32validator.AlsoRequires(path.MatchRelative().AtParent().AtName("secret_parameters_wo")),
+						},
+					},
+				},
+			},
+			"layout": schema.SingleNestedBlock{
+				Description: "How the dictionary is stored in memory.",
+				Validators: []validator.Object{
+					objectvalidator.IsRequired(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"type":       keyword("Type of the layout, such as `FLAT`, `HASHED`, `COMPLEX_KEY_HASHED`, `RANGE_HASHED`, `CACHE` or `DIRECT`."),
+					"parameters": parameters("Parameters of the layout, such as `size_in_cells` for the `CACHE` layout."),
+				},
+			},
+		},
+		MarkdownDescription: dictionaryResourceDescription,
+	}
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config Dictionary
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := make(map[string]bool)
+	checkName := func(p path.Path, name types.String) {
+		if name.IsUnknown() || name.IsNull() {
+			return
+		}
+		if seen[name.ValueString()] {
+			resp.Diagnostics.AddAttributeError(
+				p,
+				"Duplicate Attribute",
+				fmt.Sprintf("Attribute %q is defined more than once among the keys and attributes.", name.ValueString()),
+			)
+		}
+		seen[name.ValueString()] = true
+	}
+	for idx, k := range config.Keys {
+		checkName(path.Root("key").AtListIndex(idx).AtName("name"), k.Name)
+	}
+	for idx, a := range config.Attributes {
+		checkName(path.Root("attribute").AtListIndex(idx).AtName("name"), a.Name)
+	}
+
+	if !config.LifetimeMin.IsNull() && !config.LifetimeMin.IsUnknown() && !config.LifetimeMax.IsNull() && !config.LifetimeMax.IsUnknown() &&
+		config.LifetimeMax.ValueInt64() < config.LifetimeMin.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("lifetime_max"),
+			"Invalid Lifetime",
+			"'lifetime_max' must be greater than or equal to 'lifetime_min'.",
+		)
+	}
+
+	if config.Source == nil {
+		return
+	}
+
+	var plain, secret map[string]string
+	resp.Diagnostics.Append(elements(ctx, config.Source.Parameters, &plain)...)
+	resp.Diagnostics.Append(elements(ctx, config.Source.SecretParametersWO, &secret)...)
+	for name := range secret {
+		if _, ok := plain[name]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("source").AtName("secret_parameters_wo"),
+				"Duplicate Source Parameter",
+				fmt.Sprintf("Parameter %q is set in both 'parameters' and 'secret_parameters_wo'.", name),
+			)
+		}
+	}
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan, config Dictionary
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only attributes are only populated in the config.
+	diags = req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dictionary, diags := plan.toDictionary(ctx, secretParameters(config))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := r.client.CreateDictionary(ctx, dictionary, plan.ClusterName.ValueStringPointer())
+	tfutils.SaveCreated(ctx, resp, "Error Creating ClickHouse Dictionary", created, err, func(*dbops.Dictionary) (Dictionary, diag.Diagnostics) {
+		return plan, nil
+	})
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state Dictionary
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dictionary, err := r.client.GetDictionary(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Dictionary",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	if dictionary == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.sync(*dictionary)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, config Dictionary
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only attributes are only populated in the config.
+	diags = req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dictionary, diags := plan.toDictionary(ctx, secretParameters(config))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updated, err := r.client.UpdateDictionary(ctx, dictionary, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Dictionary",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	if updated == nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Dictionary",
+			"failed retrieving dictionary after update",
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state Dictionary
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteDictionary(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Dictionary",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+}
+
+// secretParameters returns the write-only source parameters of the config.
+func secretParameters(config Dictionary) types.Map {
+	if config.Source == nil {
+		return types.MapNull(types.StringType)
+	}
+	return config.Source.SecretParametersWO
+}
+
+// sync updates d with the dictionary read from the server. The structure, layout and lifetime are
+// only known once the dictionary is loaded: they are kept as is until then.
+func (d *Dictionary) sync(server dbops.Dictionary) {
+	if len(server.Keys) > 0 {
+		d.Keys = syncKeys(d.Keys, server.Keys)
+
+		// The range bounds are not listed among the attributes.
+		bounds := map[string]bool{d.RangeMin.ValueString(): !d.RangeMin.IsNull(), d.RangeMax.ValueString(): !d.RangeMax.IsNull()}
+		d.Attributes = syncAttributes(d.Attributes, server.Attributes, bounds)
+	}
+
+	if server.Layout.Name != "" && d.Layout != nil && !sameLayout(d.Layout.Type.ValueString(), server.Layout.Name) {
+		d.Layout.Type = types.StringValue(server.Layout.Name)
+	}
+
+	// A single lifetime is stored as a range by the server: only track both bounds when they are set.
+	if server.LifetimeMin != nil && !d.LifetimeMin.IsNull() && !d.LifetimeMax.IsNull() {
+		d.LifetimeMin = types.Int64PointerValue(server.LifetimeMin)
+		d.LifetimeMax = types.Int64PointerValue(server.LifetimeMax)
+	}
+
+	d.Comment = types.StringNull()
+	if server.Comment != "" {
+		d.Comment = types.StringValue(server.Comment)
+	}
+}
+
+func syncKeys(state []Key, server []dbops.DictionaryAttribute) []Key {
+	known := make(map[string]Key)
+	for _, k := range state {
+		known[k.Name.ValueString()] = k
+	}
+
+	keys := make([]Key, 0, len(server))
+	for _, s := range server {
+		k := Key{Name: types.StringValue(s.Name), Type: types.StringValue(s.Type)}
+		if base, found := known[s.Name]; found && compact(base.Type.ValueString()) == compact(s.Type) {
+			k.Type = base.Type
+		}
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// syncAttributes returns the attributes read from the server, keeping the properties of the state
+// that are not read back. Attributes of the state named in keep are kept even if the server does
+// not list them.
+func syncAttributes(state []Attribute, server []dbops.DictionaryAttribute, keep map[string]bool) []Attribute {
+	known := make(map[string]Attribute)
+	for _, a := range state {
+		known[a.Name.ValueString()] = a
+	}
+	listed := make(map[string]bool)
+
+	attributes := make([]Attribute, 0, len(state))
+	for _, s := range server {
+		listed[s.Name] = true
+
+		a, found := known[s.Name]
+		if !found {
+			a = Attribute{
+				Default:      types.StringNull(),
+				Expression:   types.StringNull(),
+				Hierarchical: types.BoolNull(),
+				Injective:    types.BoolNull(),
+				IsObjectID:   types.BoolNull(),
+			}
+		}
+		a.Name = types.StringValue(s.Name)
+		if !found || compact(a.Type.ValueString()) != compact(s.Type) {
+			a.Type = types.StringValue(s.Type)
+		}
+		attributes = append(attributes, a)
+	}
+
+	for idx, a := range state {
+		if keep[a.Name.ValueString()] && !listed[a.Name.ValueString()] {
+			attributes = slices.Insert(attributes, min(idx, len(attributes)), a)
+		}
+	}
+
+	if len(attributes) == 0 && state == nil {
+		return nil
+	}
+
+	return attributes
+}
+
+// sameLayout reports whether a configured layout, such as `COMPLEX_KEY_HASHED`, is the layout
+// named by the server, such as `ComplexKeyHashed`.
+func sameLayout(configured, server string) bool {
+	return strings.EqualFold(strings.ReplaceAll(configured, "_", ""), server)
+}
+
+// compact removes whitespace, which ClickHouse adds to type arguments.
+func compact(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
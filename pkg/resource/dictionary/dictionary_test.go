@@ -0,0 +1,134 @@
+package dictionary_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/resourcebuilder"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/runner"
+)
+
+const (
+	resourceType = "clickhousedbops_dictionary"
+	resourceName = "foo"
+)
+
+func TestDictionary_acceptance(t *testing.T) {
+	clusterName := "cluster1"
+
+	checkNotExistsFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]string) (bool, error) {
+		dictionary, err := dbopsClient.GetDictionary(ctx, attrs["database_name"], attrs["name"], clusterName)
+		return dictionary != nil, err
+	}
+
+	checkAttributesFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]interface{}) error {
+		dictionary, err := dbopsClient.GetDictionary(ctx, attrs["database_name"].(string), attrs["name"].(string), clusterName)
+		if err != nil {
+			return err
+		}
+
+		if dictionary == nil {
+			return fmt.Errorf("dictionary %q was not found", attrs["name"])
+		}
+
+		// The structure is only known once the dictionary is loaded: only check the comment.
+		if dictionary.Comment != attrs["comment"].(string) {
+			return fmt.Errorf("expected comment %q, was %q", attrs["comment"], dictionary.Comment)
+		}
+
+		return nil
+	}
+
+	newDictionary := func(clusterName *string, databaseName string, lifetime int64) string {
+		database := resourcebuilder.New("clickhousedbops_database", "db").
+			WithStringAttribute("name", databaseName)
+		table := resourcebuilder.New("clickhousedbops_table", "countries").
+			WithResourceFieldReference("database_name", "clickhousedbops_database", "db", "name").
+			WithStringAttribute("name", "countries").
+			WithStringAttribute("engine", "MergeTree").
+			WithStringAttribute("order_by", "id").
+			WithBlock("column", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("name", "id").WithStringAttribute("type", "UInt64")
+			}).
+			WithBlock("column", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("name", "name").WithStringAttribute("type", "String")
+			}).
+			WithBlock("column", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("name", "parent_id").WithStringAttribute("type", "UInt64")
+			})
+		dictionary := resourcebuilder.New(resourceType, resourceName).
+			WithResourceFieldReference("database_name", "clickhousedbops_database", "db", "name").
+			WithStringAttribute("name", "countries_dict").
+			WithIntAttribute("lifetime_min", lifetime).
+			WithIntAttribute("lifetime_max", 2*lifetime).
+			WithStringAttribute("comment", fmt.Sprintf("reloaded every %d seconds", lifetime)).
+			WithBlock("key", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("name", "id").WithStringAttribute("type", "UInt64")
+			}).
+			WithBlock("attribute", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("name", "name").WithStringAttribute("type", "String").WithStringAttribute("default", "'unknown'")
+			}).
+			WithBlock("attribute", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("name", "parent_id").WithStringAttribute("type", "UInt64").WithBoolAttribute("hierarchical", true)
+			}).
+			WithBlock("source", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("type", "CLICKHOUSE").
+					WithMapAttribute("parameters", map[string]string{"db": databaseName, "table": "countries"}).
+					// Credentials of the test servers, see tests/docker-compose.yaml.
+					WithMapAttribute("secret_parameters_wo", map[string]string{"user": "default", "password": "test"}).
+					WithIntAttribute("secret_parameters_wo_version", 1)
+			}).
+			WithBlock("layout", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("type", "HASHED")
+			}).
+			WithDependsOn("clickhousedbops_table.countries")
+		if clusterName != nil {
+			database.WithStringAttribute("cluster_name", *clusterName)
+			table.WithStringAttribute("cluster_name", *clusterName)
+			dictionary.WithStringAttribute("cluster_name", *clusterName)
+		}
+
+		return dictionary.
+			AddDependency(table.Build()).
+			AddDependency(database.Build()).
+			Build()
+	}
+
+	tests := make([]runner.TestCase, 0)
+	for _, protocol := range []string{"native", "http"} {
+		databaseName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                  fmt.Sprintf("Create and replace dictionary using %s protocol on a single replica", protocol),
+			ChEnv:                 map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol:              protocol,
+			Resource:              newDictionary(nil, databaseName, 300),
+			UpdateResource:        new(newDictionary(nil, databaseName, 600)),
+			UpdateExpectNoReplace: true,
+			ResourceName:          resourceName,
+			ResourceAddress:       fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:    checkNotExistsFunc,
+			CheckAttributesFunc:   checkAttributesFunc,
+		})
+
+		databaseName = acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                  fmt.Sprintf("Create and replace dictionary using %s protocol on a cluster using replicated storage", protocol),
+			ChEnv:                 map[string]string{"CONFIGFILE": "config-replicated.xml"},
+			ClusterName:           &clusterName,
+			Protocol:              protocol,
+			Resource:              newDictionary(&clusterName, databaseName, 300),
+			UpdateResource:        new(newDictionary(&clusterName, databaseName, 600)),
+			UpdateExpectNoReplace: true,
+			ResourceName:          resourceName,
+			ResourceAddress:       fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:    checkNotExistsFunc,
+			CheckAttributesFunc:   checkAttributesFunc,
+		})
+	}
+
+	runner.RunTests(t, tests)
+}
@@ -0,0 +1,125 @@
+package dictionary
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+type Dictionary struct {
+	ClusterName   types.String `tfsdk:"cluster_name"`
+	DatabaseName  types.String `tfsdk:"database_name"`
+	Name          types.String `tfsdk:"name"`
+	Keys          []Key        `tfsdk:"key"`
+	Attributes    []Attribute  `tfsdk:"attribute"`
+	Source        *Source      `tfsdk:"source"`
+	Layout        *Layout      `tfsdk:"layout"`
+	LifetimeMin   types.Int64  `tfsdk:"lifetime_min"`
+	LifetimeMax   types.Int64  `tfsdk:"lifetime_max"`
+	RangeMin      types.String `tfsdk:"range_min"`
+	RangeMax      types.String `tfsdk:"range_max"`
+	Settings      types.Map    `tfsdk:"settings"`
+	Comment       types.String `tfsdk:"comment"`
+	QuerySettings types.Map    `tfsdk:"query_settings"`
+}
+
+type Key struct {
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+type Attribute struct {
+	Name         types.String `tfsdk:"name"`
+	Type         types.String `tfsdk:"type"`
+	Default      types.String `tfsdk:"default"`
+	Expression   types.String `tfsdk:"expression"`
+	Hierarchical types.Bool   `tfsdk:"hierarchical"`
+	Injective    types.Bool   `tfsdk:"injective"`
+	IsObjectID   types.Bool   `tfsdk:"is_object_id"`
+}
+
+type Source struct {
+	Type                      types.String `tfsdk:"type"`
+	Parameters                types.Map    `tfsdk:"parameters"`
+	SecretParametersWO        types.Map    `tfsdk:"secret_parameters_wo"`
+	SecretParametersWOVersion types.Int32  `tfsdk:"secret_parameters_wo_version"`
+}
+
+type Layout struct {
+	Type       types.String `tfsdk:"type"`
+	Parameters types.Map    `tfsdk:"parameters"`
+}
+
+// toDictionary returns the dictionary of the plan. Write-only attributes are only populated in the
+// config: secrets are the secret_parameters_wo of the config, merged into the source parameters.
+func (d Dictionary) toDictionary(ctx context.Context, secrets types.Map) (dbops.Dictionary, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	keys := make([]dbops.DictionaryAttribute, 0, len(d.Keys))
+	for _, k := range d.Keys {
+		keys = append(keys, dbops.DictionaryAttribute{
+			Name: k.Name.ValueString(),
+			Type: k.Type.ValueString(),
+		})
+	}
+
+	attributes := make([]dbops.DictionaryAttribute, 0, len(d.Attributes))
+	for _, a := range d.Attributes {
+		attributes = append(attributes, dbops.DictionaryAttribute{
+			Name:         a.Name.ValueString(),
+			Type:         a.Type.ValueString(),
+			Default:      a.Default.ValueStringPointer(),
+			Expression:   a.Expression.ValueStringPointer(),
+			Hierarchical: a.Hierarchical.ValueBool(),
+			Injective:    a.Injective.ValueBool(),
+			IsObjectID:   a.IsObjectID.ValueBool(),
+		})
+	}
+
+	dictionary := dbops.Dictionary{
+		Database:    d.DatabaseName.ValueString(),
+		Name:        d.Name.ValueString(),
+		Keys:        keys,
+		Attributes:  attributes,
+		LifetimeMin: d.LifetimeMin.ValueInt64Pointer(),
+		LifetimeMax: d.LifetimeMax.ValueInt64Pointer(),
+		RangeMin:    d.RangeMin.ValueStringPointer(),
+		RangeMax:    d.RangeMax.ValueStringPointer(),
+		Comment:     d.Comment.ValueString(),
+	}
+
+	diags.Append(elements(ctx, d.Settings, &dictionary.Settings)...)
+
+	if d.Source != nil {
+		dictionary.Source.Name = d.Source.Type.ValueString()
+		diags.Append(elements(ctx, d.Source.Parameters, &dictionary.Source.Parameters)...)
+
+		var secretParameters map[string]string
+		diags.Append(elements(ctx, secrets, &secretParameters)...)
+		if len(secretParameters) > 0 && dictionary.Source.Parameters == nil {
+			dictionary.Source.Parameters = make(map[string]string)
+		}
+		for name, value := range secretParameters {
+			dictionary.Source.Parameters[name] = value
+		}
+	}
+
+	if d.Layout != nil {
+		dictionary.Layout.Name = d.Layout.Type.ValueString()
+		diags.Append(elements(ctx, d.Layout.Parameters, &dictionary.Layout.Parameters)...)
+	}
+
+	return dictionary, diags
+}
+
+// elements sets target to the elements of m, leaving it nil when m is null.
+func elements(ctx context.Context, m types.Map, target *map[string]string) diag.Diagnostics {
+	if m.IsNull() || m.IsUnknown() {
+		return nil
+	}
+
+	return m.ElementsAs(ctx, target, false)
+}
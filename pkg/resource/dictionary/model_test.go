@@ -0,0 +1,148 @@
+package dictionary
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+func stringMap(values map[string]string) types.Map {
+	elements := make(map[string]attr.Value, len(values))
+	for k, v := range values {
+		elements[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+func attribute(name string, attrType string) Attribute {
+	return Attribute{
+		Name:         types.StringValue(name),
+		Type:         types.StringValue(attrType),
+		Default:      types.StringNull(),
+		Expression:   types.StringNull(),
+		Hierarchical: types.BoolNull(),
+		Injective:    types.BoolNull(),
+		IsObjectID:   types.BoolNull(),
+	}
+}
+
+func TestDictionary_toDictionary(t *testing.T) {
+	model := Dictionary{
+		DatabaseName: types.StringValue("db"),
+		Name:         types.StringValue("countries"),
+		Keys:         []Key{{Name: types.StringValue("id"), Type: types.StringValue("UInt64")}},
+		Attributes:   []Attribute{attribute("name", "String")},
+		Source: &Source{
+			Type:       types.StringValue("CLICKHOUSE"),
+			Parameters: stringMap(map[string]string{"table": "countries"}),
+			// Write-only attributes are null in the plan.
+			SecretParametersWO:        types.MapNull(types.StringType),
+			SecretParametersWOVersion: types.Int32Value(1),
+		},
+		Layout:      &Layout{Type: types.StringValue("HASHED"), Parameters: types.MapNull(types.StringType)},
+		LifetimeMin: types.Int64Value(300),
+		LifetimeMax: types.Int64Null(),
+		RangeMin:    types.StringNull(),
+		RangeMax:    types.StringNull(),
+		Settings:    types.MapNull(types.StringType),
+		Comment:     types.StringNull(),
+	}
+
+	got, diags := model.toDictionary(context.Background(), stringMap(map[string]string{"user": "reader", "password": "secret"}))
+	if diags.HasError() {
+		t.Fatalf("toDictionary() diags = %v", diags)
+	}
+
+	want := dbops.Dictionary{
+		Database:    "db",
+		Name:        "countries",
+		Keys:        []dbops.DictionaryAttribute{{Name: "id", Type: "UInt64"}},
+		Attributes:  []dbops.DictionaryAttribute{{Name: "name", Type: "String"}},
+		Source:      dbops.DictionaryFunction{Name: "CLICKHOUSE", Parameters: map[string]string{"table": "countries", "user": "reader", "password": "secret"}},
+		Layout:      dbops.DictionaryFunction{Name: "HASHED"},
+		LifetimeMin: new(int64(300)),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toDictionary() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestDictionary_sync(t *testing.T) {
+	withDefault := attribute("name", "LowCardinality(String)")
+	withDefault.Default = types.StringValue("'unknown'")
+	start := attribute("valid_from", "Date")
+	end := attribute("valid_to", "Date")
+
+	base := func() Dictionary {
+		return Dictionary{
+			Keys:        []Key{{Name: types.StringValue("id"), Type: types.StringValue("UInt64")}},
+			Attributes:  []Attribute{start, end, withDefault},
+			Layout:      &Layout{Type: types.StringValue("RANGE_HASHED"), Parameters: types.MapNull(types.StringType)},
+			LifetimeMin: types.Int64Value(300),
+			LifetimeMax: types.Int64Value(600),
+			RangeMin:    types.StringValue("valid_from"),
+			RangeMax:    types.StringValue("valid_to"),
+			Comment:     types.StringValue("prices"),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		server dbops.Dictionary
+		want   func(d Dictionary) Dictionary
+	}{
+		{
+			name: "Loaded without changes",
+			server: dbops.Dictionary{
+				Keys:        []dbops.DictionaryAttribute{{Name: "id", Type: "UInt64"}},
+				Attributes:  []dbops.DictionaryAttribute{{Name: "name", Type: "LowCardinality(String)"}},
+				Layout:      dbops.DictionaryFunction{Name: "RangeHashed"},
+				LifetimeMin: new(int64(300)),
+				LifetimeMax: new(int64(600)),
+				Comment:     "prices",
+			},
+			want: func(d Dictionary) Dictionary { return d },
+		},
+		{
+			name:   "Not loaded",
+			server: dbops.Dictionary{Comment: "prices"},
+			want:   func(d Dictionary) Dictionary { return d },
+		},
+		{
+			name: "Changed outside of terraform",
+			server: dbops.Dictionary{
+				Keys:        []dbops.DictionaryAttribute{{Name: "id", Type: "UInt64"}},
+				Attributes:  []dbops.DictionaryAttribute{{Name: "name", Type: "String"}, {Name: "code", Type: "String"}},
+				Layout:      dbops.DictionaryFunction{Name: "Hashed"},
+				LifetimeMin: new(int64(0)),
+				LifetimeMax: new(int64(0)),
+			},
+			want: func(d Dictionary) Dictionary {
+				name := withDefault
+				name.Type = types.StringValue("String")
+				d.Attributes = []Attribute{start, end, name, attribute("code", "String")}
+				d.Layout = &Layout{Type: types.StringValue("Hashed"), Parameters: types.MapNull(types.StringType)}
+				d.LifetimeMin = types.Int64Value(0)
+				d.LifetimeMax = types.Int64Value(0)
+				d.Comment = types.StringNull()
+				return d
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := base()
+			got.sync(tt.server)
+
+			want := tt.want(base())
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("sync() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
 )
 
 //go:embed grantprivilege.md
@@ -51,41 +52,22 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 }
 
 func resourceSchema(version int64) schema.Schema {
-	validPrivileges := make([]string, 0)
-
-	upstrGrts := grants.Parsed()
-
-	for privilege := range upstrGrts.Scopes {
-		validPrivileges = append(validPrivileges, privilege)
-	}
-
-	for alias := range upstrGrts.Aliases {
-		validPrivileges = append(validPrivileges, alias)
-	}
-
-	for groupName := range upstrGrts.Groups {
-		validPrivileges = append(validPrivileges, groupName)
-	}
-
 	return schema.Schema{
 		Version: version,
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				Description: "Name of the cluster to create the resource into. If omitted, the provider `cluster_name` applies when set, otherwise the resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"privilege_name": schema.StringAttribute{
 				Required:    true,
-				Description: "The privilege to grant, such as `CREATE DATABASE`, `SELECT`, etc. See https://clickhouse.com/docs/en/sql-reference/statements/grant#privileges.",
+				Description: "The privilege to grant, such as `CREATE DATABASE`, `SELECT`, etc. See https://clickhouse.com/docs/en/sql-reference/statements/grant#privileges. Must be one of the privileges listed in `system.privileges` on the server.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
-				Validators: []validator.String{
-					stringvalidator.OneOf(validPrivileges...),
-				},
 			},
 			"database_name": schema.StringAttribute{
 				Optional:    true,
@@ -140,6 +122,41 @@ func resourceSchema(version int64) schema.Schema {
 					),
 				},
 			},
+			"named_collection": schema.StringAttribute{
+				Optional:    true,
+				Description: "The named collection the privilege applies to, for NAMED_COLLECTION-scoped privileges such as `NAMED COLLECTION` or `SHOW NAMED COLLECTIONS`. Supports a trailing `*` prefix pattern. Defaults to all named collections if left null.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.NoneOf("*"),
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("database_name"),
+						path.MatchRoot("table_name"),
+						path.MatchRoot("column_name"),
+						path.MatchRoot("access_object"),
+						path.MatchRoot("table_engine"),
+					),
+				},
+			},
+			"table_engine": schema.StringAttribute{
+				Optional:    true,
+				Description: "The table engine the privilege applies to, for the TABLE_ENGINE-scoped `TABLE ENGINE` privilege (e.g. `MySQL`). Defaults to all table engines if left null.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.NoneOf("*"),
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("database_name"),
+						path.MatchRoot("table_name"),
+						path.MatchRoot("column_name"),
+						path.MatchRoot("access_object"),
+					),
+				},
+			},
 			"grantee_user_name": schema.StringAttribute{
 				Optional:    true,
 				Description: "Name of the `user` to grant privileges to.",
@@ -171,9 +188,9 @@ func resourceSchema(version int64) schema.Schema {
 			"grant_option": schema.BoolAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "If true, the grantee will be able to grant the same privileges to others.",
+				Description: "If true, the grantee will be able to grant the same privileges to others. Changing it updates the grant in place, without revoking the privilege.",
 				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
+					boolplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"current_grants": schema.BoolAttribute{
@@ -185,6 +202,7 @@ func resourceSchema(version int64) schema.Schema {
 					boolplanmodifier.RequiresReplace(),
 				},
 			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
 		},
 		MarkdownDescription: grantPrivilegeDescription,
 	}
@@ -227,7 +245,7 @@ func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _
 }
 
 // validateScope errors when target attributes are set on a privilege whose scope does not support them.
-func validateScope(config GrantPrivilege, diags *diag.Diagnostics) {
+func validateScope(config GrantPrivilege, catalog grants.Catalog, diags *diag.Diagnostics) {
 	if config.Privilege.IsUnknown() {
 		return
 	}
@@ -241,10 +259,8 @@ func validateScope(config GrantPrivilege, diags *diag.Diagnostics) {
 		)
 	}
 
-	upstrGrts := grants.Parsed()
-
 	// Aliases must be granted using their canonical name.
-	if alias := upstrGrts.Aliases[config.Privilege.ValueString()]; alias != "" {
+	if alias := catalog.Aliases[config.Privilege.ValueString()]; alias != "" {
 		diags.AddAttributeError(
 			path.Root("privilege_name"),
 			"Cannot use alias",
@@ -253,8 +269,17 @@ func validateScope(config GrantPrivilege, diags *diag.Diagnostics) {
 		return
 	}
 
+	if !catalog.Has(config.Privilege.ValueString()) {
+		diags.AddAttributeError(
+			path.Root("privilege_name"),
+			"Unknown Privilege",
+			fmt.Sprintf("%q is not a privilege supported by the ClickHouse server", config.Privilege.ValueString()),
+		)
+		return
+	}
+
 	// Only the target attributes supported by the privilege's scope may be set.
-	attrs, allAttrs, ok := grants.ScopeAttributesFor(config.Privilege.ValueString())
+	attrs, allAttrs, ok := catalog.ScopeAttributesFor(config.Privilege.ValueString())
 	if !ok {
 		diags.AddAttributeError(
 			path.Root("privilege_name"),
@@ -289,6 +314,8 @@ func validateScope(config GrantPrivilege, diags *diag.Diagnostics) {
 	checkAttr("table_name", attrs.Table, allAttrs.Table, !config.Table.IsNull())
 	checkAttr("column_name", attrs.Column, allAttrs.Column, !config.Column.IsNull())
 	checkAttr("access_object", attrs.AccessObject, allAttrs.AccessObject, !config.AccessObject.IsNull())
+	checkAttr("named_collection", attrs.NamedCollection, allAttrs.NamedCollection, !config.NamedCollection.IsNull())
+	checkAttr("table_engine", attrs.TableEngine, allAttrs.TableEngine, !config.TableEngine.IsNull())
 
 	if diags.HasError() {
 		return
@@ -296,12 +323,14 @@ func validateScope(config GrantPrivilege, diags *diag.Diagnostics) {
 
 	// Restricting a multi-family group privilege to a scope silently drops members of the other family.
 	requested := grants.ScopeAttributes{
-		Database:     !config.Database.IsNull(),
-		Table:        !config.Table.IsNull(),
-		Column:       !config.Column.IsNull(),
-		AccessObject: !config.AccessObject.IsNull(),
+		Database:        !config.Database.IsNull(),
+		Table:           !config.Table.IsNull(),
+		Column:          !config.Column.IsNull(),
+		AccessObject:    !config.AccessObject.IsNull(),
+		NamedCollection: !config.NamedCollection.IsNull(),
+		TableEngine:     !config.TableEngine.IsNull(),
 	}
-	if granted, folds := grants.FoldedMembers(config.Privilege.ValueString(), requested); folds {
+	if granted, folds := catalog.FoldedMembers(config.Privilege.ValueString(), requested); folds {
 		diags.AddAttributeWarning(
 			path.Root("privilege_name"),
 			"Privilege granted on a subset of its members",
@@ -317,7 +346,7 @@ func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConf
 		return
 	}
 
-	validateScope(config, &resp.Diagnostics)
+	validateScope(config, tfutils.PrivilegeCatalog(ctx, r.client), &resp.Diagnostics)
 }
 
 func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
@@ -329,12 +358,19 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 	var config GrantPrivilege
 	if !req.Config.Raw.IsNull() {
 		resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
-		validateScope(config, &resp.Diagnostics)
+		validateScope(config, tfutils.PrivilegeCatalog(ctx, r.client), &resp.Diagnostics)
 	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if r.client != nil && req.State.Raw.IsNull() && !req.Config.Raw.IsNull() {
+		r.validateAgainstServer(ctx, config, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Only check replicated storage when cluster_name is set, to avoid
 	// unnecessary connections (e.g. during terraform plan -refresh=false).
 	if r.client != nil && !config.ClusterName.IsNull() {
@@ -357,6 +393,73 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 	}
 }
 
+// validateAgainstServer checks a grant about to be created against the server, so that problems are
+// reported by `terraform plan` rather than halfway through an apply. Attributes still unknown are
+// skipped, and so is a check that fails to query the server. Problems are warnings, as the rest of
+// the plan might solve them.
+func (r *Resource) validateAgainstServer(ctx context.Context, config GrantPrivilege, diags *diag.Diagnostics) {
+	if !config.Database.IsUnknown() && !config.Table.IsUnknown() && !config.Column.IsUnknown() {
+		missing, err := r.client.MissingGrantTarget(ctx, config.Database.ValueStringPointer(), config.Table.ValueStringPointer(), config.Column.ValueStringPointer())
+		if err != nil {
+			diags.AddWarning(
+				"Could not check the grant target",
+				fmt.Sprintf("Skipping validation of the grant target. Error: %+v", err),
+			)
+		} else if missing != "" {
+			// ClickHouse accepts grants on objects that do not exist yet, which are often created
+			// in the same apply.
+			diags.AddWarning(
+				"Grant target does not exist",
+				fmt.Sprintf("The %s does not exist on the server. The privilege is granted anyway, but unless it is created before it is used, check the resource for typos.", missing),
+			)
+		}
+	}
+
+	if config.Privilege.IsUnknown() || config.GranteeUserName.IsUnknown() || config.GranteeRoleName.IsUnknown() {
+		return
+	}
+	for _, v := range []types.String{config.NamedCollection, config.TableEngine, config.AccessObject, config.Database, config.Table, config.Column} {
+		if v.IsUnknown() {
+			return
+		}
+	}
+
+	explanations, err := r.overlappingGrants(ctx, tfutils.PrivilegeCatalog(ctx, r.client), config)
+	if err != nil {
+		diags.AddWarning(
+			"Could not check for existing overlapping privileges",
+			fmt.Sprintf("Skipping validation, overlaps will be checked at apply time. Error: %+v", err),
+		)
+		return
+	}
+
+	// The broader grants might be revoked or narrowed by the same apply, so only the apply fails when
+	// they are still there.
+	if len(explanations) > 0 {
+		diags.AddWarning(
+			"Overlapping Privilege",
+			fmt.Sprintf("We found some privileges already granted to the same grantee that are overlapping with this resource:\n%s\n\nThe apply fails unless they are revoked or narrowed before this grant is created.", strings.Join(explanations, "\n")),
+		)
+	}
+}
+
+// overlappingGrants explains the privileges already granted to the grantee that cover the one in plan.
+func (r *Resource) overlappingGrants(ctx context.Context, catalog grants.Catalog, plan GrantPrivilege) ([]string, error) {
+	existing, err := r.client.GetAllGrantsForGrantee(ctx, plan.GranteeUserName.ValueStringPointer(), plan.GranteeRoleName.ValueStringPointer(), plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		return nil, err
+	}
+
+	explanations := make([]string, 0)
+	for _, e := range existing {
+		if overlaps(catalog, plan, e) {
+			explanations = append(explanations, explainOverlap(plan, e))
+		}
+	}
+
+	return explanations, nil
+}
+
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan GrantPrivilege
 	diags := req.Plan.Get(ctx, &plan)
@@ -365,19 +468,26 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
-	grant := plan.toGrant()
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	catalog := tfutils.PrivilegeCatalog(ctx, r.client)
+	grant := plan.toGrant(catalog)
 
 	createdGrant, err := r.client.GrantPrivilege(ctx, grant, plan.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating ClickHouse Privilege Grant",
-			"Could not create privilege grant, unexpected error: "+err.Error(),
+			tfutils.WithErrorHint("Could not create privilege grant, unexpected error: "+err.Error(), err),
 		)
 		return
 	}
 
 	if createdGrant == nil {
-		existing, err := r.client.GetAllGrantsForGrantee(ctx, grant.GranteeUserName, grant.GranteeRoleName, plan.ClusterName.ValueStringPointer())
+		explanations, err := r.overlappingGrants(ctx, catalog, plan)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error checking for existing overlapping privileges",
@@ -386,23 +496,10 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 			return
 		}
 
-		overlappingExplanations := make([]string, 0)
-		for _, e := range existing {
-			if overlaps(plan, e) {
-				// Prepare human-readable explanation of the overlap.
-				overlappingExplanations = append(overlappingExplanations, explainOverlap(plan, e))
-			}
-		}
-
-		if len(overlappingExplanations) > 0 {
-			details := fmt.Sprintf(`While trying to apply this resource, we found some privileges already granted to the same grantee that are overlapping with this resource:
-%s
-
-This is a configuration error that prevents further actions. Please note that these privileges might have been granted outside terraform.`, strings.Join(overlappingExplanations, "\n"))
-
+		if len(explanations) > 0 {
 			resp.Diagnostics.AddError(
 				"Overlapping Privilege",
-				details,
+				overlapDetails(explanations),
 			)
 			return
 		}
@@ -417,6 +514,7 @@ This is a configuration error that prevents further actions. Please note that th
 	state := toState(*createdGrant, plan.ClusterName)
 	// current_grants is config-only: ClickHouse does not return it, so carry it forward.
 	state.CurrentGrants = plan.CurrentGrants
+	state.QuerySettings = plan.QuerySettings
 
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
@@ -433,11 +531,17 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
-	grant, err := r.client.GetGrantPrivilege(ctx, new(state.toGrant()), state.ClusterName.ValueStringPointer())
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	grant, err := r.client.GetGrantPrivilege(ctx, new(state.toGrant(tfutils.PrivilegeCatalog(ctx, r.client))), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse Privilege Grant",
-			"Could not read privilege grant, unexpected error: "+err.Error(),
+			tfutils.WithErrorHint("Could not read privilege grant, unexpected error: "+err.Error(), err),
 		)
 		return
 	}
@@ -446,6 +550,7 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		newState := toState(*grant, state.ClusterName)
 		// current_grants is config-only: ClickHouse does not return it, so carry it forward.
 		newState.CurrentGrants = state.CurrentGrants
+		newState.QuerySettings = state.QuerySettings
 		diags = resp.State.Set(ctx, &newState)
 		resp.Diagnostics.Append(diags...)
 	} else {
@@ -453,8 +558,51 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 	}
 }
 
-func (r *Resource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
-	panic("Update of grant privilege resource is not supported")
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All other attributes require replacement, so only grant_option and query_settings can change here.
+	var plan, state GrantPrivilege
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.GrantOption.Equal(state.GrantOption) {
+		// Changing the grant option in place keeps the privilege granted throughout.
+		updated, err := r.client.UpdateGrantPrivilege(ctx, plan.toGrant(tfutils.PrivilegeCatalog(ctx, r.client)), plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse Privilege Grant",
+				tfutils.WithErrorHint("Could not update the grant option, unexpected error: "+err.Error(), err),
+			)
+			return
+		}
+		if updated == nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse Privilege Grant",
+				"The grant option was updated but the grant could not be found in system.grants anymore.",
+			)
+			return
+		}
+
+		state.GrantOption = types.BoolValue(updated.GrantOption)
+	}
+
+	state.QuerySettings = plan.QuerySettings
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -465,11 +613,17 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
-	err := r.client.RevokeGrantPrivilege(ctx, state.toGrant(), state.ClusterName.ValueStringPointer())
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.RevokeGrantPrivilege(ctx, state.toGrant(tfutils.PrivilegeCatalog(ctx, r.client)), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse Privilege Grant",
-			"Could not delete privilege grant, unexpected error: "+err.Error(),
+			tfutils.WithErrorHint("Could not delete privilege grant, unexpected error: "+err.Error(), err),
 		)
 		return
 	}
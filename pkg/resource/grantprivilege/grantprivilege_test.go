@@ -68,6 +68,14 @@ func TestGrantprivilege_acceptance(t *testing.T) {
 			accessObject = &s
 		}
 
+		var namedCollection, tableEngine *string
+		if attrs["named_collection"] != "" {
+			namedCollection = new(attrs["named_collection"])
+		}
+		if attrs["table_engine"] != "" {
+			tableEngine = new(attrs["table_engine"])
+		}
+
 		var granteeUserName, granteeRoleName *string
 		if granteeUser != "" {
 			granteeUserName = &granteeUser
@@ -83,6 +91,8 @@ func TestGrantprivilege_acceptance(t *testing.T) {
 			TableName:           table,
 			ColumnName:          column,
 			AccessObject:        accessObject,
+			NamedCollection:     namedCollection,
+			TableEngine:         tableEngine,
 			GranteeUserName:     granteeUserName,
 			GranteeRoleName:     granteeRoleName,
 		}
@@ -118,6 +128,14 @@ func TestGrantprivilege_acceptance(t *testing.T) {
 			accessObject = &s
 		}
 
+		var namedCollection, tableEngine *string
+		if attrs["named_collection"] != nil {
+			namedCollection = new(attrs["named_collection"].(string))
+		}
+		if attrs["table_engine"] != nil {
+			tableEngine = new(attrs["table_engine"].(string))
+		}
+
 		var granteeUserName, granteeRoleName *string
 		if attrs["grantee_user_name"] != nil {
 			granteeUserName = new(attrs["grantee_user_name"].(string))
@@ -144,6 +162,8 @@ func TestGrantprivilege_acceptance(t *testing.T) {
 			TableName:           table,
 			ColumnName:          column,
 			AccessObject:        accessObject,
+			NamedCollection:     namedCollection,
+			TableEngine:         tableEngine,
 			GranteeUserName:     granteeUserName,
 			GranteeRoleName:     granteeRoleName,
 			GrantOption:         grantOption,
@@ -178,6 +198,14 @@ func TestGrantprivilege_acceptance(t *testing.T) {
 			return fmt.Errorf("wrong value for access_object attribute")
 		}
 
+		if !nilcompare.NilCompare(grantprivilege.NamedCollection, attrs["named_collection"]) {
+			return fmt.Errorf("wrong value for named_collection attribute")
+		}
+
+		if !nilcompare.NilCompare(grantprivilege.TableEngine, attrs["table_engine"]) {
+			return fmt.Errorf("wrong value for table_engine attribute")
+		}
+
 		if !nilcompare.NilCompare(clusterName, attrs["cluster_name"]) {
 			return fmt.Errorf("wrong value for cluster_name attribute")
 		}
@@ -377,6 +405,36 @@ func TestGrantprivilege_acceptance(t *testing.T) {
 			CheckNotExistsFunc:  checkNotExistsFunc,
 			CheckAttributesFunc: checkAttributesFunc,
 		},
+		{
+			Name:     "Grant NAMED_COLLECTION-scoped privilege on named collection to role using Native protocol on a single replica",
+			ChEnv:    map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol: "native",
+			Resource: resourcebuilder.New(resourceType, resourceName).
+				WithStringAttribute("privilege_name", "NAMED COLLECTION").
+				WithStringAttribute("named_collection", "s3_*").
+				WithResourceFieldReference("grantee_role_name", "clickhousedbops_role", granteeRoleName, "name").
+				AddDependency(granteeRoleResource.Build()).
+				Build(),
+			ResourceName:        resourceName,
+			ResourceAddress:     fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:  checkNotExistsFunc,
+			CheckAttributesFunc: checkAttributesFunc,
+		},
+		{
+			Name:     "Grant TABLE ENGINE on table engine to role using Native protocol on a single replica",
+			ChEnv:    map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol: "native",
+			Resource: resourcebuilder.New(resourceType, resourceName).
+				WithStringAttribute("privilege_name", "TABLE ENGINE").
+				WithStringAttribute("table_engine", "MySQL").
+				WithResourceFieldReference("grantee_role_name", "clickhousedbops_role", granteeRoleName, "name").
+				AddDependency(granteeRoleResource.Build()).
+				Build(),
+			ResourceName:        resourceName,
+			ResourceAddress:     fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:  checkNotExistsFunc,
+			CheckAttributesFunc: checkAttributesFunc,
+		},
 		// Single replica, HTTP
 		{
 			Name:     "Grant privilege on single column to role using HTTP protocol on a single replica",
@@ -14,20 +14,25 @@ type GrantPrivilege struct {
 	Table           types.String `tfsdk:"table_name"`
 	Column          types.String `tfsdk:"column_name"`
 	AccessObject    types.String `tfsdk:"access_object"`
+	NamedCollection types.String `tfsdk:"named_collection"`
+	TableEngine     types.String `tfsdk:"table_engine"`
 	GranteeUserName types.String `tfsdk:"grantee_user_name"`
 	GranteeRoleName types.String `tfsdk:"grantee_role_name"`
 	GrantOption     types.Bool   `tfsdk:"grant_option"`
 	CurrentGrants   types.Bool   `tfsdk:"current_grants"`
+	QuerySettings   types.Map    `tfsdk:"query_settings"`
 }
 
-func (g GrantPrivilege) toGrant() dbops.GrantPrivilege {
+func (g GrantPrivilege) toGrant(catalog grants.Catalog) dbops.GrantPrivilege {
 	return dbops.GrantPrivilege{
 		AccessType:          g.Privilege.ValueString(),
-		ExpandedAccessTypes: grants.AllDescendants(grants.Parsed().Groups, g.Privilege.ValueString()),
+		ExpandedAccessTypes: catalog.Descendants(g.Privilege.ValueString()),
 		DatabaseName:        g.Database.ValueStringPointer(),
 		TableName:           g.Table.ValueStringPointer(),
 		ColumnName:          g.Column.ValueStringPointer(),
 		AccessObject:        g.AccessObject.ValueStringPointer(),
+		NamedCollection:     g.NamedCollection.ValueStringPointer(),
+		TableEngine:         g.TableEngine.ValueStringPointer(),
 		GranteeUserName:     g.GranteeUserName.ValueStringPointer(),
 		GranteeRoleName:     g.GranteeRoleName.ValueStringPointer(),
 		GrantOption:         g.GrantOption.ValueBool(),
@@ -41,11 +46,24 @@ func (g GrantPrivilege) asGrant() grants.Grant {
 		Database:     g.Database.ValueStringPointer(),
 		Table:        g.Table.ValueStringPointer(),
 		Column:       g.Column.ValueStringPointer(),
-		AccessObject: g.AccessObject.ValueStringPointer(),
+		AccessObject: g.parameter(),
 		GrantOption:  g.GrantOption.ValueBool(),
 	}
 }
 
+// parameter returns whichever of access_object, named_collection and table_engine is set; they all
+// map to the access_object column of system.grants.
+func (g GrantPrivilege) parameter() *string {
+	switch {
+	case !g.NamedCollection.IsNull():
+		return g.NamedCollection.ValueStringPointer()
+	case !g.TableEngine.IsNull():
+		return g.TableEngine.ValueStringPointer()
+	default:
+		return g.AccessObject.ValueStringPointer()
+	}
+}
+
 func toState(g dbops.GrantPrivilege, clusterName types.String) GrantPrivilege {
 	return GrantPrivilege{
 		ClusterName:     clusterName,
@@ -54,8 +72,11 @@ func toState(g dbops.GrantPrivilege, clusterName types.String) GrantPrivilege {
 		Table:           types.StringPointerValue(g.TableName),
 		Column:          types.StringPointerValue(g.ColumnName),
 		AccessObject:    types.StringPointerValue(g.AccessObject),
+		NamedCollection: types.StringPointerValue(g.NamedCollection),
+		TableEngine:     types.StringPointerValue(g.TableEngine),
 		GranteeUserName: types.StringPointerValue(g.GranteeUserName),
 		GranteeRoleName: types.StringPointerValue(g.GranteeRoleName),
 		GrantOption:     types.BoolValue(g.GrantOption),
+		QuerySettings:   types.MapNull(types.StringType),
 	}
 }
@@ -2,14 +2,15 @@ package grantprivilege
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
 )
 
 // overlaps reports whether an already-granted privilege covers the one in current.
-func overlaps(current GrantPrivilege, existing dbops.GrantPrivilege) bool {
-	return grants.Covers(existing.AsGrant(), current.asGrant())
+func overlaps(catalog grants.Catalog, current GrantPrivilege, existing dbops.GrantPrivilege) bool {
+	return catalog.Covers(existing.AsGrant(), current.asGrant())
 }
 
 func explainOverlap(current GrantPrivilege, existing dbops.GrantPrivilege) string {
@@ -53,3 +54,11 @@ func explainOverlap(current GrantPrivilege, existing dbops.GrantPrivilege) strin
 
 	return row
 }
+
+// overlapDetails is the detail of the diagnostic reporting overlapping privileges.
+func overlapDetails(explanations []string) string {
+	return fmt.Sprintf(`We found some privileges already granted to the same grantee that are overlapping with this resource:
+%s
+
+This is a configuration error that prevents further actions. Please note that these privileges might have been granted outside terraform.`, strings.Join(explanations, "\n"))
+}
@@ -41,6 +41,7 @@ func TestUpgradeState(t *testing.T) {
 				Privilege:       types.StringValue("SELECT"),
 				GranteeRoleName: types.StringValue("reader"),
 				CurrentGrants:   test.currentGrants,
+				QuerySettings:   types.MapNull(types.StringType),
 			})
 			require.False(t, diags.HasError(), diags.Errors())
 
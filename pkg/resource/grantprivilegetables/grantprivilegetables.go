@@ -0,0 +1,477 @@
+package grantprivilegetables
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pingcap/errors"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
+)
+
+//go:embed grantprivilegetables.md
+var grantPrivilegeTablesDescription string
+
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_grant_privilege_tables"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the resource into. If omitted, the provider `cluster_name` applies when set, otherwise the resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"privilege_name": schema.StringAttribute{
+				Required:    true,
+				Description: "The privilege to grant, such as `SELECT` or `INSERT`. It must be grantable on a table.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the database whose tables the privilege is granted on. Patterns are not supported.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"table_glob": schema.StringAttribute{
+				Optional:    true,
+				Description: "Shell pattern the names of the tables must match, such as `events_*`. `*` matches any sequence of characters, `?` any single character and `[...]` a character class.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ExactlyOneOf(
+						path.MatchRoot("table_glob"),
+						path.MatchRoot("table_regex"),
+					),
+				},
+			},
+			"table_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression, in the RE2 syntax, the whole name of the tables must match, such as `events_[0-9]{4}`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"grantee_user_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the `user` to grant privileges to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(
+						path.MatchRoot("grantee_user_name"),
+						path.MatchRoot("grantee_role_name"),
+					),
+				},
+			},
+			"grantee_role_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the `role` to grant privileges to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"grant_option": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "If true, the grantee will be able to grant the same privileges to others. Changing it updates the grants in place, without revoking the privilege.",
+			},
+			"tables": schema.SetAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The tables of `database_name` the privilege is granted on. Planned from the tables matching the pattern on the server.",
+			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
+		},
+		MarkdownDescription: grantPrivilegeTablesDescription,
+	}
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config GrantPrivilegeTables
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Database.IsUnknown() && strings.Contains(config.Database.ValueString(), "*") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("database_name"),
+			"Invalid Database Name",
+			"'database_name' must be the name of a single database, not a pattern.",
+		)
+	}
+
+	if !config.TableGlob.IsUnknown() && !config.TableRegex.IsUnknown() && (!config.TableGlob.IsNull() || !config.TableRegex.IsNull()) {
+		if _, err := tableMatcher(config.TableGlob.ValueStringPointer(), config.TableRegex.ValueStringPointer()); err != nil {
+			attr := "table_regex"
+			if !config.TableGlob.IsNull() {
+				attr = "table_glob"
+			}
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr),
+				"Invalid Table Pattern",
+				err.Error(),
+			)
+		}
+	}
+
+	if config.Privilege.IsUnknown() {
+		return
+	}
+
+	catalog := tfutils.PrivilegeCatalog(ctx, r.client)
+	privilege := config.Privilege.ValueString()
+
+	// Aliases must be granted using their canonical name.
+	if alias := catalog.Aliases[privilege]; alias != "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("privilege_name"),
+			"Cannot use alias",
+			fmt.Sprintf("%q is an alias for %q. Please use %q instead", privilege, alias, alias),
+		)
+		return
+	}
+
+	if !catalog.Has(privilege) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("privilege_name"),
+			"Unknown Privilege",
+			fmt.Sprintf("%q is not a privilege supported by the ClickHouse server", privilege),
+		)
+		return
+	}
+
+	if _, allAttrs, ok := catalog.ScopeAttributesFor(privilege); !ok || !allAttrs.Table {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("privilege_name"),
+			"Invalid Grant Privilege",
+			fmt.Sprintf("%q cannot be granted on a table", privilege),
+		)
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan GrantPrivilegeTables
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.Database.IsUnknown() || plan.TableGlob.IsUnknown() || plan.TableRegex.IsUnknown() {
+		return
+	}
+
+	ctx, diags := tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Planning the tables matching now, rather than leaving them unknown, shows the tables created
+	// since the last apply as a change.
+	tables, err := r.matchingTables(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Could not list the matching tables",
+			fmt.Sprintf("The tables will be listed at apply time. Error: %+v", err),
+		)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tables"), types.SetUnknown(types.StringType))...)
+		return
+	}
+
+	planned, diags := tfutils.StringSliceToSet(tables)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tables"), planned)...)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan GrantPrivilegeTables
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Saved even on failure, with the tables actually granted, so that the resource is tainted
+	// instead of leaving them behind.
+	if r.reconcile(ctx, nil, &plan, false, &resp.Diagnostics) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	}
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state GrantPrivilegeTables
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tables, diags := tfutils.SetToStringSlice(ctx, state.Tables)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	granted, err := r.grantedTables(ctx, state, tables)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Privilege Grants",
+			tfutils.WithErrorHint("Could not read privilege grants, unexpected error: "+err.Error(), err),
+		)
+		return
+	}
+
+	state.Tables, diags = tfutils.StringSliceToSet(granted)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state GrantPrivilegeTables
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, diags := tfutils.SetToStringSlice(ctx, state.Tables)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.reconcile(ctx, current, &plan, !plan.GrantOption.Equal(state.GrantOption), &resp.Diagnostics) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	}
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state GrantPrivilegeTables
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tables, diags := tfutils.SetToStringSlice(ctx, state.Tables)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	catalog := tfutils.PrivilegeCatalog(ctx, r.client)
+	for _, table := range tables {
+		err := r.client.RevokeGrantPrivilege(ctx, state.toGrant(catalog, table), state.ClusterName.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Deleting ClickHouse Privilege Grants",
+				tfutils.WithErrorHint("Could not revoke privilege grant, unexpected error: "+err.Error(), err),
+			)
+			return
+		}
+	}
+}
+
+// matchingTables lists the tables of the database matching the pattern of model.
+func (r *Resource) matchingTables(ctx context.Context, model GrantPrivilegeTables) ([]string, error) {
+	match, err := tableMatcher(model.TableGlob.ValueStringPointer(), model.TableRegex.ValueStringPointer())
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := r.client.ListTables(ctx, model.Database.ValueString(), model.ClusterName.ValueStringPointer())
+	if err != nil {
+		return nil, err
+	}
+
+	return matchingTables(tables, match), nil
+}
+
+// grantedTables returns the tables among candidates the privilege of model is granted on.
+func (r *Resource) grantedTables(ctx context.Context, model GrantPrivilegeTables, candidates []string) ([]string, error) {
+	catalog := tfutils.PrivilegeCatalog(ctx, r.client)
+
+	granted := make([]string, 0)
+	for _, table := range candidates {
+		grant, err := r.client.GetGrantPrivilege(ctx, new(model.toGrant(catalog, table)), model.ClusterName.ValueStringPointer())
+		if err != nil {
+			return nil, err
+		}
+		if grant != nil {
+			granted = append(granted, table)
+		}
+	}
+
+	return granted, nil
+}
+
+// reconcile grants and revokes the privilege until it is granted exactly on the tables planned, or on
+// the tables matching now when they were unknown at plan time. current are the tables the privilege
+// is granted on before the change. See tfutils.Reconcile.
+func (r *Resource) reconcile(ctx context.Context, current []string, plan *GrantPrivilegeTables, updateGrantOption bool, diags *diag.Diagnostics) bool {
+	var desired []string
+	if plan.Tables.IsUnknown() {
+		tables, err := r.matchingTables(ctx, *plan)
+		if err != nil {
+			diags.AddError(
+				"Error Listing ClickHouse Tables",
+				tfutils.ErrorDetail(err),
+			)
+			return false
+		}
+		desired = tables
+	} else {
+		tables, d := tfutils.SetToStringSlice(ctx, plan.Tables)
+		diags.Append(d...)
+		if diags.HasError() {
+			return false
+		}
+		desired = tables
+	}
+
+	tables := desired
+	ok := tfutils.Reconcile(diags, "ClickHouse Privilege Grants", func() error {
+		return r.applyTables(ctx, *plan, tfutils.DiffSets(current, desired, func(t string) string { return t }), updateGrantOption)
+	}, func() error {
+		candidates := slices.Concat(current, desired)
+		slices.Sort(candidates)
+		granted, err := r.grantedTables(ctx, *plan, slices.Compact(candidates))
+		tables = granted
+		return err
+	})
+	if !ok {
+		return false
+	}
+
+	set, d := tfutils.StringSliceToSet(tables)
+	diags.Append(d...)
+	plan.Tables = set
+
+	return !d.HasError()
+}
+
+func (r *Resource) applyTables(ctx context.Context, plan GrantPrivilegeTables, changes tfutils.SetChanges[string], updateGrantOption bool) error {
+	catalog := tfutils.PrivilegeCatalog(ctx, r.client)
+	clusterName := plan.ClusterName.ValueStringPointer()
+
+	for _, table := range changes.Add {
+		granted, err := r.client.GrantPrivilege(ctx, plan.toGrant(catalog, table), clusterName)
+		if err != nil {
+			return err
+		}
+		if granted == nil {
+			return errors.Errorf("the privilege on %s.%s is already covered by a broader grant to the grantee", plan.Database.ValueString(), table)
+		}
+	}
+
+	if updateGrantOption {
+		for _, table := range changes.Keep {
+			updated, err := r.client.UpdateGrantPrivilege(ctx, plan.toGrant(catalog, table), clusterName)
+			if err != nil {
+				return err
+			}
+			if updated == nil {
+				return errors.Errorf("the grant option was updated but the privilege on %s.%s could not be found in system.grants anymore", plan.Database.ValueString(), table)
+			}
+		}
+	}
+
+	for _, table := range changes.Remove {
+		if err := r.client.RevokeGrantPrivilege(ctx, plan.toGrant(catalog, table), clusterName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package grantprivilegetables_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/resourcebuilder"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/runner"
+)
+
+const (
+	resourceType = "clickhousedbops_grant_privilege_tables"
+	resourceName = "foo"
+
+	granteeUserName = "user1"
+
+	// The system database has the same tables on every server.
+	databaseName = "system"
+)
+
+// matchingTables are the tables of the system database matched by the patterns used below.
+var matchingTables = []string{"columns", "tables"}
+
+func TestGrantPrivilegeTables_acceptance(t *testing.T) {
+	catalog := grants.Parsed()
+
+	granteeUserResource := resourcebuilder.
+		New("clickhousedbops_user", granteeUserName).
+		WithStringAttribute("name", granteeUserName).
+		WithFunction("password_sha256_hash_wo", "sha256", "test").
+		WithIntAttribute("password_sha256_hash_wo_version", 1)
+
+	grantedTables := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, privilege string, user string) ([]string, error) {
+		granted := make([]string, 0)
+		for _, table := range matchingTables {
+			grant, err := dbopsClient.GetGrantPrivilege(ctx, &dbops.GrantPrivilege{
+				AccessType:          privilege,
+				ExpandedAccessTypes: catalog.Descendants(privilege),
+				DatabaseName:        new(databaseName),
+				TableName:           new(table),
+				GranteeUserName:     &user,
+			}, clusterName)
+			if err != nil {
+				return nil, err
+			}
+			if grant != nil {
+				granted = append(granted, table)
+			}
+		}
+		return granted, nil
+	}
+
+	checkNotExistsFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]string) (bool, error) {
+		privilege := attrs["privilege_name"]
+		if privilege == "" {
+			return false, fmt.Errorf("privilege_name attribute was not set")
+		}
+
+		granted, err := grantedTables(ctx, dbopsClient, clusterName, privilege, attrs["grantee_user_name"])
+		return len(granted) > 0, err
+	}
+
+	checkAttributesFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]interface{}) error {
+		privilege, _ := attrs["privilege_name"].(string)
+		user, _ := attrs["grantee_user_name"].(string)
+
+		tables, _ := attrs["tables"].([]interface{})
+		if len(tables) != len(matchingTables) {
+			return fmt.Errorf("expected tables to be %v, was %v", matchingTables, tables)
+		}
+
+		granted, err := grantedTables(ctx, dbopsClient, clusterName, privilege, user)
+		if err != nil {
+			return err
+		}
+		if len(granted) != len(matchingTables) {
+			return fmt.Errorf("expected %s to be granted on %v, was granted on %v", privilege, matchingTables, granted)
+		}
+
+		return nil
+	}
+
+	tests := []runner.TestCase{
+		{
+			Name:     "Grant on tables matching a glob using Native protocol on a single replica",
+			ChEnv:    map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol: "native",
+			Resource: resourcebuilder.New(resourceType, resourceName).
+				WithStringAttribute("privilege_name", "SELECT").
+				WithStringAttribute("database_name", databaseName).
+				WithStringAttribute("table_glob", "[ct][oa][lb][ul][em][ns]*").
+				WithResourceFieldReference("grantee_user_name", "clickhousedbops_user", granteeUserName, "name").
+				AddDependency(granteeUserResource.Build()).
+				Build(),
+			ResourceName:        resourceName,
+			ResourceAddress:     fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:  checkNotExistsFunc,
+			CheckAttributesFunc: checkAttributesFunc,
+		},
+		{
+			Name:     "Grant on tables matching a regex using HTTP protocol on a single replica",
+			ChEnv:    map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol: "http",
+			Resource: resourcebuilder.New(resourceType, resourceName).
+				WithStringAttribute("privilege_name", "SELECT").
+				WithStringAttribute("database_name", databaseName).
+				WithStringAttribute("table_regex", "columns|tables").
+				WithResourceFieldReference("grantee_user_name", "clickhousedbops_user", granteeUserName, "name").
+				WithBoolAttribute("grant_option", true).
+				AddDependency(granteeUserResource.Build()).
+				Build(),
+			ResourceName:        resourceName,
+			ResourceAddress:     fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:  checkNotExistsFunc,
+			CheckAttributesFunc: checkAttributesFunc,
+		},
+	}
+
+	runner.RunTests(t, tests)
+}
@@ -0,0 +1,34 @@
+package grantprivilegetables
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/grants"
+)
+
+type GrantPrivilegeTables struct {
+	ClusterName     types.String `tfsdk:"cluster_name"`
+	Privilege       types.String `tfsdk:"privilege_name"`
+	Database        types.String `tfsdk:"database_name"`
+	TableGlob       types.String `tfsdk:"table_glob"`
+	TableRegex      types.String `tfsdk:"table_regex"`
+	GranteeUserName types.String `tfsdk:"grantee_user_name"`
+	GranteeRoleName types.String `tfsdk:"grantee_role_name"`
+	GrantOption     types.Bool   `tfsdk:"grant_option"`
+	Tables          types.Set    `tfsdk:"tables"`
+	QuerySettings   types.Map    `tfsdk:"query_settings"`
+}
+
+// toGrant returns the grant of the privilege on a single table of the database.
+func (g GrantPrivilegeTables) toGrant(catalog grants.Catalog, table string) dbops.GrantPrivilege {
+	return dbops.GrantPrivilege{
+		AccessType:          g.Privilege.ValueString(),
+		ExpandedAccessTypes: catalog.Descendants(g.Privilege.ValueString()),
+		DatabaseName:        g.Database.ValueStringPointer(),
+		TableName:           &table,
+		GranteeUserName:     g.GranteeUserName.ValueStringPointer(),
+		GranteeRoleName:     g.GranteeRoleName.ValueStringPointer(),
+		GrantOption:         g.GrantOption.ValueBool(),
+	}
+}
@@ -0,0 +1,45 @@
+package grantprivilegetables
+
+import (
+	"path"
+	"regexp"
+
+	"github.com/pingcap/errors"
+)
+
+// tableMatcher returns a function reporting whether a table name matches glob, or regex when glob is
+// nil. Regular expressions must match the whole name.
+func tableMatcher(glob *string, regex *string) (func(string) bool, error) {
+	if glob != nil {
+		if _, err := path.Match(*glob, ""); err != nil {
+			return nil, errors.WithMessage(err, "invalid glob")
+		}
+		return func(name string) bool {
+			matched, _ := path.Match(*glob, name)
+			return matched
+		}, nil
+	}
+
+	if regex == nil {
+		return nil, errors.New("either a glob or a regular expression must be set")
+	}
+
+	re, err := regexp.Compile(`^(?:` + *regex + `)$`)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid regular expression")
+	}
+
+	return re.MatchString, nil
+}
+
+// matchingTables returns the tables whose name matches.
+func matchingTables(tables []string, match func(string) bool) []string {
+	ret := make([]string, 0)
+	for _, t := range tables {
+		if match(t) {
+			ret = append(ret, t)
+		}
+	}
+
+	return ret
+}
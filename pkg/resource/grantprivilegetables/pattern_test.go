@@ -0,0 +1,72 @@
+package grantprivilegetables
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_tableMatcher(t *testing.T) {
+	tables := []string{"events", "events_1", "events_2", "events_2024", "old_events_1", "users"}
+
+	tests := []struct {
+		name    string
+		glob    *string
+		regex   *string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Prefix glob",
+			glob: new("events_*"),
+			want: []string{"events_1", "events_2", "events_2024"},
+		},
+		{
+			name: "Single character glob",
+			glob: new("events_?"),
+			want: []string{"events_1", "events_2"},
+		},
+		{
+			name: "Character class glob",
+			glob: new("events_[2-9]*"),
+			want: []string{"events_2", "events_2024"},
+		},
+		{
+			name:  "Regex matches the whole name",
+			regex: new(`events_\d`),
+			want:  []string{"events_1", "events_2"},
+		},
+		{
+			name:  "Regex alternation is anchored",
+			regex: new("users|events"),
+			want:  []string{"events", "users"},
+		},
+		{
+			name:    "Invalid glob",
+			glob:    new("events_["),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid regex",
+			regex:   new("events_("),
+			wantErr: true,
+		},
+		{
+			name:    "No pattern",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := tableMatcher(tt.glob, tt.regex)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tableMatcher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := matchingTables(tables, match); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchingTables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/rolegraph"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
 )
 
 //go:embed grantrole.md
@@ -44,7 +46,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				Description: "Name of the cluster to create the resource into. If omitted, the provider `cluster_name` applies when set, otherwise the resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -87,19 +89,37 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 			"admin_option": schema.BoolAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "If true, the grantee will be able to grant `role_name` to other `users` or `roles`.",
+				Description: "If true, the grantee will be able to grant `role_name` to other `users` or `roles`. Changing it updates the grant in place, without revoking the role.",
 				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
+					boolplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
 		},
 		MarkdownDescription: grantResourceDescription,
 	}
 }
 
 func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var removed []rolegraph.Edge
+	if r.client != nil && !req.State.Raw.IsNull() {
+		var state GrantRole
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if edge := state.roleEdge(); edge != nil {
+			removed = append(removed, *edge)
+
+			// If the entire plan is null, the resource is planned for destruction: its grant no longer
+			// counts toward the role hierarchy.
+			if req.Plan.Raw.IsNull() {
+				r.client.PlanRoleGrants(state.ClusterName.ValueStringPointer(), "revoke "+edge.Role+" from "+edge.Grantee.Name, dbops.RoleGrantPlan{Removed: removed}, nil)
+			}
+		}
+	}
+
 	if req.Plan.Raw.IsNull() {
-		// If the entire plan is null, the resource is planned for destruction.
 		return
 	}
 
@@ -111,6 +131,18 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 			return
 		}
 
+		// Granting a role to another role must not make the hierarchy circular. The grant of the
+		// state is revoked when the resource is replaced.
+		if edge := config.roleEdge(); edge != nil && (len(removed) == 0 || removed[0].Role != edge.Role || removed[0].Grantee != edge.Grantee) {
+			tfutils.RejectRoleCycle(ctx, &resp.Diagnostics, r.client, config.ClusterName.ValueStringPointer(), "grant "+edge.Role+" to "+edge.Grantee.Name, dbops.RoleGrantPlan{
+				Removed: removed,
+				Added:   []rolegraph.Edge{*edge},
+			})
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
 		// Only check replicated storage when cluster_name is set, to avoid
 		// unnecessary connections (e.g. during terraform plan -refresh=false).
 		if !config.ClusterName.IsNull() {
@@ -150,13 +182,19 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Validate that the grantee (user or role) exists before attempting to grant
 	if !plan.GranteeUserName.IsNull() {
 		user, err := r.client.FindUserByName(ctx, plan.GranteeUserName.ValueString(), plan.ClusterName.ValueStringPointer())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Validating Grantee User",
-				fmt.Sprintf("%+v\n", err),
+				tfutils.ErrorDetail(err),
 			)
 			return
 		}
@@ -172,7 +210,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Validating Grantee Role",
-				fmt.Sprintf("%+v\n", err),
+				tfutils.ErrorDetail(err),
 			)
 			return
 		}
@@ -196,7 +234,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating ClickHouse Role Grant",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -216,6 +254,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		GranteeUserName: types.StringPointerValue(createdGrant.GranteeUserName),
 		GranteeRoleName: types.StringPointerValue(createdGrant.GranteeRoleName),
 		AdminOption:     types.BoolValue(createdGrant.AdminOption),
+		QuerySettings:   plan.QuerySettings,
 	}
 
 	diags = resp.State.Set(ctx, state)
@@ -233,11 +272,17 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	grant, err := r.client.GetGrantRole(ctx, state.RoleName.ValueString(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse Role Grant",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -256,7 +301,50 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	panic("Update of grant resource is not supported")
+	// All other attributes require replacement, so only admin_option and query_settings can change here.
+	var plan, state GrantRole
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.AdminOption.Equal(state.AdminOption) {
+		// Changing the admin option in place keeps the role granted throughout.
+		grant := dbops.GrantRole{
+			RoleName:        plan.RoleName.ValueString(),
+			GranteeUserName: plan.GranteeUserName.ValueStringPointer(),
+			GranteeRoleName: plan.GranteeRoleName.ValueStringPointer(),
+			AdminOption:     plan.AdminOption.ValueBool(),
+		}
+
+		updated, err := r.client.UpdateGrantRole(ctx, grant, plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse Role Grant",
+				tfutils.ErrorDetail(err),
+			)
+			return
+		}
+
+		state.AdminOption = types.BoolValue(updated.AdminOption)
+	}
+
+	state.QuerySettings = plan.QuerySettings
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -267,11 +355,17 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.RevokeGrantRole(ctx, state.RoleName.ValueString(), state.GranteeUserName.ValueStringPointer(), state.GranteeRoleName.ValueStringPointer(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse Role Grant",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -2,6 +2,8 @@ package grantrole
 
 import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/rolegraph"
 )
 
 type GrantRole struct {
@@ -10,4 +12,18 @@ type GrantRole struct {
 	GranteeUserName types.String `tfsdk:"grantee_user_name"`
 	GranteeRoleName types.String `tfsdk:"grantee_role_name"`
 	AdminOption     types.Bool   `tfsdk:"admin_option"`
+	QuerySettings   types.Map    `tfsdk:"query_settings"`
+}
+
+// roleEdge returns the grant of the role to another role, or nil when the grantee is a user or is not
+// known yet.
+func (g GrantRole) roleEdge() *rolegraph.Edge {
+	if g.GranteeRoleName.IsNull() || g.GranteeRoleName.IsUnknown() || g.RoleName.IsUnknown() {
+		return nil
+	}
+
+	return &rolegraph.Edge{
+		Role:    g.RoleName.ValueString(),
+		Grantee: rolegraph.Node{Name: g.GranteeRoleName.ValueString(), Kind: rolegraph.KindRole},
+	}
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
 )
 
 // maskFingerprintKey names the private-state entry holding the hash of the server's UPDATE clause at the last write.
@@ -127,6 +128,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					int64validator.AtLeast(0),
 				},
 			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
 		},
 		MarkdownDescription: maskingPolicyDescription,
 	}
@@ -149,7 +151,16 @@ func (r *Resource) ConfigValidators(_ context.Context) []resource.ConfigValidato
 }
 
 func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
-	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		if r.client != nil {
+			tfutils.RequireCapability(ctx, &resp.Diagnostics, r.client, "masking policies", func(f dbops.CapabilityFlags) bool {
+				return f.MaskingPolicies
+			})
+		}
 		return
 	}
 
@@ -174,6 +185,12 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	ctx, diags := tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	mp, diags := plan.toDBOps(ctx)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -189,7 +206,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 			)
 			return
 		}
-		resp.Diagnostics.AddError("Error Creating ClickHouse Masking Policy", "Could not create masking policy, unexpected error: "+err.Error())
+		resp.Diagnostics.AddError("Error Creating ClickHouse Masking Policy", tfutils.WithErrorHint("Could not create masking policy, unexpected error: "+err.Error(), err))
 		return
 	}
 
@@ -201,6 +218,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 
 	state.Masks = plan.Masks
 	state.WhereExpression = plan.WhereExpression
+	state.QuerySettings = plan.QuerySettings
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 	resp.Diagnostics.Append(resp.Private.SetKey(ctx, maskFingerprintKey, []byte(created.AssignmentsHash))...)
 }
@@ -212,9 +230,15 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
+	ctx, diags := tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	result, err := r.client.GetMaskingPolicyByID(ctx, state.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error Reading ClickHouse Masking Policy", "Could not read masking policy, unexpected error: "+err.Error())
+		resp.Diagnostics.AddError("Error Reading ClickHouse Masking Policy", tfutils.WithErrorHint("Could not read masking policy, unexpected error: "+err.Error(), err))
 		return
 	}
 
@@ -263,6 +287,12 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		return
 	}
 
+	ctx, diags := tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	mp, diags := plan.toDBOps(ctx)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -274,7 +304,7 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 
 	updated, err := r.client.UpdateMaskingPolicy(ctx, mp)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Updating ClickHouse Masking Policy", "Could not update masking policy, unexpected error: "+err.Error())
+		resp.Diagnostics.AddError("Error Updating ClickHouse Masking Policy", tfutils.WithErrorHint("Could not update masking policy, unexpected error: "+err.Error(), err))
 		return
 	}
 
@@ -286,6 +316,7 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 
 	newState.Masks = plan.Masks
 	newState.WhereExpression = plan.WhereExpression
+	newState.QuerySettings = plan.QuerySettings
 	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
 	resp.Diagnostics.Append(resp.Private.SetKey(ctx, maskFingerprintKey, []byte(updated.AssignmentsHash))...)
 }
@@ -297,9 +328,15 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
+	ctx, diags := tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.DeleteMaskingPolicy(ctx, state.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error Deleting ClickHouse Masking Policy", "Could not delete masking policy, unexpected error: "+err.Error())
+		resp.Diagnostics.AddError("Error Deleting ClickHouse Masking Policy", tfutils.WithErrorHint("Could not delete masking policy, unexpected error: "+err.Error(), err))
 		return
 	}
 }
@@ -322,7 +359,7 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 		result, err = r.client.GetMaskingPolicy(ctx, &dbops.MaskingPolicy{Database: parts[0], Table: parts[1], Name: parts[2]})
 	}
 	if err != nil {
-		resp.Diagnostics.AddError("Error Reading ClickHouse Masking Policy", "Could not read masking policy, unexpected error: "+err.Error())
+		resp.Diagnostics.AddError("Error Reading ClickHouse Masking Policy", tfutils.WithErrorHint("Could not read masking policy, unexpected error: "+err.Error(), err))
 		return
 	}
 	if result == nil {
@@ -333,6 +370,7 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 	var state MaskingPolicy
 	resp.Diagnostics.Append(state.fromDBOps(result)...)
 	state.Masks = types.MapNull(types.StringType)
+	state.QuerySettings = types.MapNull(types.StringType)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 	resp.Diagnostics.Append(resp.Private.SetKey(ctx, maskFingerprintKey, []byte(result.AssignmentsHash))...)
 }
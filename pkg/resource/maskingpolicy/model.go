@@ -22,6 +22,7 @@ type MaskingPolicy struct {
 	GranteeNames     types.Set    `tfsdk:"grantee_names"`
 	GranteeAllExcept types.Set    `tfsdk:"grantee_all_except"`
 	Priority         types.Int64  `tfsdk:"priority"`
+	QuerySettings    types.Map    `tfsdk:"query_settings"`
 }
 
 func (m *MaskingPolicy) toDBOps(ctx context.Context) (dbops.MaskingPolicy, diag.Diagnostics) {
@@ -0,0 +1,438 @@
+package materializedview
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
+)
+
+//go:embed materializedview.md
+var materializedViewResourceDescription string
+
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_materialized_view"
+}
+
+// replacedString is an optional attribute that can only be set when the view is created.
+func replacedString(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: description,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+	}
+}
+
+// interval is an optional time interval of the refresh schedule.
+func interval(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: description,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+	}
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the view into. If omitted, the provider `cluster_name` applies when set, otherwise the view will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database of the view",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the view",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"query": schema.StringAttribute{
+				Required:    true,
+				Description: "SELECT query of the view. Changing it alters the view in place when `to_table_name` is set, and recreates it otherwise.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"to_database_name": replacedString("Database of `to_table_name`. Defaults to `database_name`."),
+			"to_table_name":    replacedString("Name of the existing table the view writes to. Exactly one of `to_table_name` and `engine` must be set."),
+			"engine":           replacedString("Engine of the inner table storing the data of the view, with its arguments, such as `SummingMergeTree`. Exactly one of `to_table_name` and `engine` must be set."),
+			"order_by":         replacedString("Sorting key of the inner table, such as `(tenant_id, day)`."),
+			"partition_by":     replacedString("Partitioning key of the inner table, such as `toYYYYMM(day)`."),
+			"populate": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to fill the inner table with the existing data of the source table when the view is created. Cannot be used with `to_table_name` or `refresh`.",
+			},
+			"definer": schema.StringAttribute{
+				Optional:    true,
+				Description: "User whose privileges are used to run the query, or `CURRENT_USER`. Requires `sql_security` to be `DEFINER`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"sql_security": schema.StringAttribute{
+				Optional:    true,
+				Description: "Whose privileges are used to run the query: `DEFINER` or `NONE`. If omitted, the server default applies.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("DEFINER", "NONE"),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comment associated with the view.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"refresh": schema.SingleNestedBlock{
+				Description: "Refresh schedule of a refreshable materialized view. Adding or removing it recreates the view.",
+				Attributes: map[string]schema.Attribute{
+					"every":         interval("Refresh the view at fixed times, such as `1 HOUR` or `1 DAY`. Exactly one of `every` and `after` must be set."),
+					"after":         interval("Refresh the view this long after the previous refresh completed, such as `30 MINUTE`. Exactly one of `every` and `after` must be set."),
+					"offset":        interval("Delay of each refresh after the times set by `every`, such as `2 HOUR`."),
+					"randomize_for": interval("Random delay added to each refresh, such as `10 MINUTE`."),
+					"depends_on": schema.ListAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "Refreshable materialized views, as `name` or `database.name`, that must be refreshed before this one.",
+						Validators: []validator.List{
+							listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+						},
+					},
+					"append": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Whether each refresh appends its rows to the target table instead of replacing its content. Changing it recreates the view.",
+					},
+				},
+			},
+		},
+		MarkdownDescription: materializedViewResourceDescription,
+	}
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config MaterializedView
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.ToTableName.IsNull() == config.Engine.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("to_table_name"),
+			"Invalid Materialized View Definition",
+			"Exactly one of 'to_table_name' and 'engine' must be set.",
+		)
+	}
+
+	for attr, requires := range map[string]struct {
+		value    bool
+		required string
+		missing  bool
+	}{
+		"to_database_name": {!config.ToDatabaseName.IsNull(), "to_table_name", config.ToTableName.IsNull()},
+		"order_by":         {!config.OrderBy.IsNull(), "engine", config.Engine.IsNull()},
+		"partition_by":     {!config.PartitionBy.IsNull(), "engine", config.Engine.IsNull()},
+	} {
+		if requires.value && requires.missing {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr),
+				"Invalid Materialized View Definition",
+				fmt.Sprintf("%q can only be set with %q.", attr, requires.required),
+			)
+		}
+	}
+
+	if config.Populate.ValueBool() && (!config.ToTableName.IsNull() || config.Refresh != nil) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("populate"),
+			"Invalid Materialized View Definition",
+			"'populate' cannot be used with 'to_table_name' or 'refresh'.",
+		)
+	}
+
+	if !config.Definer.IsNull() && !config.SQLSecurity.IsUnknown() && config.SQLSecurity.ValueString() != "DEFINER" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("sql_security"),
+			"Invalid SQL Security",
+			"'sql_security' must be \"DEFINER\" when 'definer' is set.",
+		)
+	}
+
+	if config.Refresh != nil {
+		if config.Refresh.Every.IsNull() == config.Refresh.After.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("refresh").AtName("every"),
+				"Invalid Refresh Schedule",
+				"Exactly one of 'every' and 'after' must be set.",
+			)
+		}
+		if !config.Refresh.Offset.IsNull() && !config.Refresh.After.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("refresh").AtName("offset"),
+				"Invalid Refresh Schedule",
+				"'offset' can only be set with 'every'.",
+			)
+		}
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state MaterializedView
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Query.IsUnknown() && !plan.Query.Equal(state.Query) {
+		ctx, diags := tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		same := false
+		if r.client != nil {
+			same, _ = tfutils.SameQuery(ctx, &resp.Diagnostics, r.client, plan.DatabaseName.ValueString(), plan.Query.ValueString(), state.Query.ValueString())
+		}
+
+		switch {
+		case same:
+			// Only the formatting changed: keep the query of the state.
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("query"), state.Query)...)
+		case plan.ToTableName.IsNull():
+			// The query of a view with an inner table cannot be altered.
+			resp.RequiresReplace.Append(path.Root("query"))
+		}
+	}
+
+	switch {
+	case (plan.Refresh == nil) != (state.Refresh == nil):
+		resp.RequiresReplace.Append(path.Root("refresh"))
+	case plan.Refresh != nil && !plan.Refresh.Append.Equal(state.Refresh.Append):
+		resp.RequiresReplace.Append(path.Root("refresh").AtName("append"))
+	}
+
+	if len(resp.RequiresReplace) > 0 && state.ToTableName.IsNull() {
+		resp.Diagnostics.AddWarning(
+			"Materialized View Will Be Recreated",
+			fmt.Sprintf("The materialized view %s.%s cannot be changed in place: it is dropped and created again, and the data stored in its inner table is lost.", state.DatabaseName.ValueString(), state.Name.ValueString()),
+		)
+	}
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan MaterializedView
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	view, diags := plan.toMaterializedView(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := r.client.CreateMaterializedView(ctx, view, plan.ClusterName.ValueStringPointer())
+	tfutils.SaveCreated(ctx, resp, "Error Creating ClickHouse Materialized View", created, err, func(*dbops.MaterializedView) (MaterializedView, diag.Diagnostics) {
+		return plan, nil
+	})
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state MaterializedView
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	view, err := r.client.GetMaterializedView(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Materialized View",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	if view == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Keep the configured form of the query if it only differs from the server one in formatting, or
+	// if they cannot be compared.
+	if same, ok := tfutils.SameQuery(ctx, &resp.Diagnostics, r.client, state.DatabaseName.ValueString(), state.Query.ValueString(), view.Query); ok && !same {
+		state.Query = types.StringValue(view.Query)
+	}
+
+	// The server applies defaults when these are not configured: only track them when they are.
+	if !state.SQLSecurity.IsNull() {
+		state.SQLSecurity = types.StringPointerValue(view.SQLSecurity)
+	}
+	if !state.Definer.IsNull() && state.Definer.ValueString() != "CURRENT_USER" {
+		state.Definer = types.StringPointerValue(view.Definer)
+	}
+
+	state.Comment = types.StringNull()
+	if view.Comment != "" {
+		state.Comment = types.StringValue(view.Comment)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state MaterializedView
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, diags := state.toMaterializedView(ctx)
+	resp.Diagnostics.Append(diags...)
+	desired, diags := plan.toMaterializedView(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updated, err := r.client.UpdateMaterializedView(ctx, current, desired, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Materialized View",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	if updated == nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Materialized View",
+			"failed retrieving materialized view after update",
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state MaterializedView
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteView(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Materialized View",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,140 @@
+package materializedview_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/resourcebuilder"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/runner"
+)
+
+const (
+	resourceType = "clickhousedbops_materialized_view"
+	resourceName = "foo"
+)
+
+func TestMaterializedView_acceptance(t *testing.T) {
+	clusterName := "cluster1"
+
+	checkNotExistsFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]string) (bool, error) {
+		view, err := dbopsClient.GetMaterializedView(ctx, attrs["database_name"], attrs["name"], clusterName)
+		return view != nil, err
+	}
+
+	checkAttributesFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]interface{}) error {
+		view, err := dbopsClient.GetMaterializedView(ctx, attrs["database_name"].(string), attrs["name"].(string), clusterName)
+		if err != nil {
+			return err
+		}
+
+		if view == nil {
+			return fmt.Errorf("materialized view %q was not found", attrs["name"])
+		}
+
+		// The server formats the query: only check the aggregate that changes between steps.
+		for _, aggregate := range []string{"count()", "sum(amount)"} {
+			if strings.Contains(attrs["query"].(string), aggregate) != strings.Contains(view.Query, aggregate) {
+				return fmt.Errorf("query %q does not match %q", view.Query, attrs["query"])
+			}
+		}
+
+		return nil
+	}
+
+	// With an inner table, the query reads from a table of the database of the view without naming
+	// the database, as a change of query would replace the view.
+	newView := func(clusterName *string, databaseName string, aggregate string, inner bool) string {
+		table := func(name string, engine string, columns ...string) string {
+			builder := resourcebuilder.New("clickhousedbops_table", name).
+				WithResourceFieldReference("database_name", "clickhousedbops_database", "db", "name").
+				WithStringAttribute("name", name).
+				WithStringAttribute("engine", engine).
+				WithStringAttribute("order_by", "id")
+			if clusterName != nil {
+				builder.WithStringAttribute("cluster_name", *clusterName)
+			}
+			for _, c := range columns {
+				builder.WithBlock("column", func(b *resourcebuilder.BlockBuilder) {
+					b.WithStringAttribute("name", c).WithStringAttribute("type", "UInt64")
+				})
+			}
+			return builder.Build()
+		}
+
+		database := resourcebuilder.New("clickhousedbops_database", "db").
+			WithStringAttribute("name", databaseName)
+		view := resourcebuilder.New(resourceType, resourceName).
+			WithResourceFieldReference("database_name", "clickhousedbops_database", "db", "name").
+			WithStringAttribute("name", "totals_mv").
+			WithStringAttribute("sql_security", "DEFINER").
+			WithStringAttribute("definer", "CURRENT_USER").
+			WithDependsOn("clickhousedbops_table.events").
+			AddDependency(table("events", "MergeTree", "id", "amount"))
+		if inner {
+			view.WithStringAttribute("query", fmt.Sprintf("SELECT id, %s AS total FROM events GROUP BY id", aggregate)).
+				WithStringAttribute("engine", "SummingMergeTree").
+				WithStringAttribute("order_by", "id")
+		} else {
+			view.WithStringAttribute("query", fmt.Sprintf("SELECT id, %s AS total FROM `%s`.events GROUP BY id", aggregate, databaseName)).
+				WithResourceFieldReference("to_table_name", "clickhousedbops_table", "totals", "name").
+				AddDependency(table("totals", "SummingMergeTree", "id", "total"))
+		}
+		if clusterName != nil {
+			database.WithStringAttribute("cluster_name", *clusterName)
+			view.WithStringAttribute("cluster_name", *clusterName)
+		}
+
+		return view.AddDependency(database.Build()).Build()
+	}
+
+	tests := make([]runner.TestCase, 0)
+	for _, protocol := range []string{"native", "http"} {
+		databaseName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                  fmt.Sprintf("Create and alter materialized view using %s protocol on a single replica", protocol),
+			ChEnv:                 map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol:              protocol,
+			Resource:              newView(nil, databaseName, "count()", false),
+			UpdateResource:        new(newView(nil, databaseName, "sum(amount)", false)),
+			UpdateExpectNoReplace: true,
+			ResourceName:          resourceName,
+			ResourceAddress:       fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:    checkNotExistsFunc,
+			CheckAttributesFunc:   checkAttributesFunc,
+		})
+
+		databaseName = acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                  fmt.Sprintf("Create and alter materialized view using %s protocol on a cluster using replicated storage", protocol),
+			ChEnv:                 map[string]string{"CONFIGFILE": "config-replicated.xml"},
+			ClusterName:           &clusterName,
+			Protocol:              protocol,
+			Resource:              newView(&clusterName, databaseName, "count()", false),
+			UpdateResource:        new(newView(&clusterName, databaseName, "sum(amount)", false)),
+			UpdateExpectNoReplace: true,
+			ResourceName:          resourceName,
+			ResourceAddress:       fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:    checkNotExistsFunc,
+			CheckAttributesFunc:   checkAttributesFunc,
+		})
+
+		databaseName = acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                fmt.Sprintf("Create materialized view with an inner table reading an unqualified table using %s protocol", protocol),
+			ChEnv:               map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol:            protocol,
+			Resource:            newView(nil, databaseName, "count()", true),
+			ResourceName:        resourceName,
+			ResourceAddress:     fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:  checkNotExistsFunc,
+			CheckAttributesFunc: checkAttributesFunc,
+		})
+	}
+
+	runner.RunTests(t, tests)
+}
@@ -0,0 +1,82 @@
+package materializedview
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+type MaterializedView struct {
+	ClusterName    types.String `tfsdk:"cluster_name"`
+	DatabaseName   types.String `tfsdk:"database_name"`
+	Name           types.String `tfsdk:"name"`
+	Query          types.String `tfsdk:"query"`
+	ToDatabaseName types.String `tfsdk:"to_database_name"`
+	ToTableName    types.String `tfsdk:"to_table_name"`
+	Engine         types.String `tfsdk:"engine"`
+	OrderBy        types.String `tfsdk:"order_by"`
+	PartitionBy    types.String `tfsdk:"partition_by"`
+	Populate       types.Bool   `tfsdk:"populate"`
+	Refresh        *Refresh     `tfsdk:"refresh"`
+	Definer        types.String `tfsdk:"definer"`
+	SQLSecurity    types.String `tfsdk:"sql_security"`
+	Comment        types.String `tfsdk:"comment"`
+	QuerySettings  types.Map    `tfsdk:"query_settings"`
+}
+
+type Refresh struct {
+	Every        types.String `tfsdk:"every"`
+	After        types.String `tfsdk:"after"`
+	Offset       types.String `tfsdk:"offset"`
+	RandomizeFor types.String `tfsdk:"randomize_for"`
+	DependsOn    types.List   `tfsdk:"depends_on"`
+	Append       types.Bool   `tfsdk:"append"`
+}
+
+func (m MaterializedView) toMaterializedView(ctx context.Context) (dbops.MaterializedView, diag.Diagnostics) {
+	view := dbops.MaterializedView{
+		Database:    m.DatabaseName.ValueString(),
+		Name:        m.Name.ValueString(),
+		Query:       m.Query.ValueString(),
+		Engine:      m.Engine.ValueStringPointer(),
+		OrderBy:     m.OrderBy.ValueStringPointer(),
+		PartitionBy: m.PartitionBy.ValueStringPointer(),
+		Populate:    m.Populate.ValueBool(),
+		Definer:     m.Definer.ValueStringPointer(),
+		SQLSecurity: m.SQLSecurity.ValueStringPointer(),
+		Comment:     m.Comment.ValueString(),
+	}
+
+	if !m.ToTableName.IsNull() {
+		view.ToTable = m.ToTableName.ValueString()
+		view.ToDatabase = m.DatabaseName.ValueString()
+		if !m.ToDatabaseName.IsNull() {
+			view.ToDatabase = m.ToDatabaseName.ValueString()
+		}
+	}
+
+	if m.Refresh != nil {
+		refresh := dbops.RefreshSchedule{
+			Kind:         "EVERY",
+			Interval:     m.Refresh.Every.ValueString(),
+			Offset:       m.Refresh.Offset.ValueStringPointer(),
+			RandomizeFor: m.Refresh.RandomizeFor.ValueStringPointer(),
+			Append:       m.Refresh.Append.ValueBool(),
+		}
+		if !m.Refresh.After.IsNull() {
+			refresh.Kind = "AFTER"
+			refresh.Interval = m.Refresh.After.ValueString()
+		}
+		if !m.Refresh.DependsOn.IsNull() {
+			if diags := m.Refresh.DependsOn.ElementsAs(ctx, &refresh.DependsOn, false); diags.HasError() {
+				return dbops.MaterializedView{}, diags
+			}
+		}
+		view.Refresh = &refresh
+	}
+
+	return view, nil
+}
@@ -0,0 +1,86 @@
+package materializedview
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+func TestMaterializedView_toMaterializedView(t *testing.T) {
+	base := MaterializedView{
+		DatabaseName:   types.StringValue("db"),
+		Name:           types.StringValue("mv"),
+		Query:          types.StringValue("SELECT 1"),
+		ToDatabaseName: types.StringNull(),
+		ToTableName:    types.StringNull(),
+		Engine:         types.StringNull(),
+		OrderBy:        types.StringNull(),
+		PartitionBy:    types.StringNull(),
+		Populate:       types.BoolNull(),
+		Definer:        types.StringNull(),
+		SQLSecurity:    types.StringNull(),
+		Comment:        types.StringNull(),
+	}
+
+	tests := []struct {
+		name  string
+		model func(m MaterializedView) MaterializedView
+		want  dbops.MaterializedView
+	}{
+		{
+			name: "Target table defaults to the database of the view",
+			model: func(m MaterializedView) MaterializedView {
+				m.ToTableName = types.StringValue("totals")
+				return m
+			},
+			want: dbops.MaterializedView{Database: "db", Name: "mv", Query: "SELECT 1", ToDatabase: "db", ToTable: "totals"},
+		},
+		{
+			name: "Target table in another database",
+			model: func(m MaterializedView) MaterializedView {
+				m.ToDatabaseName = types.StringValue("reports")
+				m.ToTableName = types.StringValue("totals")
+				return m
+			},
+			want: dbops.MaterializedView{Database: "db", Name: "mv", Query: "SELECT 1", ToDatabase: "reports", ToTable: "totals"},
+		},
+		{
+			name: "Refresh after",
+			model: func(m MaterializedView) MaterializedView {
+				m.Engine = types.StringValue("Memory")
+				m.Refresh = &Refresh{
+					Every:        types.StringNull(),
+					After:        types.StringValue("30 MINUTE"),
+					Offset:       types.StringNull(),
+					RandomizeFor: types.StringNull(),
+					DependsOn:    types.ListValueMust(types.StringType, []attr.Value{types.StringValue("db.other")}),
+					Append:       types.BoolNull(),
+				}
+				return m
+			},
+			want: dbops.MaterializedView{
+				Database: "db",
+				Name:     "mv",
+				Query:    "SELECT 1",
+				Engine:   new("Memory"),
+				Refresh:  &dbops.RefreshSchedule{Kind: "AFTER", Interval: "30 MINUTE", DependsOn: []string{"db.other"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := tt.model(base).toMaterializedView(context.Background())
+			if diags.HasError() {
+				t.Fatalf("toMaterializedView() diags = %v", diags)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("toMaterializedView() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
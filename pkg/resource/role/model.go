@@ -5,7 +5,8 @@ import (
 )
 
 type Role struct {
-	ClusterName types.String `tfsdk:"cluster_name"`
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
+	ClusterName   types.String `tfsdk:"cluster_name"`
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	QuerySettings types.Map    `tfsdk:"query_settings"`
 }
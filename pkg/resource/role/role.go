@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -15,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
 )
 
 //go:embed role.md
@@ -44,7 +46,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				Description: "Name of the cluster to create the resource into. If omitted, the provider `cluster_name` applies when set, otherwise the resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -57,6 +59,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 				Required:    true,
 				Description: "Name of the role",
 			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
 		},
 		MarkdownDescription: roleResourceDescription,
 	}
@@ -115,26 +118,21 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
-	createdRole, err := r.client.CreateRole(ctx, dbops.Role{Name: plan.Name.ValueString()}, plan.ClusterName.ValueStringPointer())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating ClickHouse Role",
-			fmt.Sprintf("%+v\n", err),
-		)
-		return
-	}
-
-	state := Role{
-		ClusterName: plan.ClusterName,
-		ID:          types.StringValue(createdRole.ID),
-		Name:        types.StringValue(createdRole.Name),
-	}
-
-	diags = resp.State.Set(ctx, state)
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	createdRole, err := r.client.CreateRole(ctx, dbops.Role{Name: plan.Name.ValueString()}, plan.ClusterName.ValueStringPointer())
+	tfutils.SaveCreated(ctx, resp, "Error Creating ClickHouse Role", createdRole, err, func(createdRole *dbops.Role) (Role, diag.Diagnostics) {
+		return Role{
+			ClusterName:   plan.ClusterName,
+			ID:            types.StringValue(createdRole.ID),
+			Name:          types.StringValue(createdRole.Name),
+			QuerySettings: plan.QuerySettings,
+		}, nil
+	})
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -145,16 +143,22 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	role, err := r.client.GetRole(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse Role",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
 
-	if role != nil {
+	if role != nil && !tfutils.ReplicaDrift(ctx, &resp.Diagnostics, resp.Private, role.Divergence, "role", role.Name) {
 		state.Name = types.StringValue(role.Name)
 
 		diags = resp.State.Set(ctx, &state)
@@ -178,20 +182,31 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		return
 	}
 
-	// Settings profile is changed.
-	role, err := r.client.UpdateRole(ctx, dbops.Role{
-		ID:   state.ID.ValueString(),
-		Name: plan.Name.ValueString(),
-	}, plan.ClusterName.ValueStringPointer())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating ClickHouse Role",
-			fmt.Sprintf("%+v\n", err),
-		)
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	state.Name = types.StringValue(role.Name)
+	state.QuerySettings = plan.QuerySettings
+
+	// query_settings only live in the state, the name is the only thing to change in ClickHouse.
+	if !plan.Name.Equal(state.Name) {
+		role, err := r.client.UpdateRole(ctx, dbops.Role{
+			ID:   state.ID.ValueString(),
+			Name: plan.Name.ValueString(),
+		}, plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse Role",
+				tfutils.ErrorDetail(err),
+			)
+			return
+		}
+
+		state.Name = types.StringValue(role.Name)
+	}
+
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
@@ -204,11 +219,17 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.DeleteRole(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse Role",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -234,7 +255,7 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Cannot find role",
-				fmt.Sprintf("%+v\n", err),
+				tfutils.ErrorDetail(err),
 			)
 			return
 		}
@@ -0,0 +1,47 @@
+package rolemembers
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+type RoleMembers struct {
+	ClusterName   types.String `tfsdk:"cluster_name"`
+	RoleName      types.String `tfsdk:"role_name"`
+	Members       []Member     `tfsdk:"member"`
+	QuerySettings types.Map    `tfsdk:"query_settings"`
+}
+
+type Member struct {
+	UserName    types.String `tfsdk:"user_name"`
+	RoleName    types.String `tfsdk:"role_name"`
+	AdminOption types.Bool   `tfsdk:"admin_option"`
+}
+
+func (m RoleMembers) toGrants() []dbops.GrantRole {
+	grants := make([]dbops.GrantRole, 0, len(m.Members))
+	for _, member := range m.Members {
+		grants = append(grants, dbops.GrantRole{
+			RoleName:        m.RoleName.ValueString(),
+			GranteeUserName: member.UserName.ValueStringPointer(),
+			GranteeRoleName: member.RoleName.ValueStringPointer(),
+			AdminOption:     member.AdminOption.ValueBool(),
+		})
+	}
+
+	return grants
+}
+
+func toMembers(grants []dbops.GrantRole) []Member {
+	members := make([]Member, 0, len(grants))
+	for _, g := range grants {
+		members = append(members, Member{
+			UserName:    types.StringPointerValue(g.GranteeUserName),
+			RoleName:    types.StringPointerValue(g.GranteeRoleName),
+			AdminOption: types.BoolValue(g.AdminOption),
+		})
+	}
+
+	return members
+}
@@ -0,0 +1,46 @@
+package rolemembers
+
+import (
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
+)
+
+// memberKey identifies a grantee: users and roles live in different namespaces.
+func memberKey(g dbops.GrantRole) string {
+	if g.GranteeUserName != nil {
+		return "user:" + *g.GranteeUserName
+	}
+	if g.GranteeRoleName != nil {
+		return "role:" + *g.GranteeRoleName
+	}
+	return ""
+}
+
+// memberChanges are the statements turning the current members of a role into the desired ones.
+type memberChanges struct {
+	// grant are the desired members not granted the role yet.
+	grant []dbops.GrantRole
+	// update are the members whose admin option differs.
+	update []dbops.GrantRole
+	// revoke are the members granted the role that are not desired.
+	revoke []dbops.GrantRole
+}
+
+// diffMembers compares the members of a role read from system.role_grants with the desired ones.
+func diffMembers(current []dbops.GrantRole, desired []dbops.GrantRole) memberChanges {
+	sets := tfutils.DiffSets(current, desired, memberKey)
+
+	currentByKey := make(map[string]dbops.GrantRole, len(current))
+	for _, g := range current {
+		currentByKey[memberKey(g)] = g
+	}
+
+	changes := memberChanges{grant: sets.Add, revoke: sets.Remove}
+	for _, g := range sets.Keep {
+		if currentByKey[memberKey(g)].AdminOption != g.AdminOption {
+			changes.update = append(changes.update, g)
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,71 @@
+package rolemembers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+func userMember(name string, adminOption bool) dbops.GrantRole {
+	return dbops.GrantRole{RoleName: "r", GranteeUserName: &name, AdminOption: adminOption}
+}
+
+func roleMember(name string, adminOption bool) dbops.GrantRole {
+	return dbops.GrantRole{RoleName: "r", GranteeRoleName: &name, AdminOption: adminOption}
+}
+
+func Test_diffMembers(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []dbops.GrantRole
+		desired []dbops.GrantRole
+		want    memberChanges
+	}{
+		{
+			name:    "In sync",
+			current: []dbops.GrantRole{userMember("alice", false), roleMember("ops", true)},
+			desired: []dbops.GrantRole{roleMember("ops", true), userMember("alice", false)},
+		},
+		{
+			name:    "Missing member",
+			current: []dbops.GrantRole{userMember("alice", false)},
+			desired: []dbops.GrantRole{userMember("alice", false), userMember("bob", true)},
+			want:    memberChanges{grant: []dbops.GrantRole{userMember("bob", true)}},
+		},
+		{
+			name:    "Unmanaged member",
+			current: []dbops.GrantRole{userMember("alice", false), userMember("mallory", false)},
+			desired: []dbops.GrantRole{userMember("alice", false)},
+			want:    memberChanges{revoke: []dbops.GrantRole{userMember("mallory", false)}},
+		},
+		{
+			name:    "Admin option changed",
+			current: []dbops.GrantRole{userMember("alice", true)},
+			desired: []dbops.GrantRole{userMember("alice", false)},
+			want:    memberChanges{update: []dbops.GrantRole{userMember("alice", false)}},
+		},
+		{
+			name:    "User and role with the same name",
+			current: []dbops.GrantRole{userMember("ops", false)},
+			desired: []dbops.GrantRole{roleMember("ops", false)},
+			want: memberChanges{
+				grant:  []dbops.GrantRole{roleMember("ops", false)},
+				revoke: []dbops.GrantRole{userMember("ops", false)},
+			},
+		},
+		{
+			name:    "No members",
+			current: []dbops.GrantRole{userMember("alice", false)},
+			want:    memberChanges{revoke: []dbops.GrantRole{userMember("alice", false)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffMembers(tt.current, tt.desired)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffMembers() want = %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package rolemembers
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/rolegraph"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
+)
+
+//go:embed rolemembers.md
+var roleMembersResourceDescription string
+
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+	_ resource.ResourceWithImportState    = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role_members"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the resource into. If omitted, the provider `cluster_name` applies when set, otherwise the resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the role whose members are managed.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"member": schema.SetNestedBlock{
+				Description: "A user or role granted `role_name`. Any grantee of `role_name` not listed is revoked.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"user_name": schema.StringAttribute{
+							Optional:    true,
+							Description: "Name of the `user` to grant `role_name` to.",
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(
+									path.MatchRelative().AtParent().AtName("user_name"),
+									path.MatchRelative().AtParent().AtName("role_name"),
+								),
+							},
+						},
+						"role_name": schema.StringAttribute{
+							Optional:    true,
+							Description: "Name of the `role` to grant `role_name` to.",
+						},
+						"admin_option": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+							Description: "If true, the member will be able to grant `role_name` to other `users` or `roles`.",
+						},
+					},
+				},
+			},
+		},
+		MarkdownDescription: roleMembersResourceDescription,
+	}
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config RoleMembers
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The set only collapses identical members: the same grantee with a different admin option
+	// would be granted twice.
+	seen := make(map[string]bool)
+	for _, member := range config.Members {
+		if member.UserName.IsUnknown() || member.RoleName.IsUnknown() {
+			continue
+		}
+
+		key := memberKey(dbops.GrantRole{GranteeUserName: member.UserName.ValueStringPointer(), GranteeRoleName: member.RoleName.ValueStringPointer()})
+		if key == "" {
+			continue
+		}
+		if seen[key] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("member"),
+				"Duplicate Role Member",
+				fmt.Sprintf("%s is listed more than once. Each user or role can only be a member once.", strings.Replace(key, ":", " ", 1)),
+			)
+		}
+		seen[key] = true
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.client == nil {
+		return
+	}
+
+	// The grants of the role in the state are replaced, or revoked when the resource is destroyed.
+	var replaced []string
+	if !req.State.Raw.IsNull() {
+		var state RoleMembers
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		replaced = append(replaced, state.RoleName.ValueString())
+
+		// If the entire plan is null, the resource is planned for destruction.
+		if req.Plan.Raw.IsNull() {
+			r.client.PlanRoleGrants(state.ClusterName.ValueStringPointer(), "revoke members of "+state.RoleName.ValueString(), dbops.RoleGrantPlan{ReplacedRoles: replaced}, nil)
+		}
+	}
+
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// Members generated from values known after apply only can't be checked yet.
+	var members types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("member"), &members)...)
+	if resp.Diagnostics.HasError() || members.IsUnknown() {
+		return
+	}
+
+	var plan RoleMembers
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.RoleName.IsUnknown() {
+		return
+	}
+
+	// The role's members replace its current grantees, and must not make the hierarchy circular.
+	edges := make([]rolegraph.Edge, 0, len(plan.Members))
+	for _, member := range plan.Members {
+		if member.RoleName.IsNull() || member.RoleName.IsUnknown() {
+			continue
+		}
+		edges = append(edges, rolegraph.Edge{
+			Role:    plan.RoleName.ValueString(),
+			Grantee: rolegraph.Node{Name: member.RoleName.ValueString(), Kind: rolegraph.KindRole},
+		})
+	}
+	tfutils.RejectRoleCycle(ctx, &resp.Diagnostics, r.client, plan.ClusterName.ValueStringPointer(), "members of "+plan.RoleName.ValueString(), dbops.RoleGrantPlan{
+		ReplacedRoles: append(replaced, plan.RoleName.ValueString()),
+		Added:         edges,
+	})
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RoleMembers
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	role, err := r.client.FindRoleByName(ctx, plan.RoleName.ValueString(), plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Validating Role",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+	if role == nil {
+		resp.Diagnostics.AddError(
+			"Role Does Not Exist",
+			fmt.Sprintf("Role '%s' does not exist. Please create the role before managing its members.", plan.RoleName.ValueString()),
+		)
+		return
+	}
+
+	// Saved even on failure, with the members actually granted, so that the resource is tainted
+	// instead of leaving them behind.
+	if r.reconcile(ctx, &plan, &resp.Diagnostics) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	}
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RoleMembers
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	role, err := r.client.FindRoleByName(ctx, state.RoleName.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Role Members",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+	if role == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	members, err := r.client.GetRoleMembers(ctx, state.RoleName.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Role Members",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	state.Members = toMembers(members)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan RoleMembers
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.reconcile(ctx, &plan, &resp.Diagnostics) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	}
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state RoleMembers
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, member := range state.toGrants() {
+		err := r.client.RevokeGrantRole(ctx, member.RoleName, member.GranteeUserName, member.GranteeRoleName, state.ClusterName.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Revoking ClickHouse Role Member",
+				tfutils.ErrorDetail(err),
+			)
+			return
+		}
+	}
+}
+
+func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// req.ID can either be in the form <cluster name>:<role name> or just <role name>.
+	roleName := req.ID
+	if clusterName, name, ok := strings.Cut(req.ID, ":"); ok {
+		roleName = name
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), clusterName)...)
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role_name"), roleName)...)
+}
+
+// reconcile grants, updates and revokes members of the role until they match plan. See
+// tfutils.Reconcile.
+func (r *Resource) reconcile(ctx context.Context, plan *RoleMembers, diags *diag.Diagnostics) bool {
+	clusterName := plan.ClusterName.ValueStringPointer()
+
+	return tfutils.Reconcile(diags, "ClickHouse Role Members", func() error {
+		return r.applyMembers(ctx, *plan, clusterName)
+	}, func() error {
+		members, err := r.client.GetRoleMembers(ctx, plan.RoleName.ValueString(), clusterName)
+		if err != nil {
+			return err
+		}
+		plan.Members = toMembers(members)
+		return nil
+	})
+}
+
+func (r *Resource) applyMembers(ctx context.Context, plan RoleMembers, clusterName *string) error {
+	current, err := r.client.GetRoleMembers(ctx, plan.RoleName.ValueString(), clusterName)
+	if err != nil {
+		return err
+	}
+
+	changes := diffMembers(current, plan.toGrants())
+
+	// Grant before revoking, so that replacing a member with another never leaves the role unused.
+	for _, member := range changes.grant {
+		if _, err := r.client.GrantRole(ctx, member, clusterName); err != nil {
+			return err
+		}
+	}
+
+	for _, member := range changes.update {
+		if _, err := r.client.UpdateGrantRole(ctx, member, clusterName); err != nil {
+			return err
+		}
+	}
+
+	for _, member := range changes.revoke {
+		if err := r.client.RevokeGrantRole(ctx, member.RoleName, member.GranteeUserName, member.GranteeRoleName, clusterName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
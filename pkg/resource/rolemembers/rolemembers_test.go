@@ -0,0 +1,124 @@
+package rolemembers_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/resourcebuilder"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/runner"
+)
+
+const (
+	resourceType = "clickhousedbops_role_members"
+	resourceName = "foo"
+
+	roleName       = "role1"
+	strayRoleName  = "stray"
+	memberRoleName = "member"
+	memberUserName = "user1"
+)
+
+func TestRoleMembers_acceptance(t *testing.T) {
+	memberRoleResource := resourcebuilder.
+		New("clickhousedbops_role", memberRoleName).
+		WithStringAttribute("name", memberRoleName)
+	memberUserResource := resourcebuilder.
+		New("clickhousedbops_user", memberUserName).
+		WithStringAttribute("name", memberUserName).
+		WithFunction("password_sha256_hash_wo", "sha256", "test").
+		WithIntAttribute("password_sha256_hash_wo_version", 1)
+
+	// role1 is granted to a role outside of terraform, that the resource must revoke.
+	setupFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string) error {
+		for _, name := range []string{roleName, strayRoleName} {
+			if _, err := dbopsClient.CreateRole(ctx, dbops.Role{Name: name}, clusterName); err != nil {
+				return fmt.Errorf("pre-creating role %q: %w", name, err)
+			}
+		}
+		if _, err := dbopsClient.GrantRole(ctx, dbops.GrantRole{RoleName: roleName, GranteeRoleName: new(strayRoleName)}, clusterName); err != nil {
+			return fmt.Errorf("pre-granting role %q: %w", roleName, err)
+		}
+		return nil
+	}
+
+	checkNotExistsFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]string) (bool, error) {
+		role := attrs["role_name"]
+		if role == "" {
+			return false, fmt.Errorf("role_name attribute was not set")
+		}
+
+		members, err := dbopsClient.GetRoleMembers(ctx, role, clusterName)
+		return len(members) > 0, err
+	}
+
+	checkAttributesFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]interface{}) error {
+		role, _ := attrs["role_name"].(string)
+		if role != roleName {
+			return fmt.Errorf("expected role_name to be %q, was %q", roleName, role)
+		}
+
+		configured, _ := attrs["member"].([]interface{})
+
+		members, err := dbopsClient.GetRoleMembers(ctx, role, clusterName)
+		if err != nil {
+			return err
+		}
+		if len(members) != len(configured) {
+			return fmt.Errorf("expected %d members, found %d", len(configured), len(members))
+		}
+
+		for _, m := range members {
+			if m.GranteeRoleName != nil && *m.GranteeRoleName == strayRoleName {
+				return fmt.Errorf("unmanaged member %q was not revoked", strayRoleName)
+			}
+		}
+
+		return nil
+	}
+
+	tests := []runner.TestCase{
+		{
+			Name:      "Manage role members using Native protocol on a single replica",
+			ChEnv:     map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol:  "native",
+			SetupFunc: setupFunc,
+			Resource: resourcebuilder.New(resourceType, resourceName).
+				WithStringAttribute("role_name", roleName).
+				WithBlock("member", func(b *resourcebuilder.BlockBuilder) {
+					b.WithResourceFieldReference("user_name", "clickhousedbops_user", memberUserName, "name")
+				}).
+				WithBlock("member", func(b *resourcebuilder.BlockBuilder) {
+					b.WithResourceFieldReference("role_name", "clickhousedbops_role", memberRoleName, "name").
+						WithBoolAttribute("admin_option", true)
+				}).
+				AddDependency(memberUserResource.Build()).
+				AddDependency(memberRoleResource.Build()).
+				Build(),
+			ResourceName:        resourceName,
+			ResourceAddress:     fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:  checkNotExistsFunc,
+			CheckAttributesFunc: checkAttributesFunc,
+		},
+		{
+			Name:      "Manage role members using HTTP protocol on a single replica",
+			ChEnv:     map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol:  "http",
+			SetupFunc: setupFunc,
+			Resource: resourcebuilder.New(resourceType, resourceName).
+				WithStringAttribute("role_name", roleName).
+				WithBlock("member", func(b *resourcebuilder.BlockBuilder) {
+					b.WithResourceFieldReference("user_name", "clickhousedbops_user", memberUserName, "name")
+				}).
+				AddDependency(memberUserResource.Build()).
+				Build(),
+			ResourceName:        resourceName,
+			ResourceAddress:     fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:  checkNotExistsFunc,
+			CheckAttributesFunc: checkAttributesFunc,
+		},
+	}
+
+	runner.RunTests(t, tests)
+}
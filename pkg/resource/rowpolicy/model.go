@@ -21,6 +21,7 @@ type RowPolicy struct {
 	IsRestrictive    types.Bool   `tfsdk:"is_restrictive"`
 	GranteeNames     types.Set    `tfsdk:"grantee_names"`
 	GranteeAllExcept types.Set    `tfsdk:"grantee_all_except"`
+	QuerySettings    types.Map    `tfsdk:"query_settings"`
 }
 
 func (m *RowPolicy) toDBOps(ctx context.Context) (dbops.RowPolicy, diag.Diagnostics) {
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -20,6 +21,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
 )
 
 //go:embed rowpolicy.md
@@ -49,7 +51,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\n",
+				Description: "Name of the cluster to create the resource into. If omitted, the provider `cluster_name` applies when set, otherwise the resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\n",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -114,6 +116,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 				Optional:    true,
 				Description: "Apply the row policy to all users and roles, excluding those listed. An empty set applies to everyone with no exclusions.",
 			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
 		},
 		MarkdownDescription: rowPolicyDescription,
 	}
@@ -176,46 +179,35 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
-	rp, diags := plan.toDBOps(ctx)
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	created, err := r.client.CreateRowPolicy(ctx, rp, plan.ClusterName.ValueStringPointer())
-	if err != nil {
-		if strings.Contains(err.Error(), "already exists") {
-			resp.Diagnostics.AddError(
-				"ClickHouse Row Policy already exists",
-				fmt.Sprintf("A row policy %q already exists on %s.%s. Import it with `terraform import <resource> %s.%s.%s` instead of recreating it.", rp.Name, rp.Database, rp.Table, rp.Database, rp.Table, rp.Name),
-			)
-			return
-		}
-
-		resp.Diagnostics.AddError(
-			"Error Creating ClickHouse Row Policy",
-			"Could not create row policy, unexpected error: "+err.Error(),
-		)
+	rp, diags := plan.toDBOps(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if created == nil {
+	created, err := r.client.CreateRowPolicy(ctx, rp, plan.ClusterName.ValueStringPointer())
+	if err != nil && created == nil && strings.Contains(err.Error(), "already exists") {
 		resp.Diagnostics.AddError(
-			"Error Creating ClickHouse Row Policy",
-			"The row policy was created but could not be found in system.row_policies.",
+			"ClickHouse Row Policy already exists",
+			fmt.Sprintf("A row policy %q already exists on %s.%s. Import it with `terraform import <resource> %s.%s.%s` instead of recreating it.", rp.Name, rp.Database, rp.Table, rp.Database, rp.Table, rp.Name),
 		)
 		return
 	}
 
-	var state RowPolicy
-	resp.Diagnostics.Append(state.fromDBOps(created)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	state.ClusterName = plan.ClusterName
-	state.SelectFilter = plan.SelectFilter // store non-normalized version to avoid diff
-	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+	tfutils.SaveCreated(ctx, resp, "Error Creating ClickHouse Row Policy", created, err, func(created *dbops.RowPolicy) (RowPolicy, diag.Diagnostics) {
+		var state RowPolicy
+		diags := state.fromDBOps(created)
+		state.ClusterName = plan.ClusterName
+		state.SelectFilter = plan.SelectFilter // store non-normalized version to avoid diff
+		state.QuerySettings = plan.QuerySettings
+		return state, diags
+	})
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -226,11 +218,17 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	result, err := r.client.GetRowPolicyByID(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse Row Policy",
-			"Could not read row policy, unexpected error: "+err.Error(),
+			tfutils.WithErrorHint("Could not read row policy, unexpected error: "+err.Error(), err),
 		)
 		return
 	}
@@ -272,6 +270,12 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	rp, diags := plan.toDBOps(ctx)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -282,7 +286,7 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating ClickHouse Row Policy",
-			"Could not update row policy, unexpected error: "+err.Error(),
+			tfutils.WithErrorHint("Could not update row policy, unexpected error: "+err.Error(), err),
 		)
 		return
 	}
@@ -293,6 +297,7 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 			return
 		}
 		state.SelectFilter = plan.SelectFilter
+		state.QuerySettings = plan.QuerySettings
 
 		diags = resp.State.Set(ctx, &state)
 		resp.Diagnostics.Append(diags...)
@@ -312,11 +317,17 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.DeleteRowPolicy(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse Row Policy",
-			"Could not delete row policy, unexpected error: "+err.Error(),
+			tfutils.WithErrorHint("Could not delete row policy, unexpected error: "+err.Error(), err),
 		)
 		return
 	}
@@ -342,7 +353,7 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse Row Policy",
-			"Could not read row policy, unexpected error: "+err.Error(),
+			tfutils.WithErrorHint("Could not read row policy, unexpected error: "+err.Error(), err),
 		)
 		return
 	}
@@ -352,7 +363,7 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 	}
 
 	// On import there is no configured filter to reconcile against, so adopt the stored value.
-	var state RowPolicy
+	state := RowPolicy{QuerySettings: types.MapNull(types.StringType)}
 	resp.Diagnostics.Append(state.fromDBOps(result)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
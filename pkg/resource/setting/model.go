@@ -14,4 +14,5 @@ type Setting struct {
 	Max               types.String   `tfsdk:"max"`
 	Writability       types.String   `tfsdk:"writability"`
 	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+	QuerySettings     types.Map      `tfsdk:"query_settings"`
 }
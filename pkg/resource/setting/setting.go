@@ -17,6 +17,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
 )
 
 //go:embed setting.md
@@ -45,7 +46,7 @@ func (r *Resource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *r
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				Description: "Name of the cluster to create the resource into. If omitted, the provider `cluster_name` applies when set, otherwise the resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -121,6 +122,7 @@ func (r *Resource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *r
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 			}),
+			"query_settings": tfutils.QuerySettingsAttribute(),
 		},
 		MarkdownDescription: settingResourceDescription,
 	}
@@ -179,6 +181,12 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	setting := dbops.Setting{
 		Name:        plan.Name.ValueString(),
 		Value:       plan.Value.ValueStringPointer(),
@@ -198,7 +206,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating ClickHouse Setting",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -215,6 +223,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		ClusterName:       plan.ClusterName,
 		SettingsProfileID: plan.SettingsProfileID,
 		Timeouts:          plan.Timeouts,
+		QuerySettings:     plan.QuerySettings,
 	}
 
 	modelFromApiResponse(&state, *createdSetting)
@@ -234,11 +243,17 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	settingsProfile, err := r.client.GetSetting(ctx, state.SettingsProfileID.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse Setting",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -254,7 +269,24 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	panic("Update of settingsprofilesetting resource is not supported")
+	// All other attributes require replacement, so only timeouts and query_settings can change here.
+	var plan, state Setting
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Timeouts = plan.Timeouts
+	state.QuerySettings = plan.QuerySettings
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -265,11 +297,17 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.DeleteSetting(ctx, state.SettingsProfileID.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse Setting",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -5,8 +5,9 @@ import (
 )
 
 type SettingsProfile struct {
-	ClusterName types.String `tfsdk:"cluster_name"`
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	InheritFrom types.List   `tfsdk:"inherit_from"`
+	ClusterName   types.String `tfsdk:"cluster_name"`
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	InheritFrom   types.List   `tfsdk:"inherit_from"`
+	QuerySettings types.Map    `tfsdk:"query_settings"`
 }
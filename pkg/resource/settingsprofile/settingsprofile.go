@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -18,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
 )
 
 //go:embed settingsprofile.md
@@ -47,7 +49,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				Description: "Name of the cluster to create the resource into. If omitted, the provider `cluster_name` applies when set, otherwise the resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -71,6 +73,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					listvalidator.SizeAtLeast(1),
 				},
 			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
 		},
 		MarkdownDescription: settingsProfileResourceDescription,
 	}
@@ -82,6 +85,8 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 		return
 	}
 
+	tfutils.ReplaceDriftedReplicas(ctx, req, resp, "settings profile", "id")
+
 	if r.client != nil {
 		var config SettingsProfile
 		diags := req.Config.Get(ctx, &config)
@@ -129,6 +134,12 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	inherit := make([]string, 0)
 
 	resp.Diagnostics.Append(plan.InheritFrom.ElementsAs(ctx, &inherit, false)...)
@@ -142,30 +153,25 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	}
 
 	createdSettingsProfile, err := r.client.CreateSettingsProfile(ctx, profile, plan.ClusterName.ValueStringPointer())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating ClickHouse SettingsProfile",
-			fmt.Sprintf("%+v\n", err),
-		)
-		return
-	}
-
-	state := SettingsProfile{
-		ClusterName: plan.ClusterName,
-	}
-
-	modelFromApiResponse(&state, *createdSettingsProfile)
+	tfutils.SaveCreated(ctx, resp, "Error Creating ClickHouse SettingsProfile", createdSettingsProfile, err, func(createdSettingsProfile *dbops.SettingsProfile) (SettingsProfile, diag.Diagnostics) {
+		state := SettingsProfile{
+			ClusterName:   plan.ClusterName,
+			QuerySettings: plan.QuerySettings,
+		}
+		modelFromApiResponse(&state, *createdSettingsProfile)
+		return state, nil
+	})
+}
 
-	diags = resp.State.Set(ctx, state)
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SettingsProfile
+	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-}
 
-func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state SettingsProfile
-	diags := req.State.Get(ctx, &state)
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -175,12 +181,12 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse SettingsProfile",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
 
-	if settingsProfile != nil {
+	if settingsProfile != nil && !tfutils.ReplicaDrift(ctx, &resp.Diagnostics, resp.Private, settingsProfile.Divergence, "settings profile", settingsProfile.Name) {
 		modelFromApiResponse(&state, *settingsProfile)
 
 		diags = resp.State.Set(ctx, &state)
@@ -204,6 +210,21 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.QuerySettings = plan.QuerySettings
+
+	if plan.Name.Equal(state.Name) && plan.InheritFrom.Equal(state.InheritFrom) {
+		// Only query_settings changed, they are not stored in ClickHouse.
+		diags = resp.State.Set(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
 	inherit := make([]string, 0)
 	resp.Diagnostics.Append(plan.InheritFrom.ElementsAs(ctx, &inherit, false)...)
 	if resp.Diagnostics.HasError() {
@@ -220,7 +241,7 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating ClickHouse SettingsProfile",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -242,11 +263,17 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.DeleteSettingsProfile(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse SettingsProfile",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -272,7 +299,7 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Cannot find settings profile",
-				fmt.Sprintf("%+v\n", err),
+				tfutils.ErrorDetail(err),
 			)
 			return
 		}
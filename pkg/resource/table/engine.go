@@ -0,0 +1,41 @@
+package table
+
+import (
+	"slices"
+	"strings"
+)
+
+// engineName returns the name of an engine without its arguments, such as `ReplicatedMergeTree` for
+// `ReplicatedMergeTree('/path', '{replica}')`.
+func engineName(engine string) string {
+	name, _, _ := strings.Cut(engine, "(")
+	return strings.TrimSpace(name)
+}
+
+// isMergeTree reports whether the engine belongs to the MergeTree family, including its Replicated and
+// Shared variants. Only those support ORDER BY, PARTITION BY, PRIMARY KEY, SAMPLE BY and TTL.
+func isMergeTree(engine string) bool {
+	return strings.HasSuffix(engineName(engine), "MergeTree")
+}
+
+// alterableEngines are the engines, besides the MergeTree family, whose columns can be added,
+// modified and dropped with ALTER TABLE.
+var alterableEngines = []string{"Buffer", "Distributed", "Memory", "Merge", "Null"}
+
+// supportsAlterColumns reports whether the columns of a table with engine can be changed in place.
+// The other engines, such as Kafka or S3Queue, hold no data of their own and are recreated instead.
+func supportsAlterColumns(engine string) bool {
+	return isMergeTree(engine) || slices.Contains(alterableEngines, engineName(engine))
+}
+
+// sameEngine reports whether the engine returned by the server is the configured one. ClickHouse
+// Cloud creates MergeTree family tables, replicated or not, with their Shared variant.
+func sameEngine(configured string, server string) bool {
+	name := engineName(configured)
+	if name == server {
+		return true
+	}
+
+	shared, ok := strings.CutPrefix(server, "Shared")
+	return ok && isMergeTree(server) && strings.TrimPrefix(name, "Replicated") == shared
+}
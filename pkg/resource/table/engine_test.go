@@ -0,0 +1,56 @@
+package table
+
+import (
+	"testing"
+)
+
+func Test_engines(t *testing.T) {
+	tests := []struct {
+		engine        string
+		wantName      string
+		wantMergeTree bool
+		wantAlterable bool
+	}{
+		{engine: "MergeTree", wantName: "MergeTree", wantMergeTree: true, wantAlterable: true},
+		{engine: "MergeTree()", wantName: "MergeTree", wantMergeTree: true, wantAlterable: true},
+		{engine: "ReplicatedReplacingMergeTree('/clickhouse/tables/{shard}/t', '{replica}', ver)", wantName: "ReplicatedReplacingMergeTree", wantMergeTree: true, wantAlterable: true},
+		{engine: "Distributed(cluster, db, t, rand())", wantName: "Distributed", wantAlterable: true},
+		{engine: "Kafka", wantName: "Kafka"},
+		{engine: " S3Queue ('https://bucket/*.csv', 'CSV')", wantName: "S3Queue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.engine, func(t *testing.T) {
+			if got := engineName(tt.engine); got != tt.wantName {
+				t.Errorf("engineName() = %q, want %q", got, tt.wantName)
+			}
+			if got := isMergeTree(tt.engine); got != tt.wantMergeTree {
+				t.Errorf("isMergeTree() = %v, want %v", got, tt.wantMergeTree)
+			}
+			if got := supportsAlterColumns(tt.engine); got != tt.wantAlterable {
+				t.Errorf("supportsAlterColumns() = %v, want %v", got, tt.wantAlterable)
+			}
+		})
+	}
+}
+
+func Test_sameEngine(t *testing.T) {
+	tests := []struct {
+		configured string
+		server     string
+		want       bool
+	}{
+		{configured: "MergeTree()", server: "MergeTree", want: true},
+		{configured: "MergeTree", server: "SharedMergeTree", want: true},
+		{configured: "ReplicatedAggregatingMergeTree('/p', '{replica}')", server: "SharedAggregatingMergeTree", want: true},
+		{configured: "MergeTree", server: "ReplacingMergeTree", want: false},
+		{configured: "ReplacingMergeTree", server: "SharedMergeTree", want: false},
+		{configured: "Log", server: "TinyLog", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.configured+" "+tt.server, func(t *testing.T) {
+			if got := sameEngine(tt.configured, tt.server); got != tt.want {
+				t.Errorf("sameEngine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
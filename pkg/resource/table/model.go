@@ -0,0 +1,91 @@
+package table
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+type Table struct {
+	ClusterName   types.String `tfsdk:"cluster_name"`
+	DatabaseName  types.String `tfsdk:"database_name"`
+	Name          types.String `tfsdk:"name"`
+	Columns       []Column     `tfsdk:"column"`
+	Engine        types.String `tfsdk:"engine"`
+	OrderBy       types.String `tfsdk:"order_by"`
+	PartitionBy   types.String `tfsdk:"partition_by"`
+	PrimaryKey    types.String `tfsdk:"primary_key"`
+	SampleBy      types.String `tfsdk:"sample_by"`
+	TTL           types.String `tfsdk:"ttl"`
+	Settings      types.Map    `tfsdk:"settings"`
+	Comment       types.String `tfsdk:"comment"`
+	QuerySettings types.Map    `tfsdk:"query_settings"`
+}
+
+type Column struct {
+	Name        types.String `tfsdk:"name"`
+	Type        types.String `tfsdk:"type"`
+	DefaultKind types.String `tfsdk:"default_kind"`
+	Default     types.String `tfsdk:"default"`
+	Codec       types.String `tfsdk:"codec"`
+	Comment     types.String `tfsdk:"comment"`
+	TTL         types.String `tfsdk:"ttl"`
+}
+
+func (t Table) toTable(ctx context.Context) (dbops.Table, diag.Diagnostics) {
+	var settings map[string]string
+	if !t.Settings.IsNull() {
+		if diags := t.Settings.ElementsAs(ctx, &settings, false); diags.HasError() {
+			return dbops.Table{}, diags
+		}
+	}
+
+	columns := make([]dbops.TableColumn, 0, len(t.Columns))
+	for _, c := range t.Columns {
+		columns = append(columns, dbops.TableColumn{
+			Name:        c.Name.ValueString(),
+			Type:        c.Type.ValueString(),
+			DefaultKind: c.DefaultKind.ValueStringPointer(),
+			Default:     c.Default.ValueStringPointer(),
+			Codec:       c.Codec.ValueStringPointer(),
+			Comment:     c.Comment.ValueStringPointer(),
+			TTL:         c.TTL.ValueStringPointer(),
+		})
+	}
+
+	return dbops.Table{
+		Database:    t.DatabaseName.ValueString(),
+		Name:        t.Name.ValueString(),
+		Columns:     columns,
+		Engine:      t.Engine.ValueString(),
+		OrderBy:     t.OrderBy.ValueStringPointer(),
+		PartitionBy: t.PartitionBy.ValueStringPointer(),
+		PrimaryKey:  t.PrimaryKey.ValueStringPointer(),
+		SampleBy:    t.SampleBy.ValueStringPointer(),
+		TTL:         t.TTL.ValueStringPointer(),
+		Settings:    settings,
+		Comment:     t.Comment.ValueString(),
+	}, nil
+}
+
+// equal reports whether c and o define the same column. Unknown values are never equal.
+func (c Column) equal(o Column) bool {
+	for _, pair := range [][2]types.String{
+		{c.Name, o.Name},
+		{c.Type, o.Type},
+		{c.DefaultKind, o.DefaultKind},
+		{c.Default, o.Default},
+		{c.Codec, o.Codec},
+		{c.Comment, o.Comment},
+		{c.TTL, o.TTL},
+	} {
+		if pair[0].IsUnknown() || pair[1].IsUnknown() || !pair[0].Equal(pair[1]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,597 @@
+package table
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
+)
+
+//go:embed table.md
+var tableResourceDescription string
+
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table"
+}
+
+// replacedExpression is an optional table expression that can only be set when the table is created.
+func replacedExpression(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: description,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+	}
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the table into. If omitted, the provider `cluster_name` applies when set, otherwise the table will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database of the table",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the table",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"engine": schema.StringAttribute{
+				Required:    true,
+				Description: "Engine of the table with its arguments, such as `MergeTree`, `ReplicatedMergeTree('/clickhouse/tables/{shard}/db/events', '{replica}')`, `Distributed(cluster, db, events, rand())`, `Kafka` or `S3Queue('https://bucket.s3.amazonaws.com/data/*.csv', 'CSV')`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"order_by":     replacedExpression("Sorting key of a MergeTree family table, such as `(tenant_id, ts)`. Use `tuple()` for no sorting."),
+			"partition_by": replacedExpression("Partitioning key of a MergeTree family table, such as `toYYYYMM(ts)`."),
+			"primary_key":  replacedExpression("Primary key of a MergeTree family table, when it differs from the sorting key. It must be a prefix of `order_by`."),
+			"sample_by":    replacedExpression("Sampling expression of a MergeTree family table. It must be part of the primary key."),
+			"ttl": schema.StringAttribute{
+				Optional:    true,
+				Description: "TTL rules of a MergeTree family table, such as `ts + INTERVAL 1 MONTH DELETE`. Changing it alters the table in place.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"settings": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Settings of the table, such as `index_granularity`. Changing them alters the table in place, which ClickHouse refuses for settings that can only be set at creation.",
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comment associated with the table. Changing it alters the table in place.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"column": schema.ListNestedBlock{
+				Description: "A column of the table, in order. Columns added, changed, moved or removed are altered in place on engines that support it.",
+				Validators: []validator.List{
+					listvalidator.IsRequired(),
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the column. Renaming a column drops it and adds a new, empty one.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"type": schema.StringAttribute{
+							Required:    true,
+							Description: "Data type of the column, such as `UInt64`, `LowCardinality(String)` or `Nullable(DateTime64(3))`.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"default_kind": schema.StringAttribute{
+							Optional:    true,
+							Description: "How `default` is used: `DEFAULT`, `MATERIALIZED`, `ALIAS` or `EPHEMERAL`. Defaults to `DEFAULT` when `default` is set.",
+							Validators: []validator.String{
+								stringvalidator.OneOf("DEFAULT", "MATERIALIZED", "ALIAS", "EPHEMERAL"),
+							},
+						},
+						"default": schema.StringAttribute{
+							Optional:    true,
+							Description: "Default expression of the column, such as `now()`.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"codec": schema.StringAttribute{
+							Optional:    true,
+							Description: "Compression codecs of the column, without the `CODEC(...)` wrapper, such as `Delta, ZSTD(3)`.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"comment": schema.StringAttribute{
+							Optional:    true,
+							Description: "Comment associated with the column.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"ttl": schema.StringAttribute{
+							Optional:    true,
+							Description: "TTL of the values of the column, such as `ts + INTERVAL 1 DAY`.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+					},
+				},
+			},
+		},
+		MarkdownDescription: tableResourceDescription,
+	}
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config Table
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for idx, c := range config.Columns {
+		if !c.Name.IsUnknown() && !c.Name.IsNull() {
+			if seen[c.Name.ValueString()] {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("column").AtListIndex(idx).AtName("name"),
+					"Duplicate Column",
+					fmt.Sprintf("Column %q is defined more than once.", c.Name.ValueString()),
+				)
+			}
+			seen[c.Name.ValueString()] = true
+		}
+
+		kind := c.DefaultKind.ValueString()
+		if (kind == "MATERIALIZED" || kind == "ALIAS") && c.Default.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("column").AtListIndex(idx).AtName("default"),
+				"Missing Default Expression",
+				fmt.Sprintf("'default' is required when 'default_kind' is %q.", kind),
+			)
+		}
+	}
+
+	if config.Engine.IsUnknown() {
+		return
+	}
+
+	if isMergeTree(config.Engine.ValueString()) {
+		if config.OrderBy.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("order_by"),
+				"Missing Sorting Key",
+				"'order_by' is required for MergeTree family engines. Use `tuple()` for no sorting.",
+			)
+		}
+		return
+	}
+
+	for attr, value := range map[string]types.String{
+		"order_by":     config.OrderBy,
+		"partition_by": config.PartitionBy,
+		"primary_key":  config.PrimaryKey,
+		"sample_by":    config.SampleBy,
+		"ttl":          config.TTL,
+	} {
+		if !value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr),
+				"Invalid Table Definition",
+				fmt.Sprintf("%q can only be set for MergeTree family engines, not %q.", attr, engineName(config.Engine.ValueString())),
+			)
+		}
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state Table
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	columnsChanged := len(plan.Columns) != len(state.Columns)
+	for idx := 0; !columnsChanged && idx < len(plan.Columns); idx++ {
+		columnsChanged = !plan.Columns[idx].equal(state.Columns[idx])
+	}
+	if columnsChanged && !plan.Engine.IsUnknown() && !supportsAlterColumns(plan.Engine.ValueString()) {
+		resp.RequiresReplace.Append(path.Root("column"))
+	}
+
+	table := fmt.Sprintf("%s.%s", state.DatabaseName.ValueString(), state.Name.ValueString())
+
+	if len(resp.RequiresReplace) > 0 {
+		resp.Diagnostics.AddWarning(
+			"Table Will Be Recreated",
+			fmt.Sprintf("The change to %s cannot be applied in place: the table %s is dropped and created again, and the data stored in it is lost.", describePaths(resp.RequiresReplace), table),
+		)
+		return
+	}
+
+	planned := make(map[string]bool)
+	for _, c := range plan.Columns {
+		if c.Name.IsUnknown() {
+			// The name of some column is known after apply only: any column could be dropped.
+			return
+		}
+		planned[c.Name.ValueString()] = true
+	}
+	dropped := make([]string, 0)
+	for _, c := range state.Columns {
+		if !planned[c.Name.ValueString()] {
+			dropped = append(dropped, c.Name.ValueString())
+		}
+	}
+	if len(dropped) > 0 {
+		resp.Diagnostics.AddWarning(
+			"Columns Will Be Dropped",
+			fmt.Sprintf("The columns %s of the table %s are dropped, with their data. Renaming a column drops it and adds a new, empty one.", strings.Join(dropped, ", "), table),
+		)
+	}
+}
+
+// describePaths lists the attributes of paths, without their nested steps.
+func describePaths(paths path.Paths) string {
+	attrs := make([]string, 0, len(paths))
+	for _, p := range paths {
+		attr := p.String()
+		if steps := p.Steps(); len(steps) > 0 {
+			attr = steps[0].String()
+		}
+		attrs = append(attrs, fmt.Sprintf("%q", attr))
+	}
+
+	return strings.Join(attrs, ", ")
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan Table
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	table, diags := plan.toTable(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := r.client.CreateTable(ctx, table, plan.ClusterName.ValueStringPointer())
+	tfutils.SaveCreated(ctx, resp, "Error Creating ClickHouse Table", created, err, func(*dbops.Table) (Table, diag.Diagnostics) {
+		return plan, nil
+	})
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state Table
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	table, err := r.client.GetTable(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse Table",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	if table == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Only the engine name is read back: keep the configured arguments unless the engine changed.
+	if !sameEngine(state.Engine.ValueString(), table.Engine) {
+		state.Engine = types.StringValue(table.Engine)
+	}
+
+	state.Comment = types.StringNull()
+	if table.Comment != "" {
+		state.Comment = types.StringValue(table.Comment)
+	}
+
+	state.OrderBy = r.syncExpression(ctx, &resp.Diagnostics, state.OrderBy, table.OrderBy)
+	state.PartitionBy = r.syncExpression(ctx, &resp.Diagnostics, state.PartitionBy, table.PartitionBy)
+	state.PrimaryKey = r.syncExpression(ctx, &resp.Diagnostics, state.PrimaryKey, table.PrimaryKey)
+	state.SampleBy = r.syncExpression(ctx, &resp.Diagnostics, state.SampleBy, table.SampleBy)
+	state.TTL = r.syncExpression(ctx, &resp.Diagnostics, state.TTL, table.TTL)
+
+	state.Settings, diags = syncSettings(ctx, state.Settings, table.Settings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Columns = r.syncColumns(ctx, &resp.Diagnostics, state.Columns, table.Columns)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state Table
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, diags := state.toTable(ctx)
+	resp.Diagnostics.Append(diags...)
+	desired, diags := plan.toTable(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updated, err := r.client.UpdateTable(ctx, current, desired, plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Table",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	if updated == nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse Table",
+			"failed retrieving table after update",
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state Table
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteTable(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse Table",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+}
+
+// syncColumns returns the columns read from the server, keeping the form of the columns in state
+// when the server only formats them differently.
+func (r *Resource) syncColumns(ctx context.Context, diags *diag.Diagnostics, state []Column, server []dbops.TableColumn) []Column {
+	known := make(map[string]Column)
+	for _, c := range state {
+		known[c.Name.ValueString()] = c
+	}
+
+	columns := make([]Column, 0, len(server))
+	for _, s := range server {
+		base, found := known[s.Name]
+
+		c := Column{
+			Name:        types.StringValue(s.Name),
+			Type:        types.StringValue(s.Type),
+			DefaultKind: types.StringPointerValue(s.DefaultKind),
+			Default:     types.StringPointerValue(s.Default),
+			Codec:       types.StringPointerValue(s.Codec),
+			Comment:     types.StringPointerValue(s.Comment),
+			// Column TTLs are not read back.
+			TTL: types.StringNull(),
+		}
+
+		if found {
+			c.TTL = base.TTL
+			if compact(base.Type.ValueString()) == compact(s.Type) {
+				c.Type = base.Type
+			}
+			if base.DefaultKind.IsNull() && s.DefaultKind != nil && *s.DefaultKind == "DEFAULT" {
+				c.DefaultKind = base.DefaultKind
+			}
+			if s.Default != nil && !base.Default.IsNull() && r.normalizedEquals(ctx, diags, base.Default.ValueString(), *s.Default) {
+				c.Default = base.Default
+			}
+			if s.Codec != nil && strings.EqualFold(compact(base.Codec.ValueString()), compact(*s.Codec)) {
+				c.Codec = base.Codec
+			}
+		}
+
+		columns = append(columns, c)
+	}
+
+	return columns
+}
+
+// syncExpression returns the expression read from the server, keeping the form in state when the
+// server only formats it differently.
+func (r *Resource) syncExpression(ctx context.Context, diags *diag.Diagnostics, state types.String, server *string) types.String {
+	if server == nil {
+		return types.StringNull()
+	}
+	if !state.IsNull() && r.normalizedEquals(ctx, diags, state.ValueString(), *server) {
+		return state
+	}
+
+	return types.StringValue(*server)
+}
+
+// syncSettings returns the settings in state with the values read from the server. Settings only the
+// server has are left out, as ClickHouse adds defaults such as index_granularity.
+func syncSettings(ctx context.Context, state types.Map, server map[string]string) (types.Map, diag.Diagnostics) {
+	if state.IsNull() {
+		return state, nil
+	}
+
+	var settings map[string]string
+	if diags := state.ElementsAs(ctx, &settings, false); diags.HasError() {
+		return state, diags
+	}
+
+	synced := make(map[string]string)
+	for name := range settings {
+		if value, ok := server[name]; ok {
+			synced[name] = value
+		}
+	}
+
+	if len(synced) == 0 {
+		return types.MapNull(types.StringType), nil
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, synced)
+}
+
+// normalizedEquals reports whether an expression returned by the server is equal to state. When the
+// expression cannot be normalized, they are reported different with a warning.
+func (r *Resource) normalizedEquals(ctx context.Context, diags *diag.Diagnostics, state, server string) bool {
+	if server == state {
+		return true
+	}
+
+	normalized, err := r.client.NormalizeExpression(ctx, state)
+	if err != nil {
+		diags.AddWarning(
+			"Could not normalize expression",
+			fmt.Sprintf("Comparing %q with %q as written, which might show a difference where ClickHouse only formats the expression differently. Error: %+v", state, server, err),
+		)
+		return false
+	}
+	return server == normalized
+}
+
+// compact removes whitespace, which ClickHouse adds to type and codec arguments.
+func compact(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,115 @@
+package table_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/resourcebuilder"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/runner"
+)
+
+const (
+	resourceType = "clickhousedbops_table"
+	resourceName = "foo"
+)
+
+func TestTable_acceptance(t *testing.T) {
+	clusterName := "cluster1"
+
+	checkNotExistsFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]string) (bool, error) {
+		table, err := dbopsClient.GetTable(ctx, attrs["database_name"], attrs["name"], clusterName)
+		return table != nil, err
+	}
+
+	checkAttributesFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]interface{}) error {
+		table, err := dbopsClient.GetTable(ctx, attrs["database_name"].(string), attrs["name"].(string), clusterName)
+		if err != nil {
+			return err
+		}
+
+		if table == nil {
+			return fmt.Errorf("table %q was not found", attrs["name"])
+		}
+
+		columns := attrs["column"].([]interface{})
+		if len(columns) != len(table.Columns) {
+			return fmt.Errorf("expected %d columns, table has %d", len(columns), len(table.Columns))
+		}
+		for idx, c := range columns {
+			name := c.(map[string]interface{})["name"].(string)
+			if table.Columns[idx].Name != name {
+				return fmt.Errorf("expected column %d to be %q, was %q", idx, name, table.Columns[idx].Name)
+			}
+		}
+
+		return nil
+	}
+
+	newTable := func(clusterName *string, databaseName string, tableName string, columns ...string) *resourcebuilder.ResourceBuilder {
+		database := resourcebuilder.New("clickhousedbops_database", "db").
+			WithStringAttribute("name", databaseName)
+		table := resourcebuilder.New(resourceType, resourceName).
+			WithResourceFieldReference("database_name", "clickhousedbops_database", "db", "name").
+			WithStringAttribute("name", tableName).
+			WithStringAttribute("engine", "MergeTree").
+			WithStringAttribute("order_by", "(id, ts)").
+			WithStringAttribute("comment", "test")
+		if clusterName != nil {
+			database.WithStringAttribute("cluster_name", *clusterName)
+			table.WithStringAttribute("cluster_name", *clusterName)
+		}
+
+		table.WithBlock("column", func(b *resourcebuilder.BlockBuilder) {
+			b.WithStringAttribute("name", "id").WithStringAttribute("type", "UInt64")
+		}).WithBlock("column", func(b *resourcebuilder.BlockBuilder) {
+			b.WithStringAttribute("name", "ts").WithStringAttribute("type", "DateTime").WithStringAttribute("default", "now()")
+		})
+		for _, c := range columns {
+			table.WithBlock("column", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("name", c).WithStringAttribute("type", "String").WithStringAttribute("codec", "ZSTD(3)")
+			})
+		}
+
+		return table.AddDependency(database.Build())
+	}
+
+	tests := make([]runner.TestCase, 0)
+	for _, protocol := range []string{"native", "http"} {
+		databaseName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tableName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                  fmt.Sprintf("Create and alter table using %s protocol on a single replica", protocol),
+			ChEnv:                 map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol:              protocol,
+			Resource:              newTable(nil, databaseName, tableName).Build(),
+			UpdateResource:        new(newTable(nil, databaseName, tableName, "payload").Build()),
+			UpdateExpectNoReplace: true,
+			ResourceName:          resourceName,
+			ResourceAddress:       fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:    checkNotExistsFunc,
+			CheckAttributesFunc:   checkAttributesFunc,
+		})
+
+		databaseName = acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tableName = acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                  fmt.Sprintf("Create and alter table using %s protocol on a cluster using replicated storage", protocol),
+			ChEnv:                 map[string]string{"CONFIGFILE": "config-replicated.xml"},
+			ClusterName:           &clusterName,
+			Protocol:              protocol,
+			Resource:              newTable(&clusterName, databaseName, tableName).Build(),
+			UpdateResource:        new(newTable(&clusterName, databaseName, tableName, "payload").Build()),
+			UpdateExpectNoReplace: true,
+			ResourceName:          resourceName,
+			ResourceAddress:       fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:    checkNotExistsFunc,
+			CheckAttributesFunc:   checkAttributesFunc,
+		})
+	}
+
+	runner.RunTests(t, tests)
+}
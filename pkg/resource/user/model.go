@@ -1,6 +1,8 @@
 package user
 
 import (
+	"reflect"
+
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -13,6 +15,7 @@ type User struct {
 	PasswordSha256HashVersionWO types.Int32  `tfsdk:"password_sha256_hash_wo_version"`
 	HostIPs                     types.Set    `tfsdk:"host_ips"`
 	Auth                        *AuthModel   `tfsdk:"auth"`
+	QuerySettings               types.Map    `tfsdk:"query_settings"`
 }
 
 type AuthModel struct {
@@ -67,3 +70,12 @@ type LDAPModel struct {
 type KerberosModel struct {
 	Realm types.String `tfsdk:"realm"`
 }
+
+// userChanged reports whether plan changes the user itself. A change of query_settings alone only
+// applies to the queries of the provider: it must not alter the user again. The computed id is
+// unknown in the plan.
+func userChanged(plan User, state User) bool {
+	plan.ID = state.ID
+	plan.QuerySettings = state.QuerySettings
+	return !reflect.DeepEqual(plan, state)
+}
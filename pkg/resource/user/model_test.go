@@ -0,0 +1,65 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_userChanged(t *testing.T) {
+	base := User{
+		ClusterName:                 types.StringNull(),
+		ID:                          types.StringValue("5d1f4a2e-0000-0000-0000-000000000000"),
+		Name:                        types.StringValue("alice"),
+		PasswordSha256Hash:          types.StringNull(),
+		PasswordSha256HashWO:        types.StringNull(),
+		PasswordSha256HashVersionWO: types.Int32Null(),
+		HostIPs:                     types.SetNull(types.StringType),
+		Auth:                        &AuthModel{NoPassword: &NoPasswordModel{}},
+		QuerySettings:               types.MapNull(types.StringType),
+	}
+
+	tests := []struct {
+		name string
+		plan func(u User) User
+		want bool
+	}{
+		{
+			name: "No change",
+			plan: func(u User) User { return u },
+		},
+		{
+			name: "Query settings only",
+			plan: func(u User) User {
+				u.ID = types.StringUnknown()
+				u.QuerySettings = types.MapValueMust(types.StringType, map[string]attr.Value{"max_execution_time": types.StringValue("60")})
+				return u
+			},
+		},
+		{
+			name: "Renamed",
+			plan: func(u User) User {
+				u.Name = types.StringValue("bob")
+				return u
+			},
+			want: true,
+		},
+		{
+			name: "Write-only password version bumped",
+			plan: func(u User) User {
+				u.Auth = nil
+				u.PasswordSha256HashVersionWO = types.Int32Value(2)
+				return u
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userChanged(tt.plan(base), base); got != tt.want {
+				t.Errorf("userChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
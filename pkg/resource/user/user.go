@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -20,6 +21,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
 )
 
 //go:embed user.md
@@ -49,7 +51,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Optional:    true,
-				Description: "Name of the cluster to create the resource into. If omitted, resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
+				Description: "Name of the cluster to create the resource into. If omitted, the provider `cluster_name` applies when set, otherwise the resource will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nWhen using a self hosted ClickHouse instance, this field should only be set when there is more than one replica and you are not using 'replicated' storage for user_directory.\n",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -100,6 +102,7 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					setplanmodifier.RequiresReplace(),
 				},
 			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
 		},
 		Blocks: map[string]schema.Block{
 			"auth": userAuthBlock(),
@@ -118,6 +121,8 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 		return
 	}
 
+	tfutils.ReplaceDriftedReplicas(ctx, req, resp, "user", "id")
+
 	if r.client != nil {
 		var config User
 		diags := req.Config.Get(ctx, &config)
@@ -126,6 +131,15 @@ func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReques
 			return
 		}
 
+		if len(resolveAuthMethods(config, config)) > 1 {
+			tfutils.RequireCapability(ctx, &resp.Diagnostics, r.client, "more than one authentication method per user", func(f dbops.CapabilityFlags) bool {
+				return f.MultipleAuthMethods
+			})
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
 		// Only check replicated storage when cluster_name is set, to avoid
 		// unnecessary connections (e.g. during terraform plan -refresh=false).
 		if !config.ClusterName.IsNull() {
@@ -176,6 +190,12 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	user := dbops.User{
 		Name:        plan.Name.ValueString(),
 		AuthMethods: resolveAuthMethods(plan, config),
@@ -193,34 +213,29 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	}
 
 	createdUser, err := r.client.CreateUser(ctx, user, plan.ClusterName.ValueStringPointer())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating ClickHouse User",
-			fmt.Sprintf("%+v\n", err),
-		)
-		return
-	}
-
-	state := User{
-		ClusterName:                 plan.ClusterName,
-		ID:                          types.StringValue(createdUser.ID),
-		Name:                        types.StringValue(createdUser.Name),
-		PasswordSha256Hash:          plan.PasswordSha256Hash,
-		PasswordSha256HashVersionWO: plan.PasswordSha256HashVersionWO,
-		HostIPs:                     plan.HostIPs,
-		Auth:                        plan.Auth,
-	}
+	tfutils.SaveCreated(ctx, resp, "Error Creating ClickHouse User", createdUser, err, func(createdUser *dbops.User) (User, diag.Diagnostics) {
+		return User{
+			ClusterName:                 plan.ClusterName,
+			ID:                          types.StringValue(createdUser.ID),
+			Name:                        types.StringValue(createdUser.Name),
+			PasswordSha256Hash:          plan.PasswordSha256Hash,
+			PasswordSha256HashVersionWO: plan.PasswordSha256HashVersionWO,
+			HostIPs:                     plan.HostIPs,
+			Auth:                        plan.Auth,
+			QuerySettings:               plan.QuerySettings,
+		}, nil
+	})
+}
 
-	diags = resp.State.Set(ctx, state)
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state User
+	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-}
 
-func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state User
-	diags := req.State.Get(ctx, &state)
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -230,12 +245,12 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading ClickHouse User",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
 
-	if user != nil {
+	if user != nil && !tfutils.ReplicaDrift(ctx, &resp.Diagnostics, resp.Private, user.Divergence, "user", user.Name) {
 		state.Name = types.StringValue(user.Name)
 
 		diags = resp.State.Set(ctx, &state)
@@ -271,27 +286,38 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		return
 	}
 
-	updatedUser, err := r.client.UpdateUser(ctx, dbops.User{
-		ID:          state.ID.ValueString(),
-		Name:        plan.Name.ValueString(),
-		AuthMethods: resolveAuthMethods(plan, config),
-	}, plan.ClusterName.ValueStringPointer())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating ClickHouse User",
-			fmt.Sprintf("%+v\n", err),
-		)
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	name := state.Name
+	if userChanged(plan, state) {
+		updatedUser, err := r.client.UpdateUser(ctx, dbops.User{
+			ID:          state.ID.ValueString(),
+			Name:        plan.Name.ValueString(),
+			AuthMethods: resolveAuthMethods(plan, config),
+		}, plan.ClusterName.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating ClickHouse User",
+				tfutils.ErrorDetail(err),
+			)
+			return
+		}
+		name = types.StringValue(updatedUser.Name)
+	}
+
 	newState := User{
 		ClusterName:                 plan.ClusterName,
 		ID:                          state.ID,
-		Name:                        types.StringValue(updatedUser.Name),
+		Name:                        name,
 		PasswordSha256Hash:          plan.PasswordSha256Hash,
 		PasswordSha256HashVersionWO: plan.PasswordSha256HashVersionWO,
 		HostIPs:                     plan.HostIPs,
 		Auth:                        plan.Auth,
+		QuerySettings:               plan.QuerySettings,
 	}
 
 	diags = resp.State.Set(ctx, &newState)
@@ -306,11 +332,17 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		return
 	}
 
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.DeleteUser(ctx, state.ID.ValueString(), state.ClusterName.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting ClickHouse User",
-			fmt.Sprintf("%+v\n", err),
+			tfutils.ErrorDetail(err),
 		)
 		return
 	}
@@ -336,7 +368,7 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Cannot find user",
-				fmt.Sprintf("%+v\n", err),
+				tfutils.ErrorDetail(err),
 			)
 			return
 		}
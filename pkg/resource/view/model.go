@@ -0,0 +1,29 @@
+package view
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+)
+
+type View struct {
+	ClusterName   types.String `tfsdk:"cluster_name"`
+	DatabaseName  types.String `tfsdk:"database_name"`
+	Name          types.String `tfsdk:"name"`
+	Query         types.String `tfsdk:"query"`
+	Definer       types.String `tfsdk:"definer"`
+	SQLSecurity   types.String `tfsdk:"sql_security"`
+	Comment       types.String `tfsdk:"comment"`
+	QuerySettings types.Map    `tfsdk:"query_settings"`
+}
+
+func (v View) toView() dbops.View {
+	return dbops.View{
+		Database:    v.DatabaseName.ValueString(),
+		Name:        v.Name.ValueString(),
+		Query:       v.Query.ValueString(),
+		Definer:     v.Definer.ValueStringPointer(),
+		SQLSecurity: v.SQLSecurity.ValueStringPointer(),
+		Comment:     v.Comment.ValueString(),
+	}
+}
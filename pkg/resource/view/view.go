@@ -0,0 +1,287 @@
+package view
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/tfutils"
+)
+
+//go:embed view.md
+var viewResourceDescription string
+
+var (
+	_ resource.Resource                   = &Resource{}
+	_ resource.ResourceWithConfigure      = &Resource{}
+	_ resource.ResourceWithValidateConfig = &Resource{}
+	_ resource.ResourceWithModifyPlan     = &Resource{}
+)
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+type Resource struct {
+	client dbops.Client
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_view"
+}
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the cluster to create the view into. If omitted, the provider `cluster_name` applies when set, otherwise the view will be created on the replica hit by the query.\nThis field must be left null when using a ClickHouse Cloud cluster.\nShould be set when hitting a cluster with more than one replica.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the database of the view",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the view",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"query": schema.StringAttribute{
+				Required:    true,
+				Description: "SELECT query of the view.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"definer": schema.StringAttribute{
+				Optional:    true,
+				Description: "User whose privileges are used to run the query, or `CURRENT_USER`. Requires `sql_security` to be `DEFINER`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"sql_security": schema.StringAttribute{
+				Optional:    true,
+				Description: "Whose privileges are used to run the query: `DEFINER`, `INVOKER` or `NONE`. If omitted, the server default applies.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("DEFINER", "INVOKER", "NONE"),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comment associated with the view.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"query_settings": tfutils.QuerySettingsAttribute(),
+		},
+		MarkdownDescription: viewResourceDescription,
+	}
+}
+
+func (r *Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(dbops.Client)
+}
+
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config View
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Definer.IsNull() && !config.SQLSecurity.IsUnknown() && config.SQLSecurity.ValueString() != "DEFINER" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("sql_security"),
+			"Invalid SQL Security",
+			"'sql_security' must be \"DEFINER\" when 'definer' is set.",
+		)
+	}
+}
+
+// ModifyPlan keeps the query of the state when the planned one only differs in formatting.
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan, state View
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Query.IsUnknown() || plan.Query.Equal(state.Query) {
+		return
+	}
+
+	ctx, diags := tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if same, _ := tfutils.SameQuery(ctx, &resp.Diagnostics, r.client, plan.DatabaseName.ValueString(), plan.Query.ValueString(), state.Query.ValueString()); same {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("query"), state.Query)...)
+	}
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan View
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := r.client.CreateView(ctx, plan.toView(), plan.ClusterName.ValueStringPointer())
+	tfutils.SaveCreated(ctx, resp, "Error Creating ClickHouse View", created, err, func(*dbops.View) (View, diag.Diagnostics) {
+		return plan, nil
+	})
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state View
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	view, err := r.client.GetView(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading ClickHouse View",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	if view == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Keep the configured form of the query if it only differs from the server one in formatting, or
+	// if they cannot be compared.
+	if same, ok := tfutils.SameQuery(ctx, &resp.Diagnostics, r.client, state.DatabaseName.ValueString(), state.Query.ValueString(), view.Query); ok && !same {
+		state.Query = types.StringValue(view.Query)
+	}
+
+	// The server applies defaults when these are not configured: only track them when they are.
+	if !state.SQLSecurity.IsNull() {
+		state.SQLSecurity = types.StringPointerValue(view.SQLSecurity)
+	}
+	if !state.Definer.IsNull() && state.Definer.ValueString() != "CURRENT_USER" {
+		state.Definer = types.StringPointerValue(view.Definer)
+	}
+
+	state.Comment = types.StringNull()
+	if view.Comment != "" {
+		state.Comment = types.StringValue(view.Comment)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan View
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, plan.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updated, err := r.client.UpdateView(ctx, plan.toView(), plan.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse View",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+
+	if updated == nil {
+		resp.Diagnostics.AddError(
+			"Error Updating ClickHouse View",
+			"failed retrieving view after update",
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state View
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = tfutils.WithQuerySettings(ctx, state.QuerySettings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteView(ctx, state.DatabaseName.ValueString(), state.Name.ValueString(), state.ClusterName.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting ClickHouse View",
+			tfutils.ErrorDetail(err),
+		)
+		return
+	}
+}
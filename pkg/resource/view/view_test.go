@@ -0,0 +1,131 @@
+package view_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/dbops"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/nilcompare"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/resourcebuilder"
+	"github.com/ClickHouse/terraform-provider-clickhousedbops/internal/testutils/runner"
+)
+
+const (
+	resourceType = "clickhousedbops_view"
+	resourceName = "foo"
+)
+
+func TestView_acceptance(t *testing.T) {
+	clusterName := "cluster1"
+
+	checkNotExistsFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]string) (bool, error) {
+		view, err := dbopsClient.GetView(ctx, attrs["database_name"], attrs["name"], clusterName)
+		return view != nil, err
+	}
+
+	checkAttributesFunc := func(ctx context.Context, dbopsClient dbops.Client, clusterName *string, attrs map[string]interface{}) error {
+		view, err := dbopsClient.GetView(ctx, attrs["database_name"].(string), attrs["name"].(string), clusterName)
+		if err != nil {
+			return err
+		}
+
+		if view == nil {
+			return fmt.Errorf("view %q was not found", attrs["name"])
+		}
+
+		// The server formats the query: only check the part that changes between steps.
+		limit := strings.TrimSpace(attrs["query"].(string)[strings.LastIndex(attrs["query"].(string), "LIMIT"):])
+		if !strings.HasSuffix(view.Query, limit) {
+			return fmt.Errorf("expected query to end with %q, was %q", limit, view.Query)
+		}
+
+		if !nilcompare.NilCompare(view.SQLSecurity, attrs["sql_security"]) {
+			return fmt.Errorf("wrong value for sql_security attribute")
+		}
+
+		return nil
+	}
+
+	newView := func(clusterName *string, databaseName string, viewName string, query string) string {
+		database := resourcebuilder.New("clickhousedbops_database", "db").
+			WithStringAttribute("name", databaseName)
+		table := resourcebuilder.New("clickhousedbops_table", "events").
+			WithResourceFieldReference("database_name", "clickhousedbops_database", "db", "name").
+			WithStringAttribute("name", "events").
+			WithStringAttribute("engine", "MergeTree").
+			WithStringAttribute("order_by", "id").
+			WithBlock("column", func(b *resourcebuilder.BlockBuilder) {
+				b.WithStringAttribute("name", "id").WithStringAttribute("type", "UInt64")
+			})
+		view := resourcebuilder.New(resourceType, resourceName).
+			WithResourceFieldReference("database_name", "clickhousedbops_database", "db", "name").
+			WithStringAttribute("name", viewName).
+			WithStringAttribute("query", query).
+			WithStringAttribute("sql_security", "INVOKER").
+			WithStringAttribute("comment", "test").
+			WithDependsOn("clickhousedbops_table.events")
+		if clusterName != nil {
+			database.WithStringAttribute("cluster_name", *clusterName)
+			table.WithStringAttribute("cluster_name", *clusterName)
+			view.WithStringAttribute("cluster_name", *clusterName)
+		}
+
+		return view.AddDependency(table.Build()).AddDependency(database.Build()).Build()
+	}
+
+	tests := make([]runner.TestCase, 0)
+	for _, protocol := range []string{"native", "http"} {
+		databaseName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		viewName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                  fmt.Sprintf("Create and replace view using %s protocol on a single replica", protocol),
+			ChEnv:                 map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol:              protocol,
+			Resource:              newView(nil, databaseName, viewName, "select number from system.numbers LIMIT 10"),
+			UpdateResource:        new(newView(nil, databaseName, viewName, "SELECT number FROM system.numbers LIMIT 20")),
+			UpdateExpectNoReplace: true,
+			ResourceName:          resourceName,
+			ResourceAddress:       fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:    checkNotExistsFunc,
+			CheckAttributesFunc:   checkAttributesFunc,
+		})
+
+		databaseName = acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		viewName = acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                  fmt.Sprintf("Create and replace view using %s protocol on a cluster using replicated storage", protocol),
+			ChEnv:                 map[string]string{"CONFIGFILE": "config-replicated.xml"},
+			ClusterName:           &clusterName,
+			Protocol:              protocol,
+			Resource:              newView(&clusterName, databaseName, viewName, "SELECT number FROM system.numbers LIMIT 10"),
+			UpdateResource:        new(newView(&clusterName, databaseName, viewName, "SELECT number FROM system.numbers LIMIT 20")),
+			UpdateExpectNoReplace: true,
+			ResourceName:          resourceName,
+			ResourceAddress:       fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:    checkNotExistsFunc,
+			CheckAttributesFunc:   checkAttributesFunc,
+		})
+
+		// The server stores the query with the database of the table.
+		databaseName = acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		viewName = acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+		tests = append(tests, runner.TestCase{
+			Name:                  fmt.Sprintf("Create and replace view reading an unqualified table using %s protocol", protocol),
+			ChEnv:                 map[string]string{"CONFIGFILE": "config-single.xml"},
+			Protocol:              protocol,
+			Resource:              newView(nil, databaseName, viewName, "SELECT id FROM events LIMIT 10"),
+			UpdateResource:        new(newView(nil, databaseName, viewName, "SELECT id FROM events LIMIT 20")),
+			UpdateExpectNoReplace: true,
+			ResourceName:          resourceName,
+			ResourceAddress:       fmt.Sprintf("%s.%s", resourceType, resourceName),
+			CheckNotExistsFunc:    checkNotExistsFunc,
+			CheckAttributesFunc:   checkAttributesFunc,
+		})
+	}
+
+	runner.RunTests(t, tests)
+}